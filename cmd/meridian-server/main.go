@@ -21,6 +21,7 @@ import (
 	"github.com/tidwall/gjson"
 	"github.com/aiqia-dev/meridian/core"
 	"github.com/aiqia-dev/meridian/internal/hservice"
+	hservicev1 "github.com/aiqia-dev/meridian/internal/hservice/v1"
 	"github.com/aiqia-dev/meridian/internal/log"
 	"github.com/aiqia-dev/meridian/internal/server"
 
@@ -90,6 +91,31 @@ func (s *hserver) Send(ctx context.Context, in *hservice.MessageRequest) (*hserv
 	return &hservice.MessageReply{Ok: true}, nil
 }
 
+type hserverV1 struct {
+	hservicev1.UnimplementedHookServiceServer
+}
+
+func (s *hserverV1) Send(ctx context.Context, in *hservicev1.GeofenceEvent) (*hservicev1.Ack, error) {
+	log.HTTPf("grpc: %s", in.Json)
+	return &hservicev1.Ack{Seq: in.Seq, Ok: true}, nil
+}
+
+func (s *hserverV1) Stream(stream hservicev1.HookService_StreamServer) error {
+	for {
+		in, err := stream.Recv()
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		log.HTTPf("grpc: %s", in.Json)
+		if err := stream.Send(&hservicev1.Ack{Seq: in.Seq, Ok: true}); err != nil {
+			return err
+		}
+	}
+}
+
 func main() {
 	// Load .env file if it exists (silent fail if not found)
 	_ = godotenv.Load()
@@ -128,6 +154,13 @@ Advanced Options:
   --nohup                 : do not exit on SIGHUP
   --spinlock              : use a spinlock. For very write-heavy workloads
 
+TLS Options:
+  --tls-cert path                   : server certificate, enables TLS
+  --tls-key path                    : server private key
+  --tls-ca path                     : CA for client and leader certificates
+  --tls-client-auth no/optional/yes : require client certificates (default: no)
+  With TLS, SIGHUP reloads the certificates instead of exiting.
+
 Developer Options:
   --dev                             : enable developer mode
   --webhook-http-consumer-port port : Start a test HTTP webhook server
@@ -141,6 +174,10 @@ Environment Variables (can also be set in .env file):
   MERIDIAN_REQUIREPASS    : authentication password
   MERIDIAN_PROTECTED_MODE : yes/no
   MERIDIAN_APPENDONLY     : yes/no
+  MERIDIAN_TLS_CERT       : server certificate
+  MERIDIAN_TLS_KEY        : server private key
+  MERIDIAN_TLS_CA         : CA certificate
+  MERIDIAN_TLS_CLIENT_AUTH : no/optional/yes
 
 `,
 		)
@@ -179,6 +216,7 @@ Environment Variables (can also be set in .env file):
 		}
 		s := grpc.NewServer()
 		hservice.RegisterHookServiceServer(s, &hserver{})
+		hservicev1.RegisterHookServiceServer(s, &hserverV1{})
 		log.Infof("webhook server grpc://localhost:%d/", port)
 		if err := s.Serve(lis); err != nil {
 			log.Fatal(err)
@@ -336,6 +374,7 @@ Environment Variables (can also be set in .env file):
 	os.Args = nargs
 
 	metricsAddr := flag.String("metrics-addr", getEnv("MERIDIAN_METRICS_ADDR", ""), "The listening addr for Prometheus metrics.")
+	grpcAddr := flag.String("grpc-addr", getEnv("MERIDIAN_GRPC_ADDR", ""), "The listening addr for the gRPC command API.")
 
 	var (
 		dir            string
@@ -355,6 +394,10 @@ Environment Variables (can also be set in .env file):
 		adminUser      string
 		adminPassword  string
 		adminJWTSecret string
+		tlsCert        string
+		tlsKey         string
+		tlsCA          string
+		tlsClientAuth  string
 	)
 
 	flag.IntVar(&port, "p", getEnvInt("MERIDIAN_PORT", 9851), "The listening port")
@@ -374,6 +417,10 @@ Environment Variables (can also be set in .env file):
 	flag.StringVar(&adminUser, "admin-user", getEnv("MERIDIAN_ADMIN_USER", ""), "Admin panel username")
 	flag.StringVar(&adminPassword, "admin-password", getEnv("MERIDIAN_ADMIN_PASSWORD", ""), "Admin panel password")
 	flag.StringVar(&adminJWTSecret, "admin-jwt-secret", getEnv("MERIDIAN_ADMIN_JWT_SECRET", ""), "Admin panel JWT secret")
+	flag.StringVar(&tlsCert, "tls-cert", getEnv("MERIDIAN_TLS_CERT", ""), "TLS server certificate")
+	flag.StringVar(&tlsKey, "tls-key", getEnv("MERIDIAN_TLS_KEY", ""), "TLS server private key")
+	flag.StringVar(&tlsCA, "tls-ca", getEnv("MERIDIAN_TLS_CA", ""), "TLS CA certificate")
+	flag.StringVar(&tlsClientAuth, "tls-client-auth", getEnv("MERIDIAN_TLS_CLIENT_AUTH", "no"), "Require TLS client certificates: no, optional or yes")
 	flag.Parse()
 
 	if logEncoding == "json" {
@@ -489,10 +536,19 @@ Environment Variables (can also be set in .env file):
 
 	c := make(chan os.Signal, 1)
 	shutdown := make(chan bool, 1)
+	tlsReload := make(chan bool, 1)
 
 	signal.Notify(c, syscall.SIGHUP, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT)
 	go func() {
 		for s := range c {
+			if s == syscall.SIGHUP && tlsCert != "" {
+				// reload the certificates instead of exiting
+				select {
+				case tlsReload <- true:
+				default:
+				}
+				continue
+			}
 			if s == syscall.SIGHUP && nohup {
 				continue
 			}
@@ -554,6 +610,7 @@ Environment Variables (can also be set in .env file):
 		Dir:               dir,
 		UseHTTP:           httpTransport,
 		MetricsAddr:       *metricsAddr,
+		GRPCAddr:          *grpcAddr,
 		UnixSocketPath:    unixSocket,
 		DevMode:           devMode,
 		ShowDebugMessages: showDebugMessages,
@@ -569,6 +626,11 @@ Environment Variables (can also be set in .env file):
 		AdminUser:         adminUser,
 		AdminPassword:     adminPassword,
 		AdminJWTSecret:    adminJWTSecret,
+		TLSCert:           tlsCert,
+		TLSKey:            tlsKey,
+		TLSCA:             tlsCA,
+		TLSClientAuth:     tlsClientAuth,
+		TLSReload:         tlsReload,
 	}
 	if err := server.Serve(opts); err != nil {
 		log.Fatal(err)
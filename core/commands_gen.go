@@ -1604,6 +1604,95 @@ var commandsJSON = `{
         "optional": true,
         "multiple": false
       },
+      {
+        "command": "RETRY",
+        "name": ["attempts"],
+        "type": ["integer"],
+        "optional": true,
+        "multiple": false
+      },
+      {
+        "command": "BACKOFF",
+        "name": ["min", "max"],
+        "type": ["double", "double"],
+        "optional": true,
+        "multiple": false
+      },
+      {
+        "command": "MSGTTL",
+        "name": ["seconds"],
+        "type": ["double"],
+        "optional": true,
+        "multiple": false
+      },
+      {
+        "command": "DLQ",
+        "name": ["maxlen", "seconds"],
+        "type": ["integer", "double"],
+        "optional": true,
+        "multiple": false
+      },
+      {
+        "command": "BATCH",
+        "name": ["maxEvents", "maxDelay"],
+        "type": ["integer", "double"],
+        "optional": true,
+        "multiple": false
+      },
+      {
+        "command": "SECRET",
+        "name": ["secret"],
+        "type": ["string"],
+        "optional": true,
+        "multiple": true
+      },
+      {
+        "command": "STALE",
+        "name": ["seconds"],
+        "type": ["double"],
+        "optional": true,
+        "multiple": false
+      },
+      {
+        "name": "window",
+        "optional": true,
+        "enumargs": [
+          {
+            "name": "SCHEDULE",
+            "arguments": [
+              {
+                "name": "cron",
+                "type": "string"
+              },
+              {
+                "command": "DURATION",
+                "name": ["seconds"],
+                "type": ["double"]
+              }
+            ]
+          },
+          {
+            "name": "ACTIVE",
+            "arguments": [
+              {
+                "name": "from",
+                "type": "string"
+              },
+              {
+                "name": "to",
+                "type": "string"
+              }
+            ]
+          }
+        ]
+      },
+      {
+        "command": "TZ",
+        "name": ["zone"],
+        "type": ["string"],
+        "optional": true,
+        "multiple": false
+      },
       {
         "enum": ["NEARBY", "WITHIN", "INTERSECTS"]
       },
@@ -1666,6 +1755,95 @@ var commandsJSON = `{
     ],
     "group": "webhook"
   },
+  "HOOKSTATS": {
+    "summary": "Returns delivery statistics for all hooks matching a pattern",
+    "arguments": [
+      {
+        "name": "pattern",
+        "type": "pattern",
+        "optional": true
+      }
+    ],
+    "group": "webhook"
+  },
+  "HOOKDLQ": {
+    "summary": "Lists, replays or purges the dead-lettered messages of a hook",
+    "arguments": [
+      {
+        "name": "name",
+        "type": "string"
+      },
+      {
+        "name": "operation",
+        "optional": true,
+        "enumargs": [
+          {
+            "name": "REPLAY"
+          },
+          {
+            "name": "PURGE"
+          }
+        ]
+      }
+    ],
+    "group": "webhook"
+  },
+
+  "HOOKPAUSE": {
+    "summary": "Pauses the delivery of the hooks that match a pattern",
+    "arguments": [
+      {
+        "name": "pattern",
+        "type": "pattern"
+      },
+      {
+        "name": "mode",
+        "optional": true,
+        "enumargs": [
+          {
+            "name": "QUEUE",
+            "arguments": [
+              {
+                "name": "max",
+                "type": "integer"
+              }
+            ]
+          },
+          {
+            "name": "DROP"
+          }
+        ]
+      }
+    ],
+    "group": "webhook"
+  },
+
+  "HOOKRESUME": {
+    "summary": "Resumes the delivery of the hooks that match a pattern",
+    "arguments": [
+      {
+        "name": "pattern",
+        "type": "pattern"
+      }
+    ],
+    "group": "webhook"
+  },
+
+  "REPLAY": {
+    "summary": "Redelivers the logged events of a hook or channel starting at a sequence number",
+    "arguments": [
+      {
+        "name": "name",
+        "type": "string"
+      },
+      {
+        "command": "FROM",
+        "name": ["seq"],
+        "type": ["integer"]
+      }
+    ],
+    "group": "webhook"
+  },
 
   "SETCHAN": {
     "summary": "Creates a pubsub channel which points to geofenced search",
@@ -1688,6 +1866,53 @@ var commandsJSON = `{
         "optional": true,
         "multiple": false
       },
+      {
+        "command": "STALE",
+        "name": ["seconds"],
+        "type": ["double"],
+        "optional": true,
+        "multiple": false
+      },
+      {
+        "name": "window",
+        "optional": true,
+        "enumargs": [
+          {
+            "name": "SCHEDULE",
+            "arguments": [
+              {
+                "name": "cron",
+                "type": "string"
+              },
+              {
+                "command": "DURATION",
+                "name": ["seconds"],
+                "type": ["double"]
+              }
+            ]
+          },
+          {
+            "name": "ACTIVE",
+            "arguments": [
+              {
+                "name": "from",
+                "type": "string"
+              },
+              {
+                "name": "to",
+                "type": "string"
+              }
+            ]
+          }
+        ]
+      },
+      {
+        "command": "TZ",
+        "name": ["zone"],
+        "type": ["string"],
+        "optional": true,
+        "multiple": false
+      },
       {
         "enum": ["NEARBY", "WITHIN", "INTERSECTS"]
       },
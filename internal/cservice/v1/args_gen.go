@@ -0,0 +1,533 @@
+// Code generated by gen.go. DO NOT EDIT.
+
+package cservicev1
+
+import "strconv"
+
+func formatDouble(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func (x *SetRequest) appendArgs(args []string) []string {
+	args = append(args, "SET")
+	args = append(args, x.GetKey())
+	args = append(args, x.GetId())
+	for _, v := range x.GetField() {
+		args = append(args, "FIELD")
+		args = v.appendArgs(args)
+	}
+	if x != nil && x.Ex != nil {
+		args = append(args, "EX", formatDouble(*x.Ex))
+	}
+	switch x.GetType() {
+	case SetRequest_TYPE_NX:
+		args = append(args, "NX")
+	case SetRequest_TYPE_XX:
+		args = append(args, "XX")
+	}
+	switch v := x.GetValue().(type) {
+	case *SetRequest_Object:
+		args = append(args, "OBJECT", v.Object)
+	case *SetRequest_Point:
+		args = append(args, "POINT")
+		args = v.Point.appendArgs(args)
+	case *SetRequest_Bounds:
+		args = append(args, "BOUNDS")
+		args = v.Bounds.appendArgs(args)
+	case *SetRequest_Hash:
+		args = append(args, "HASH", v.Hash)
+	case *SetRequest_Text:
+		args = append(args, "STRING", v.Text)
+	}
+	return args
+}
+
+// Args returns the arguments of the SET command.
+func (x *SetRequest) Args() []string {
+	return x.appendArgs(nil)
+}
+
+func (x *Field) appendArgs(args []string) []string {
+	args = append(args, x.GetName())
+	args = append(args, formatDouble(x.GetValue()))
+	return args
+}
+
+func (x *Point) appendArgs(args []string) []string {
+	args = append(args, formatDouble(x.GetLat()))
+	args = append(args, formatDouble(x.GetLon()))
+	if x != nil && x.Z != nil {
+		args = append(args, formatDouble(*x.Z))
+	}
+	return args
+}
+
+func (x *Bounds) appendArgs(args []string) []string {
+	args = append(args, formatDouble(x.GetMinLat()))
+	args = append(args, formatDouble(x.GetMinLon()))
+	args = append(args, formatDouble(x.GetMaxLat()))
+	args = append(args, formatDouble(x.GetMaxLon()))
+	return args
+}
+
+func (x *GetRequest) appendArgs(args []string) []string {
+	args = append(args, "GET")
+	args = append(args, x.GetKey())
+	args = append(args, x.GetId())
+	if x.GetWithFields() {
+		args = append(args, "WITHFIELDS")
+	}
+	return args
+}
+
+// Args returns the arguments of the GET command.
+func (x *GetRequest) Args() []string {
+	return x.appendArgs(nil)
+}
+
+func (x *DelRequest) appendArgs(args []string) []string {
+	args = append(args, "DEL")
+	args = append(args, x.GetKey())
+	args = append(args, x.GetId())
+	if x.GetErrOn404() {
+		args = append(args, "ERRON404")
+	}
+	return args
+}
+
+// Args returns the arguments of the DEL command.
+func (x *DelRequest) Args() []string {
+	return x.appendArgs(nil)
+}
+
+func (x *ScanRequest) appendArgs(args []string) []string {
+	args = append(args, "SCAN")
+	args = append(args, x.GetKey())
+	if x != nil && x.Cursor != nil {
+		args = append(args, "CURSOR", strconv.FormatInt(*x.Cursor, 10))
+	}
+	if x != nil && x.Limit != nil {
+		args = append(args, "LIMIT", strconv.FormatInt(*x.Limit, 10))
+	}
+	if x != nil && x.Match != nil {
+		args = append(args, "MATCH", *x.Match)
+	}
+	switch x.GetOrder() {
+	case ScanRequest_ORDER_ASC:
+		args = append(args, "ASC")
+	case ScanRequest_ORDER_DESC:
+		args = append(args, "DESC")
+	}
+	for _, v := range x.GetWhere() {
+		args = append(args, "WHERE")
+		args = v.appendArgs(args)
+	}
+	for _, v := range x.GetWhereIn() {
+		args = append(args, "WHEREIN")
+		args = v.appendArgs(args)
+	}
+	for _, v := range x.GetWhereEval() {
+		args = append(args, "WHEREEVAL")
+		args = v.appendArgs(args)
+	}
+	for _, v := range x.GetWhereEvalSha() {
+		args = append(args, "WHEREEVALSHA")
+		args = v.appendArgs(args)
+	}
+	if x.GetNoFields() {
+		args = append(args, "NOFIELDS")
+	}
+	return args
+}
+
+// Args returns the arguments of the SCAN command.
+func (x *ScanRequest) Args() []string {
+	return x.appendArgs(nil)
+}
+
+func (x *Where) appendArgs(args []string) []string {
+	args = append(args, x.GetField())
+	args = append(args, formatDouble(x.GetMin()))
+	args = append(args, formatDouble(x.GetMax()))
+	return args
+}
+
+func (x *WhereIn) appendArgs(args []string) []string {
+	args = append(args, x.GetField())
+	args = append(args, strconv.Itoa(len(x.GetValue())))
+	for _, v := range x.GetValue() {
+		args = append(args, formatDouble(v))
+	}
+	return args
+}
+
+func (x *WhereEval) appendArgs(args []string) []string {
+	args = append(args, x.GetScript())
+	args = append(args, strconv.Itoa(len(x.GetArg())))
+	args = append(args, x.GetArg()...)
+	return args
+}
+
+func (x *WhereEvalSha) appendArgs(args []string) []string {
+	args = append(args, x.GetSha1())
+	args = append(args, strconv.Itoa(len(x.GetArg())))
+	args = append(args, x.GetArg()...)
+	return args
+}
+
+func (x *NearbyRequest) appendArgs(args []string) []string {
+	args = append(args, "NEARBY")
+	args = append(args, x.GetKey())
+	if x != nil && x.Cursor != nil {
+		args = append(args, "CURSOR", strconv.FormatInt(*x.Cursor, 10))
+	}
+	if x != nil && x.Limit != nil {
+		args = append(args, "LIMIT", strconv.FormatInt(*x.Limit, 10))
+	}
+	if x != nil && x.Match != nil {
+		args = append(args, "MATCH", *x.Match)
+	}
+	if x.GetDistance() {
+		args = append(args, "DISTANCE")
+	}
+	for _, v := range x.GetWhere() {
+		args = append(args, "WHERE")
+		args = v.appendArgs(args)
+	}
+	for _, v := range x.GetWhereIn() {
+		args = append(args, "WHEREIN")
+		args = v.appendArgs(args)
+	}
+	for _, v := range x.GetWhereEval() {
+		args = append(args, "WHEREEVAL")
+		args = v.appendArgs(args)
+	}
+	for _, v := range x.GetWhereEvalSha() {
+		args = append(args, "WHEREEVALSHA")
+		args = v.appendArgs(args)
+	}
+	if x.GetNoFields() {
+		args = append(args, "NOFIELDS")
+	}
+	if x.GetFence() {
+		args = append(args, "FENCE")
+	}
+	if x != nil && x.Detect != nil {
+		args = append(args, "DETECT", *x.Detect)
+	}
+	if x != nil && x.Commands != nil {
+		args = append(args, "COMMANDS", *x.Commands)
+	}
+	switch v := x.GetArea().(type) {
+	case *NearbyRequest_Point:
+		args = append(args, "POINT")
+		args = v.Point.appendArgs(args)
+	case *NearbyRequest_Roam:
+		args = append(args, "ROAM")
+		args = v.Roam.appendArgs(args)
+	}
+	return args
+}
+
+// Args returns the arguments of the NEARBY command.
+func (x *NearbyRequest) Args() []string {
+	return x.appendArgs(nil)
+}
+
+func (x *NearbyPoint) appendArgs(args []string) []string {
+	args = append(args, formatDouble(x.GetLat()))
+	args = append(args, formatDouble(x.GetLon()))
+	args = append(args, formatDouble(x.GetMeters()))
+	return args
+}
+
+func (x *Roam) appendArgs(args []string) []string {
+	args = append(args, x.GetKey())
+	args = append(args, x.GetPattern())
+	args = append(args, formatDouble(x.GetMeters()))
+	return args
+}
+
+func (x *WithinRequest) appendArgs(args []string) []string {
+	args = append(args, "WITHIN")
+	args = append(args, x.GetKey())
+	if x != nil && x.Cursor != nil {
+		args = append(args, "CURSOR", strconv.FormatInt(*x.Cursor, 10))
+	}
+	if x != nil && x.Limit != nil {
+		args = append(args, "LIMIT", strconv.FormatInt(*x.Limit, 10))
+	}
+	if x != nil && x.Match != nil {
+		args = append(args, "MATCH", *x.Match)
+	}
+	for _, v := range x.GetWhere() {
+		args = append(args, "WHERE")
+		args = v.appendArgs(args)
+	}
+	for _, v := range x.GetWhereIn() {
+		args = append(args, "WHEREIN")
+		args = v.appendArgs(args)
+	}
+	for _, v := range x.GetWhereEval() {
+		args = append(args, "WHEREEVAL")
+		args = v.appendArgs(args)
+	}
+	for _, v := range x.GetWhereEvalSha() {
+		args = append(args, "WHEREEVALSHA")
+		args = v.appendArgs(args)
+	}
+	if x.GetNoFields() {
+		args = append(args, "NOFIELDS")
+	}
+	if x.GetFence() {
+		args = append(args, "FENCE")
+	}
+	if x != nil && x.Detect != nil {
+		args = append(args, "DETECT", *x.Detect)
+	}
+	if x != nil && x.Commands != nil {
+		args = append(args, "COMMANDS", *x.Commands)
+	}
+	switch v := x.GetArea().(type) {
+	case *WithinRequest_Get:
+		args = append(args, "GET")
+		args = v.Get.appendArgs(args)
+	case *WithinRequest_Bounds:
+		args = append(args, "BOUNDS")
+		args = v.Bounds.appendArgs(args)
+	case *WithinRequest_Object:
+		args = append(args, "OBJECT", v.Object)
+	case *WithinRequest_Circle:
+		args = append(args, "CIRCLE")
+		args = v.Circle.appendArgs(args)
+	case *WithinRequest_Tile:
+		args = append(args, "TILE")
+		args = v.Tile.appendArgs(args)
+	case *WithinRequest_Quadkey:
+		args = append(args, "QUADKEY", v.Quadkey)
+	case *WithinRequest_Hash:
+		args = append(args, "HASH", v.Hash)
+	case *WithinRequest_Sector:
+		args = append(args, "SECTOR")
+		args = v.Sector.appendArgs(args)
+	}
+	return args
+}
+
+// Args returns the arguments of the WITHIN command.
+func (x *WithinRequest) Args() []string {
+	return x.appendArgs(nil)
+}
+
+func (x *Get) appendArgs(args []string) []string {
+	args = append(args, x.GetKey())
+	args = append(args, x.GetId())
+	return args
+}
+
+func (x *Circle) appendArgs(args []string) []string {
+	args = append(args, formatDouble(x.GetLat()))
+	args = append(args, formatDouble(x.GetLon()))
+	args = append(args, formatDouble(x.GetMeters()))
+	return args
+}
+
+func (x *Tile) appendArgs(args []string) []string {
+	args = append(args, formatDouble(x.GetX()))
+	args = append(args, formatDouble(x.GetY()))
+	args = append(args, formatDouble(x.GetZ()))
+	return args
+}
+
+func (x *Sector) appendArgs(args []string) []string {
+	args = append(args, formatDouble(x.GetLat()))
+	args = append(args, formatDouble(x.GetLon()))
+	args = append(args, formatDouble(x.GetRadius()))
+	args = append(args, formatDouble(x.GetStartBearing()))
+	args = append(args, formatDouble(x.GetEndBearing()))
+	return args
+}
+
+func (x *IntersectsRequest) appendArgs(args []string) []string {
+	args = append(args, "INTERSECTS")
+	args = append(args, x.GetKey())
+	if x != nil && x.Cursor != nil {
+		args = append(args, "CURSOR", strconv.FormatInt(*x.Cursor, 10))
+	}
+	if x != nil && x.Limit != nil {
+		args = append(args, "LIMIT", strconv.FormatInt(*x.Limit, 10))
+	}
+	if x != nil && x.Match != nil {
+		args = append(args, "MATCH", *x.Match)
+	}
+	for _, v := range x.GetWhere() {
+		args = append(args, "WHERE")
+		args = v.appendArgs(args)
+	}
+	for _, v := range x.GetWhereIn() {
+		args = append(args, "WHEREIN")
+		args = v.appendArgs(args)
+	}
+	for _, v := range x.GetWhereEval() {
+		args = append(args, "WHEREEVAL")
+		args = v.appendArgs(args)
+	}
+	for _, v := range x.GetWhereEvalSha() {
+		args = append(args, "WHEREEVALSHA")
+		args = v.appendArgs(args)
+	}
+	if x.GetClip() {
+		args = append(args, "CLIP")
+	}
+	if x.GetNoFields() {
+		args = append(args, "NOFIELDS")
+	}
+	if x.GetFence() {
+		args = append(args, "FENCE")
+	}
+	if x != nil && x.Detect != nil {
+		args = append(args, "DETECT", *x.Detect)
+	}
+	if x != nil && x.Commands != nil {
+		args = append(args, "COMMANDS", *x.Commands)
+	}
+	switch v := x.GetArea().(type) {
+	case *IntersectsRequest_Get:
+		args = append(args, "GET")
+		args = v.Get.appendArgs(args)
+	case *IntersectsRequest_Bounds:
+		args = append(args, "BOUNDS")
+		args = v.Bounds.appendArgs(args)
+	case *IntersectsRequest_Object:
+		args = append(args, "OBJECT", v.Object)
+	case *IntersectsRequest_Circle:
+		args = append(args, "CIRCLE")
+		args = v.Circle.appendArgs(args)
+	case *IntersectsRequest_Tile:
+		args = append(args, "TILE")
+		args = v.Tile.appendArgs(args)
+	case *IntersectsRequest_Quadkey:
+		args = append(args, "QUADKEY", v.Quadkey)
+	case *IntersectsRequest_Hash:
+		args = append(args, "HASH", v.Hash)
+	case *IntersectsRequest_Sector:
+		args = append(args, "SECTOR")
+		args = v.Sector.appendArgs(args)
+	}
+	return args
+}
+
+// Args returns the arguments of the INTERSECTS command.
+func (x *IntersectsRequest) Args() []string {
+	return x.appendArgs(nil)
+}
+
+func (x *SetHookRequest) appendArgs(args []string) []string {
+	args = append(args, "SETHOOK")
+	args = append(args, x.GetName())
+	args = append(args, x.GetEndpoint())
+	for _, v := range x.GetMeta() {
+		args = append(args, "META")
+		args = v.appendArgs(args)
+	}
+	if x != nil && x.Ex != nil {
+		args = append(args, "EX", formatDouble(*x.Ex))
+	}
+	if x != nil && x.Retry != nil {
+		args = append(args, "RETRY", strconv.FormatInt(*x.Retry, 10))
+	}
+	if v := x.GetBackoff(); v != nil {
+		args = append(args, "BACKOFF")
+		args = v.appendArgs(args)
+	}
+	if x != nil && x.MsgTtl != nil {
+		args = append(args, "MSGTTL", formatDouble(*x.MsgTtl))
+	}
+	if v := x.GetDlq(); v != nil {
+		args = append(args, "DLQ")
+		args = v.appendArgs(args)
+	}
+	if v := x.GetBatch(); v != nil {
+		args = append(args, "BATCH")
+		args = v.appendArgs(args)
+	}
+	for _, v := range x.GetSecret() {
+		args = append(args, "SECRET", v)
+	}
+	if x != nil && x.Stale != nil {
+		args = append(args, "STALE", formatDouble(*x.Stale))
+	}
+	switch v := x.GetWindow().(type) {
+	case *SetHookRequest_Schedule:
+		args = append(args, "SCHEDULE")
+		args = v.Schedule.appendArgs(args)
+	case *SetHookRequest_Active:
+		args = append(args, "ACTIVE")
+		args = v.Active.appendArgs(args)
+	}
+	if x != nil && x.Tz != nil {
+		args = append(args, "TZ", *x.Tz)
+	}
+	if v := x.GetFence(); v != nil {
+		args = v.appendArgs(args)
+	}
+	return args
+}
+
+// Args returns the arguments of the SETHOOK command.
+func (x *SetHookRequest) Args() []string {
+	return x.appendArgs(nil)
+}
+
+func (x *Meta) appendArgs(args []string) []string {
+	args = append(args, x.GetName())
+	args = append(args, x.GetValue())
+	return args
+}
+
+func (x *Backoff) appendArgs(args []string) []string {
+	args = append(args, formatDouble(x.GetMin()))
+	args = append(args, formatDouble(x.GetMax()))
+	return args
+}
+
+func (x *Dlq) appendArgs(args []string) []string {
+	args = append(args, strconv.FormatInt(x.GetMaxLen(), 10))
+	args = append(args, formatDouble(x.GetSeconds()))
+	return args
+}
+
+func (x *Batch) appendArgs(args []string) []string {
+	args = append(args, strconv.FormatInt(x.GetMaxEvents(), 10))
+	args = append(args, formatDouble(x.GetMaxDelay()))
+	return args
+}
+
+func (x *Schedule) appendArgs(args []string) []string {
+	args = append(args, x.GetCron())
+	args = append(args, "DURATION", formatDouble(x.GetDuration()))
+	return args
+}
+
+func (x *Active) appendArgs(args []string) []string {
+	args = append(args, x.GetFrom())
+	args = append(args, x.GetTo())
+	return args
+}
+
+func (x *FenceRequest) appendArgs(args []string) []string {
+	switch v := x.GetSearch().(type) {
+	case *FenceRequest_Nearby:
+		args = v.Nearby.appendArgs(args)
+	case *FenceRequest_Within:
+		args = v.Within.appendArgs(args)
+	case *FenceRequest_Intersects:
+		args = v.Intersects.appendArgs(args)
+	}
+	return args
+}
+
+// Args returns the arguments of the search command.
+func (x *FenceRequest) Args() []string {
+	return x.appendArgs(nil)
+}
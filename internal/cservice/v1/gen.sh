@@ -0,0 +1,7 @@
+#!/bin/bash
+
+cd $(dirname "${BASH_SOURCE[0]}")
+go run gen.go
+protoc -I . -I ../../hservice/v1 \
+	--go_out=. --go_opt=paths=source_relative \
+	--go-grpc_out=. --go-grpc_opt=paths=source_relative *.proto
@@ -44,6 +44,12 @@ const (
 	EventHub = Protocol("sb")
 	// CFQueue protocol
 	CFQueue = Protocol("cf-queue")
+	// WebSocket protocol
+	WebSocket = Protocol("ws")
+	// File protocol
+	File = Protocol("file")
+	// Unix socket protocol
+	Unix = Protocol("unix")
 )
 
 // Endpoint represents an endpoint.
@@ -51,8 +57,9 @@ type Endpoint struct {
 	Protocol Protocol
 	Original string
 	GRPC     struct {
-		Host string
-		Port int
+		Host   string
+		Port   int
+		Legacy bool
 	}
 	Disque struct {
 		Host      string
@@ -66,6 +73,8 @@ type Endpoint struct {
 		Host    string
 		Port    int
 		Channel string
+		XAdd    bool
+		MaxLen  int
 	}
 	Kafka struct {
 		Host       string
@@ -144,6 +153,20 @@ type Endpoint struct {
 	Local struct {
 		Channel string
 	}
+	WebSocket struct {
+		URL        string
+		Ack        bool
+		AckTimeout time.Duration
+	}
+	File struct {
+		Path    string
+		MaxSize int64
+		MaxAge  time.Duration
+		Fsync   string
+	}
+	Unix struct {
+		Path string
+	}
 }
 
 // Conn is an endpoint connection
@@ -153,11 +176,26 @@ type Conn interface {
 	Send(val string) error
 }
 
+// BatchConn is an endpoint connection that can deliver multiple messages
+// in a single request.
+type BatchConn interface {
+	Conn
+	SendBatch(vals []string) error
+}
+
+// SignedConn is an endpoint connection that can sign messages using a set
+// of shared secrets.
+type SignedConn interface {
+	Conn
+	SendSigned(val string, secrets []string) error
+}
+
 // Manager manages all endpoints
 type Manager struct {
 	mu        sync.RWMutex
 	conns     map[string]Conn
 	publisher LocalPublisher
+	localDir  string         // directory of the file and unix endpoints
 	shutdown  atomic.Bool    // atomic bool
 	wg        sync.WaitGroup // run wait group
 }
@@ -204,14 +242,71 @@ func (epc *Manager) run() {
 	}
 }
 
+// SetLocalDir sets the directory of the file and unix endpoints, which are
+// disabled when the directory is empty. The open file and unix connections
+// are expired.
+func (epc *Manager) SetLocalDir(dir string) {
+	epc.mu.Lock()
+	defer epc.mu.Unlock()
+	epc.localDir = dir
+	for _, conn := range epc.conns {
+		switch conn.(type) {
+		case *FileConn, *UnixConn:
+			conn.ExpireNow()
+		}
+	}
+}
+
 // Validate an endpoint url
 func (epc *Manager) Validate(url string) error {
-	_, err := parseEndpoint(url)
+	ep, err := parseEndpoint(url)
+	if err != nil {
+		return err
+	}
+	epc.mu.RLock()
+	dir := epc.localDir
+	epc.mu.RUnlock()
+	switch ep.Protocol {
+	case File:
+		_, err = localPath(dir, ep.File.Path)
+	case Unix:
+		_, err = localPath(dir, ep.Unix.Path)
+	}
 	return err
 }
 
-// Send send a message to an endpoint
-func (epc *Manager) Send(endpoint, msg string) error {
+// Send send a message to an endpoint. The message is signed with the
+// provided secrets when the endpoint supports signing.
+func (epc *Manager) Send(endpoint, msg string, secrets ...string) error {
+	return epc.send(endpoint, func(conn Conn) error {
+		return sendSigned(conn, msg, secrets)
+	})
+}
+
+// SendBatch sends multiple messages to an endpoint as a single delivery.
+// Connections that cannot natively send batches receive the messages as
+// one JSON array.
+func (epc *Manager) SendBatch(endpoint string, msgs []string,
+	secrets ...string,
+) error {
+	return epc.send(endpoint, func(conn Conn) error {
+		if conn, ok := conn.(BatchConn); ok {
+			return conn.SendBatch(msgs)
+		}
+		return sendSigned(conn, "["+strings.Join(msgs, ",")+"]", secrets)
+	})
+}
+
+func sendSigned(conn Conn, msg string, secrets []string) error {
+	if len(secrets) > 0 {
+		if conn, ok := conn.(SignedConn); ok {
+			return conn.SendSigned(msg, secrets)
+		}
+	}
+	return conn.Send(msg)
+}
+
+func (epc *Manager) send(endpoint string, send func(conn Conn) error) error {
 	for {
 		epc.mu.Lock()
 		conn, exists := epc.conns[endpoint]
@@ -223,6 +318,7 @@ func (epc *Manager) Send(endpoint, msg string) error {
 			}
 			switch ep.Protocol {
 			default:
+				epc.mu.Unlock()
 				return errors.New("invalid protocol")
 			case HTTP:
 				conn = newHTTPConn(ep)
@@ -248,13 +344,19 @@ func (epc *Manager) Send(endpoint, msg string) error {
 				conn = newLocalConn(ep, epc.publisher)
 			case EventHub:
 				conn = newEventHubConn(ep)
+			case WebSocket:
+				conn = newWebSocketConn(ep)
+			case File:
+				conn = newFileConn(ep, epc.localDir)
+			case Unix:
+				conn = newUnixConn(ep, epc.localDir)
 			case CFQueue:
 				conn = newCFQueueConn(ep)
 			}
 			epc.conns[endpoint] = conn
 		}
 		epc.mu.Unlock()
-		err := conn.Send(msg)
+		err := send(conn)
 		if err != nil {
 			if err == errExpired {
 				// it's possible that the connection has expired in-between
@@ -309,6 +411,12 @@ func parseEndpoint(s string) (Endpoint, error) {
 		endpoint.Protocol = EventHub
 	case strings.HasPrefix(s, "cf-queue:"):
 		endpoint.Protocol = CFQueue
+	case strings.HasPrefix(s, "ws:"), strings.HasPrefix(s, "wss:"):
+		endpoint.Protocol = WebSocket
+	case strings.HasPrefix(s, "file:"):
+		endpoint.Protocol = File
+	case strings.HasPrefix(s, "unix:"):
+		endpoint.Protocol = Unix
 	}
 
 	s = s[strings.Index(s, ":")+1:]
@@ -316,6 +424,65 @@ func parseEndpoint(s string) (Endpoint, error) {
 		return endpoint, errors.New("missing the two slashes")
 	}
 
+	// Local file and unix socket sinks have a path instead of a host
+	// file:///<path>?maxsize=<size>&maxage=<seconds>&fsync=<policy>
+	// unix:///<path>
+	//
+	//  file params are:
+	//
+	// maxsize - rotate the file when it reaches the size, such as 100mb
+	// maxage - rotate the file when it's older than the seconds
+	// fsync - none (default), everysec or always
+	if endpoint.Protocol == File || endpoint.Protocol == Unix {
+		pq := strings.SplitN(s[2:], "?", 2)
+		path, err := url.PathUnescape(pq[0])
+		if err != nil || !strings.HasPrefix(path, "/") || len(path) == 1 {
+			return endpoint, errors.New("missing absolute path")
+		}
+		if endpoint.Protocol == Unix {
+			if len(pq) > 1 {
+				return endpoint, errors.New("invalid unix url")
+			}
+			endpoint.Unix.Path = path
+			return endpoint, nil
+		}
+		endpoint.File.Path = path
+		endpoint.File.Fsync = FileSyncNone
+		if len(pq) > 1 {
+			m, err := url.ParseQuery(pq[1])
+			if err != nil {
+				return endpoint, errors.New("invalid file url")
+			}
+			for key, val := range m {
+				if len(val) == 0 {
+					continue
+				}
+				switch key {
+				case "maxsize":
+					endpoint.File.MaxSize, err = parseFileSize(val[0])
+					if err != nil {
+						return endpoint, errors.New("invalid file maxsize")
+					}
+				case "maxage":
+					secs, err := strconv.ParseFloat(val[0], 64)
+					if err != nil || secs < 0 {
+						return endpoint, errors.New("invalid file maxage")
+					}
+					endpoint.File.MaxAge =
+						time.Duration(secs * float64(time.Second))
+				case "fsync":
+					switch val[0] {
+					case FileSyncNone, FileSyncAlways, FileSyncEverySec:
+						endpoint.File.Fsync = val[0]
+					default:
+						return endpoint, errors.New("invalid file fsync")
+					}
+				}
+			}
+		}
+		return endpoint, nil
+	}
+
 	sqp := strings.Split(s[2:], "?")
 	sp := strings.Split(sqp[0], "/")
 	s = sp[0]
@@ -347,6 +514,22 @@ func parseEndpoint(s string) (Endpoint, error) {
 			}
 			endpoint.GRPC.Port = int(n)
 		}
+
+		// grpc://<host>:<port>?legacy=true
+		//
+		//  params are:
+		//
+		// legacy - use the legacy hservice.HookService/Send method, which
+		//          sends the events as json strings
+		if len(sqp) > 1 {
+			m, err := url.ParseQuery(sqp[1])
+			if err != nil {
+				return endpoint, errors.New("invalid grpc url")
+			}
+			if val := m.Get("legacy"); val != "" {
+				endpoint.GRPC.Legacy = queryBool(val)
+			}
+		}
 	}
 
 	if endpoint.Protocol == Redis {
@@ -373,6 +556,47 @@ func parseEndpoint(s string) (Endpoint, error) {
 				return endpoint, errors.New("invalid redis channel name")
 			}
 		}
+
+		// redis://<host>:<port>/<stream>?mode=xadd&maxlen=<n>
+		//
+		//  params are:
+		//
+		// mode - publish (default) or xadd
+		// maxlen - cap the stream length, xadd mode only
+		if len(sqp) > 1 {
+			m, err := url.ParseQuery(sqp[1])
+			if err != nil {
+				return endpoint, errors.New("invalid redis url")
+			}
+			for key, val := range m {
+				if len(val) == 0 {
+					continue
+				}
+				switch key {
+				case "mode":
+					switch strings.ToLower(val[0]) {
+					case "publish":
+						endpoint.Redis.XAdd = false
+					case "xadd":
+						endpoint.Redis.XAdd = true
+					default:
+						return endpoint, errors.New("invalid redis mode")
+					}
+				case "maxlen":
+					n, err := strconv.ParseUint(val[0], 10, 63)
+					if err != nil {
+						return endpoint, errors.New("invalid redis maxlen")
+					}
+					endpoint.Redis.MaxLen = int(n)
+				}
+			}
+		}
+		if endpoint.Redis.MaxLen > 0 && !endpoint.Redis.XAdd {
+			return endpoint, errors.New("redis maxlen requires xadd mode")
+		}
+		if endpoint.Redis.XAdd && endpoint.Redis.Channel == "" {
+			return endpoint, errors.New("missing redis stream name")
+		}
 	}
 
 	if endpoint.Protocol == Disque {
@@ -870,6 +1094,39 @@ func parseEndpoint(s string) (Endpoint, error) {
 		}
 	}
 
+	// WebSocket connection strings in HOOKS interface
+	// ws://<host>:<port>/<path>?ack=true&ack_timeout=<seconds>
+	// wss://<host>:<port>/<path>?ack=true&ack_timeout=<seconds>
+	//
+	//  params are:
+	//
+	// ack - wait for a reply from the server for each message
+	// ack_timeout - seconds to wait for the reply, default 5
+	//
+	// Other params are passed to the server as-is.
+	if endpoint.Protocol == WebSocket {
+		u, err := url.Parse(endpoint.Original)
+		if err != nil {
+			return endpoint, errors.New("invalid websocket url")
+		}
+		q := u.Query()
+		if v := q.Get("ack"); v != "" {
+			endpoint.WebSocket.Ack = queryBool(v)
+		}
+		if v := q.Get("ack_timeout"); v != "" {
+			secs, err := strconv.ParseFloat(v, 64)
+			if err != nil || secs <= 0 {
+				return endpoint, errors.New("invalid websocket ack_timeout")
+			}
+			endpoint.WebSocket.AckTimeout =
+				time.Duration(secs * float64(time.Second))
+		}
+		q.Del("ack")
+		q.Del("ack_timeout")
+		u.RawQuery = q.Encode()
+		endpoint.WebSocket.URL = u.String()
+	}
+
 	return endpoint, nil
 }
 
@@ -0,0 +1,246 @@
+package endpoint
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// File fsync policies
+const (
+	FileSyncNone     = "none"
+	FileSyncAlways   = "always"
+	FileSyncEverySec = "everysec"
+)
+
+const fileExpiresAfter = time.Second * 30
+
+var (
+	errLocalDisabled = errors.New("file and unix endpoints are disabled")
+	errLocalPath     = errors.New("path is outside of the endpoint directory")
+)
+
+// localPath returns the path of a file or unix endpoint, which must be in
+// the endpoint directory. The symbolic links are resolved, so that a link
+// can't point outside of the directory. An empty directory disables the
+// endpoints.
+func localPath(dir, path string) (string, error) {
+	if dir == "" {
+		return "", errLocalDisabled
+	}
+	root, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return "", err
+	}
+	// resolve the longest part of the path that exists
+	resolved, rest := filepath.Clean(path), ""
+	for {
+		p, err := filepath.EvalSymlinks(resolved)
+		if err == nil {
+			resolved = filepath.Join(p, rest)
+			break
+		}
+		if !os.IsNotExist(err) {
+			return "", err
+		}
+		parent := filepath.Dir(resolved)
+		if parent == resolved {
+			break
+		}
+		rest = filepath.Join(filepath.Base(resolved), rest)
+		resolved = parent
+	}
+	rel, err := filepath.Rel(root, resolved)
+	if err != nil || rel == "." || rel == ".." ||
+		strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", errLocalPath
+	}
+	return resolved, nil
+}
+
+// FileConn is an endpoint connection that appends newline-delimited events
+// to a local file, rotating the file by size and age.
+type FileConn struct {
+	mu       sync.Mutex
+	ep       Endpoint
+	dir      string // endpoint directory
+	ex       bool
+	t        time.Time
+	f        *os.File
+	size     int64     // size of the active file
+	opened   time.Time // when the active file was started
+	lastSync time.Time
+}
+
+func newFileConn(ep Endpoint, dir string) *FileConn {
+	return &FileConn{
+		ep:  ep,
+		dir: dir,
+		t:   time.Now(),
+	}
+}
+
+// Expired returns true if the connection has expired
+func (conn *FileConn) Expired() bool {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+	if !conn.ex {
+		if time.Since(conn.t) > fileExpiresAfter {
+			conn.close()
+			conn.ex = true
+		}
+	}
+	return conn.ex
+}
+
+// ExpireNow forces the connection to expire
+func (conn *FileConn) ExpireNow() {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+	conn.close()
+	conn.ex = true
+}
+
+func (conn *FileConn) close() {
+	if conn.f != nil {
+		if conn.ep.File.Fsync != FileSyncNone {
+			conn.f.Sync()
+		}
+		conn.f.Close()
+		conn.f = nil
+	}
+}
+
+func (conn *FileConn) open() error {
+	if conn.f != nil {
+		return nil
+	}
+	path, err := localPath(conn.dir, conn.ep.File.Path)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	conn.f = f
+	conn.size = fi.Size()
+	conn.opened = time.Now()
+	return nil
+}
+
+// rotate renames the active file using the current time as a suffix.
+func (conn *FileConn) rotate() error {
+	conn.close()
+	path, err := localPath(conn.dir, conn.ep.File.Path)
+	if err != nil {
+		return err
+	}
+	suffix := time.Now().UTC().Format("20060102T150405.000000000")
+	if err := os.Rename(path, path+"."+suffix); err != nil {
+		return err
+	}
+	return conn.open()
+}
+
+// write appends the data to the file, rotating the file first when needed.
+func (conn *FileConn) write(data []byte) error {
+	if conn.ex {
+		return errExpired
+	}
+	conn.t = time.Now()
+	if err := conn.open(); err != nil {
+		return err
+	}
+	if conn.size > 0 {
+		maxSize, maxAge := conn.ep.File.MaxSize, conn.ep.File.MaxAge
+		if (maxSize > 0 && conn.size+int64(len(data)) > maxSize) ||
+			(maxAge > 0 && time.Since(conn.opened) > maxAge) {
+			if err := conn.rotate(); err != nil {
+				conn.close()
+				return err
+			}
+		}
+	}
+	n, err := conn.f.Write(data)
+	conn.size += int64(n)
+	if err != nil {
+		conn.close()
+		return err
+	}
+	switch conn.ep.File.Fsync {
+	case FileSyncAlways:
+		err = conn.f.Sync()
+	case FileSyncEverySec:
+		if time.Since(conn.lastSync) >= time.Second {
+			err = conn.f.Sync()
+			conn.lastSync = time.Now()
+		}
+	}
+	if err != nil {
+		conn.close()
+		return err
+	}
+	return nil
+}
+
+// Send sends a message
+func (conn *FileConn) Send(msg string) error {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+	return conn.write(ndjson([]string{msg}))
+}
+
+// SendBatch writes multiple messages, one per line, with a single write.
+func (conn *FileConn) SendBatch(msgs []string) error {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+	return conn.write(ndjson(msgs))
+}
+
+// ndjson returns the messages as newline-delimited json.
+func ndjson(msgs []string) []byte {
+	var n int
+	for _, msg := range msgs {
+		n += len(msg) + 1
+	}
+	data := make([]byte, 0, n)
+	for _, msg := range msgs {
+		data = append(data, msg...)
+		data = append(data, '\n')
+	}
+	return data
+}
+
+// parseFileSize parses a size such as "100mb". Without a suffix the size is
+// in bytes.
+func parseFileSize(s string) (int64, error) {
+	s = strings.ToLower(s)
+	mul := int64(1)
+	for _, unit := range []struct {
+		suffix string
+		mul    int64
+	}{{"gb", 1 << 30}, {"mb", 1 << 20}, {"kb", 1 << 10}, {"b", 1}} {
+		if strings.HasSuffix(s, unit.suffix) {
+			s, mul = s[:len(s)-len(unit.suffix)], unit.mul
+			break
+		}
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n < 0 {
+		return 0, errors.New("invalid size")
+	}
+	return n * mul, nil
+}
@@ -3,24 +3,33 @@ package endpoint
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/aiqia-dev/meridian/internal/hservice"
+	hservicev1 "github.com/aiqia-dev/meridian/internal/hservice/v1"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
 )
 
-const grpcExpiresAfter = time.Second * 30
+const (
+	grpcExpiresAfter = time.Second * 30
+	grpcAckTimeout   = time.Second * 10
+)
 
 // GRPCConn is an endpoint connection
 type GRPCConn struct {
-	mu    sync.Mutex
-	ep    Endpoint
-	ex    bool
-	t     time.Time
-	conn  *grpc.ClientConn
-	sconn hservice.HookServiceClient
+	mu     sync.Mutex
+	ep     Endpoint
+	ex     bool
+	t      time.Time
+	conn   *grpc.ClientConn
+	sconn  hservice.HookServiceClient // legacy service
+	client hservicev1.HookServiceClient
+	stream hservicev1.HookService_StreamClient
+	cancel context.CancelFunc // cancels the active stream
+	seq    uint64             // last event sequence sent on the stream
 }
 
 func newGRPCConn(ep Endpoint) *GRPCConn {
@@ -52,16 +61,22 @@ func (conn *GRPCConn) ExpireNow() {
 }
 
 func (conn *GRPCConn) close() {
+	conn.closeStream()
 	if conn.conn != nil {
 		conn.conn.Close()
 		conn.conn = nil
 	}
 }
 
-// Send sends a message
-func (conn *GRPCConn) Send(msg string) error {
-	conn.mu.Lock()
-	defer conn.mu.Unlock()
+func (conn *GRPCConn) closeStream() {
+	if conn.stream != nil {
+		conn.cancel()
+		conn.stream = nil
+		conn.cancel = nil
+	}
+}
+
+func (conn *GRPCConn) connect() error {
 	if conn.ex {
 		return errExpired
 	}
@@ -75,7 +90,40 @@ func (conn *GRPCConn) Send(msg string) error {
 			return err
 		}
 		conn.sconn = hservice.NewHookServiceClient(conn.conn)
+		conn.client = hservicev1.NewHookServiceClient(conn.conn)
+	}
+	return nil
+}
+
+// Send sends a message
+func (conn *GRPCConn) Send(msg string) error {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+	if err := conn.connect(); err != nil {
+		return err
 	}
+	if conn.ep.GRPC.Legacy {
+		return conn.sendLegacy(msg)
+	}
+	return conn.sendEvents([]string{msg})
+}
+
+// SendBatch sends multiple messages. The events are written to the stream
+// and then all acks are awaited. In legacy mode, the messages are sent as a
+// single json array.
+func (conn *GRPCConn) SendBatch(msgs []string) error {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+	if err := conn.connect(); err != nil {
+		return err
+	}
+	if conn.ep.GRPC.Legacy {
+		return conn.sendLegacy("[" + strings.Join(msgs, ",") + "]")
+	}
+	return conn.sendEvents(msgs)
+}
+
+func (conn *GRPCConn) sendLegacy(msg string) error {
 	r, err := conn.sconn.Send(context.Background(), &hservice.MessageRequest{Value: msg})
 	if err != nil {
 		conn.close()
@@ -87,3 +135,75 @@ func (conn *GRPCConn) Send(msg string) error {
 	}
 	return nil
 }
+
+// sendEvents writes the messages as typed events to the stream, opening the
+// stream when needed, and waits for an ack for each event.
+func (conn *GRPCConn) sendEvents(msgs []string) error {
+	if conn.stream == nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		stream, err := conn.client.Stream(ctx)
+		if err != nil {
+			cancel()
+			conn.close()
+			return err
+		}
+		conn.stream = stream
+		conn.cancel = cancel
+	}
+	first := conn.seq + 1
+	for _, msg := range msgs {
+		ev, err := hservicev1.EventFromJSON(msg)
+		if err != nil {
+			return err
+		}
+		conn.seq++
+		ev.Seq = conn.seq
+		if err := conn.stream.Send(ev); err != nil {
+			conn.closeStream()
+			return err
+		}
+	}
+	stream := conn.stream
+	done := make(chan error, 1)
+	go func() {
+		done <- recvAcks(stream, first, len(msgs))
+	}()
+	select {
+	case err := <-done:
+		if err != nil && !errors.Is(err, errGRPCRejected) {
+			conn.closeStream()
+		}
+		return err
+	case <-time.After(grpcAckTimeout):
+		conn.closeStream()
+		<-done
+		return errors.New("grpc ack timeout")
+	}
+}
+
+var errGRPCRejected = errors.New("grpc event rejected")
+
+// recvAcks reads n acks from the stream, which must arrive in order starting
+// at the first sequence. All acks are read, even when an event is rejected,
+// so the stream stays in sync.
+func recvAcks(stream hservicev1.HookService_StreamClient, first uint64,
+	n int,
+) error {
+	var rejected error
+	for i := 0; i < n; i++ {
+		ack, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		if ack.Seq != first+uint64(i) {
+			return errors.New("grpc ack out of order")
+		}
+		if !ack.Ok && rejected == nil {
+			rejected = errGRPCRejected
+			if ack.Error != "" {
+				rejected = fmt.Errorf("%w: %s", errGRPCRejected, ack.Error)
+			}
+		}
+	}
+	return rejected
+}
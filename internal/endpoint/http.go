@@ -2,9 +2,14 @@ package endpoint
 
 import (
 	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -14,6 +19,13 @@ const (
 	httpMaxIdleConnections = 20
 )
 
+const (
+	// HTTPSignatureHeader holds the HMAC-SHA256 signatures of a delivery.
+	HTTPSignatureHeader = "X-Meridian-Signature"
+	// HTTPTimestampHeader holds the unix time of a delivery.
+	HTTPTimestampHeader = "X-Meridian-Timestamp"
+)
+
 // HTTPConn is an endpoint connection
 type HTTPConn struct {
 	ep     Endpoint
@@ -44,12 +56,30 @@ func (conn *HTTPConn) ExpireNow() {
 
 // Send sends a message
 func (conn *HTTPConn) Send(msg string) error {
+	return conn.SendSigned(msg, nil)
+}
+
+// SendSigned sends a message that is signed with each of the secrets.
+//
+// The signature header contains one "v1=<hex>" entry per secret, separated
+// by commas, where <hex> is the HMAC-SHA256 of the timestamp header value,
+// a dot and the request body.
+func (conn *HTTPConn) SendSigned(msg string, secrets []string) error {
 	req, err := http.NewRequest("POST", conn.ep.Original, bytes.NewBufferString(msg))
 	if err != nil {
 		return err
 	}
 
 	req.Header.Set("Content-Type", "application/json")
+	if len(secrets) > 0 {
+		ts := strconv.FormatInt(time.Now().Unix(), 10)
+		sigs := make([]string, len(secrets))
+		for i, secret := range secrets {
+			sigs[i] = "v1=" + SignHTTP(secret, ts, msg)
+		}
+		req.Header.Set(HTTPTimestampHeader, ts)
+		req.Header.Set(HTTPSignatureHeader, strings.Join(sigs, ","))
+	}
 	resp, err := conn.client.Do(req)
 	if err != nil {
 		return err
@@ -68,3 +98,12 @@ func (conn *HTTPConn) Send(msg string) error {
 	}
 	return nil
 }
+
+// SignHTTP returns the hex encoded HMAC-SHA256 signature of an HTTP delivery.
+func SignHTTP(secret, timestamp, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte{'.'})
+	mac.Write([]byte(body))
+	return hex.EncodeToString(mac.Sum(nil))
+}
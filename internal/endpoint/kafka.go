@@ -68,6 +68,51 @@ func (conn *KafkaConn) Send(msg string) error {
 	}
 	conn.t = time.Now()
 
+	if err := conn.connect(); err != nil {
+		return err
+	}
+
+	_, offset, err := conn.conn.SendMessage(conn.message(msg))
+	if err != nil {
+		conn.close()
+		return err
+	}
+
+	if offset < 0 {
+		conn.close()
+		return errors.New("invalid kafka reply")
+	}
+
+	return nil
+}
+
+// SendBatch sends multiple messages in a single produce request
+func (conn *KafkaConn) SendBatch(msgs []string) error {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+
+	if conn.ex {
+		return errExpired
+	}
+	conn.t = time.Now()
+
+	if err := conn.connect(); err != nil {
+		return err
+	}
+
+	messages := make([]*sarama.ProducerMessage, len(msgs))
+	for i, msg := range msgs {
+		messages[i] = conn.message(msg)
+	}
+	if err := conn.conn.SendMessages(messages); err != nil {
+		conn.close()
+		return err
+	}
+	return nil
+}
+
+// connect opens the producer, if needed
+func (conn *KafkaConn) connect() error {
 	if log.Level() > 2 {
 		sarama.Logger = lg.New(log.Output(), "[sarama] ", 0)
 	}
@@ -172,29 +217,21 @@ func (conn *KafkaConn) Send(msg string) error {
 		conn.cfg = cfg
 	}
 
+	return nil
+}
+
+// message returns the producer message for a hook message
+func (conn *KafkaConn) message(msg string) *sarama.ProducerMessage {
 	// parse json again to get out info for our kafka key
 	key := gjson.Get(msg, "key")
 	id := gjson.Get(msg, "id")
 	keyValue := fmt.Sprintf("%s-%s", key.String(), id.String())
 
-	message := &sarama.ProducerMessage{
+	return &sarama.ProducerMessage{
 		Topic: conn.ep.Kafka.TopicName,
 		Key:   sarama.StringEncoder(keyValue),
 		Value: sarama.StringEncoder(msg),
 	}
-
-	_, offset, err := conn.conn.SendMessage(message)
-	if err != nil {
-		conn.close()
-		return err
-	}
-
-	if offset < 0 {
-		conn.close()
-		return errors.New("invalid kafka reply")
-	}
-
-	return nil
 }
 
 func newKafkaConn(ep Endpoint) *KafkaConn {
@@ -2,10 +2,12 @@ package endpoint
 
 import (
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/gomodule/redigo/redis"
+	"github.com/tidwall/gjson"
 )
 
 const redisExpiresAfter = time.Second * 30
@@ -63,6 +65,60 @@ func (conn *RedisConn) Send(msg string) error {
 		return errExpired
 	}
 	conn.t = time.Now()
+	if err := conn.connect(); err != nil {
+		return err
+	}
+	var err error
+	if conn.ep.Redis.XAdd {
+		_, err = redis.String(conn.conn.Do("XADD", xaddArgs(conn.ep, msg)...))
+	} else {
+		_, err = redis.Int(conn.conn.Do("PUBLISH", conn.ep.Redis.Channel, msg))
+	}
+	if err != nil {
+		conn.close()
+		return err
+	}
+	return nil
+}
+
+// SendBatch sends multiple messages. In XADD mode every message is added as
+// its own stream entry, with the commands pipelined in a single round trip.
+// Otherwise the messages are published as one JSON array.
+func (conn *RedisConn) SendBatch(msgs []string) error {
+	if !conn.ep.Redis.XAdd {
+		return conn.Send("[" + strings.Join(msgs, ",") + "]")
+	}
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+
+	if conn.ex {
+		return errExpired
+	}
+	conn.t = time.Now()
+	if err := conn.connect(); err != nil {
+		return err
+	}
+	for _, msg := range msgs {
+		if err := conn.conn.Send("XADD", xaddArgs(conn.ep, msg)...); err != nil {
+			conn.close()
+			return err
+		}
+	}
+	if err := conn.conn.Flush(); err != nil {
+		conn.close()
+		return err
+	}
+	for range msgs {
+		if _, err := redis.String(conn.conn.Receive()); err != nil {
+			conn.close()
+			return err
+		}
+	}
+	return nil
+}
+
+// connect dials the redis server, if needed
+func (conn *RedisConn) connect() error {
 	if conn.conn == nil {
 		addr := fmt.Sprintf("%s:%d", conn.ep.Redis.Host, conn.ep.Redis.Port)
 		var err error
@@ -72,10 +128,23 @@ func (conn *RedisConn) Send(msg string) error {
 			return err
 		}
 	}
-	_, err := redis.Int(conn.conn.Do("PUBLISH", conn.ep.Redis.Channel, msg))
-	if err != nil {
-		conn.close()
-		return err
-	}
 	return nil
 }
+
+// xaddArgs returns the XADD arguments for a message. The hook, key, id,
+// detect and command members of the message are copied to their own stream
+// entry fields, and the full message is stored in the "message" field.
+func xaddArgs(ep Endpoint, msg string) []interface{} {
+	args := []interface{}{ep.Redis.Channel}
+	if ep.Redis.MaxLen > 0 {
+		args = append(args, "MAXLEN", ep.Redis.MaxLen)
+	}
+	args = append(args, "*")
+	res := gjson.GetMany(msg, "hook", "key", "id", "detect", "command")
+	for i, name := range []string{"hook", "key", "id", "detect", "command"} {
+		if res[i].Exists() {
+			args = append(args, name, res[i].String())
+		}
+	}
+	return append(args, "message", msg)
+}
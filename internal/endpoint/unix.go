@@ -0,0 +1,97 @@
+package endpoint
+
+import (
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	unixExpiresAfter = time.Second * 30
+	unixWriteTimeout = time.Second * 10
+)
+
+// UnixConn is an endpoint connection that streams newline-delimited events
+// to a unix domain socket.
+type UnixConn struct {
+	mu   sync.Mutex
+	ep   Endpoint
+	dir  string // endpoint directory
+	ex   bool
+	t    time.Time
+	conn net.Conn
+}
+
+func newUnixConn(ep Endpoint, dir string) *UnixConn {
+	return &UnixConn{
+		ep:  ep,
+		dir: dir,
+		t:   time.Now(),
+	}
+}
+
+// Expired returns true if the connection has expired
+func (conn *UnixConn) Expired() bool {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+	if !conn.ex {
+		if time.Since(conn.t) > unixExpiresAfter {
+			conn.close()
+			conn.ex = true
+		}
+	}
+	return conn.ex
+}
+
+// ExpireNow forces the connection to expire
+func (conn *UnixConn) ExpireNow() {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+	conn.close()
+	conn.ex = true
+}
+
+func (conn *UnixConn) close() {
+	if conn.conn != nil {
+		conn.conn.Close()
+		conn.conn = nil
+	}
+}
+
+func (conn *UnixConn) write(data []byte) error {
+	if conn.ex {
+		return errExpired
+	}
+	conn.t = time.Now()
+	if conn.conn == nil {
+		path, err := localPath(conn.dir, conn.ep.Unix.Path)
+		if err != nil {
+			return err
+		}
+		c, err := net.DialTimeout("unix", path, unixWriteTimeout)
+		if err != nil {
+			return err
+		}
+		conn.conn = c
+	}
+	conn.conn.SetWriteDeadline(time.Now().Add(unixWriteTimeout))
+	if _, err := conn.conn.Write(data); err != nil {
+		conn.close()
+		return err
+	}
+	return nil
+}
+
+// Send sends a message
+func (conn *UnixConn) Send(msg string) error {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+	return conn.write(ndjson([]string{msg}))
+}
+
+// SendBatch writes multiple messages, one per line, with a single write.
+func (conn *UnixConn) SendBatch(msgs []string) error {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+	return conn.write(ndjson(msgs))
+}
@@ -0,0 +1,186 @@
+package endpoint
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/tidwall/gjson"
+)
+
+const (
+	wsExpiresAfter   = time.Minute * 5
+	wsDialTimeout    = time.Second * 10
+	wsWriteTimeout   = time.Second * 10
+	wsBackoffMin     = time.Second / 2
+	wsBackoffMax     = time.Second * 30
+	wsAckTimeoutDef  = time.Second * 5
+	wsPingInterval   = time.Second * 30
+	wsReadBufferSize = 16
+)
+
+var errWebSocketBackoff = errors.New("websocket reconnect backoff")
+
+// WebSocketConn is an endpoint connection that keeps a long-lived websocket
+// client connection.
+type WebSocketConn struct {
+	mu      sync.Mutex
+	ep      Endpoint
+	ex      bool
+	t       time.Time
+	conn    *websocket.Conn
+	replies chan string   // text frames received from the remote server
+	done    chan struct{} // closed when the connection reader exits
+	backoff time.Duration // current reconnect backoff
+	retryAt time.Time     // no reconnect attempts until this time
+}
+
+func newWebSocketConn(ep Endpoint) *WebSocketConn {
+	return &WebSocketConn{
+		ep: ep,
+		t:  time.Now(),
+	}
+}
+
+// Expired returns true if the connection has expired
+func (conn *WebSocketConn) Expired() bool {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+	if !conn.ex {
+		if time.Since(conn.t) > wsExpiresAfter {
+			conn.close()
+			conn.ex = true
+		}
+	}
+	return conn.ex
+}
+
+// ExpireNow forces the connection to expire
+func (conn *WebSocketConn) ExpireNow() {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+	conn.close()
+	conn.ex = true
+}
+
+func (conn *WebSocketConn) close() {
+	if conn.conn != nil {
+		conn.conn.Close()
+		<-conn.done
+		conn.conn = nil
+	}
+}
+
+// connect dials the remote server, honoring the reconnect backoff.
+func (conn *WebSocketConn) connect() error {
+	if conn.conn != nil {
+		return nil
+	}
+	if time.Now().Before(conn.retryAt) {
+		return errWebSocketBackoff
+	}
+	dialer := *websocket.DefaultDialer
+	dialer.HandshakeTimeout = wsDialTimeout
+	c, _, err := dialer.Dial(conn.ep.WebSocket.URL, nil)
+	if err != nil {
+		if conn.backoff == 0 {
+			conn.backoff = wsBackoffMin
+		} else if conn.backoff *= 2; conn.backoff > wsBackoffMax {
+			conn.backoff = wsBackoffMax
+		}
+		conn.retryAt = time.Now().Add(conn.backoff)
+		return err
+	}
+	conn.backoff = 0
+	conn.retryAt = time.Time{}
+	conn.conn = c
+	conn.replies = make(chan string, wsReadBufferSize)
+	conn.done = make(chan struct{})
+	go conn.read(c, conn.replies, conn.done)
+	return nil
+}
+
+// read processes incoming frames until the connection is closed. Control
+// frames, such as pings, are handled by the websocket library while reading.
+func (conn *WebSocketConn) read(c *websocket.Conn, replies chan string,
+	done chan struct{},
+) {
+	defer close(done)
+	ticker := time.NewTicker(wsPingInterval)
+	defer ticker.Stop()
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				c.WriteControl(websocket.PingMessage, nil,
+					time.Now().Add(wsWriteTimeout))
+			}
+		}
+	}()
+	for {
+		typ, data, err := c.ReadMessage()
+		if err != nil {
+			return
+		}
+		if typ != websocket.TextMessage {
+			continue
+		}
+		select {
+		case replies <- string(data):
+		default:
+			// drop unsolicited messages when nobody is waiting for an ack
+		}
+	}
+}
+
+// Send sends a message
+func (conn *WebSocketConn) Send(msg string) error {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+	if conn.ex {
+		return errExpired
+	}
+	conn.t = time.Now()
+	if err := conn.connect(); err != nil {
+		return err
+	}
+	// discard stale replies from earlier deliveries
+	for len(conn.replies) > 0 {
+		<-conn.replies
+	}
+	conn.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+	err := conn.conn.WriteMessage(websocket.TextMessage, []byte(msg))
+	if err != nil {
+		conn.close()
+		return err
+	}
+	if !conn.ep.WebSocket.Ack {
+		return nil
+	}
+	timeout := conn.ep.WebSocket.AckTimeout
+	if timeout == 0 {
+		timeout = wsAckTimeoutDef
+	}
+	select {
+	case reply := <-conn.replies:
+		// any reply acknowledges the message, unless it's a json object
+		// with "ok":false
+		res := gjson.Parse(reply)
+		if res.IsObject() && res.Get("ok").Exists() && !res.Get("ok").Bool() {
+			if errmsg := res.Get("err").String(); errmsg != "" {
+				return errors.New(errmsg)
+			}
+			return errors.New("websocket message rejected")
+		}
+		return nil
+	case <-conn.done:
+		conn.close()
+		return errors.New("websocket connection closed")
+	case <-time.After(timeout):
+		conn.close()
+		return errors.New("websocket ack timeout")
+	}
+}
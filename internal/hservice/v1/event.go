@@ -0,0 +1,71 @@
+package hservicev1
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/tidwall/gjson"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// EventFromJSON converts a json geofence message into a typed event.
+func EventFromJSON(msg string) (*GeofenceEvent, error) {
+	res := gjson.Parse(msg)
+	ev := &GeofenceEvent{
+		Command: res.Get("command").String(),
+		Detect:  res.Get("detect").String(),
+		Hook:    res.Get("hook").String(),
+		Key:     res.Get("key").String(),
+		Id:      res.Get("id").String(),
+		Group:   res.Get("group").String(),
+		Json:    msg,
+	}
+	if t, err := time.Parse(time.RFC3339Nano, res.Get("time").String()); err == nil {
+		ev.Time = timestamppb.New(t)
+	}
+	ev.Geometry = GeometryFromJSON(res.Get("object"))
+	if fields := res.Get("fields"); fields.IsObject() {
+		var m map[string]interface{}
+		if err := json.Unmarshal([]byte(fields.Raw), &m); err != nil {
+			return nil, err
+		}
+		var err error
+		if ev.Fields, err = structpb.NewStruct(m); err != nil {
+			return nil, err
+		}
+	}
+	if meta := res.Get("meta"); meta.IsObject() {
+		ev.Meta = make(map[string]string)
+		meta.ForEach(func(key, val gjson.Result) bool {
+			ev.Meta[key.String()] = val.String()
+			return true
+		})
+	}
+	return ev, nil
+}
+
+// GeometryFromJSON converts a json object member into a geometry. Returns
+// nil when the object does not exist.
+func GeometryFromJSON(obj gjson.Result) *Geometry {
+	if !obj.Exists() {
+		return nil
+	}
+	geom := new(Geometry)
+	if obj.Type == gjson.String {
+		geom.Geojson = obj.String()
+		return geom
+	}
+	geom.Geojson = obj.Raw
+	coords := obj.Get("coordinates").Array()
+	if obj.Get("type").String() == "Point" && len(coords) >= 2 {
+		geom.Point = &Point{
+			Lon: coords[0].Float(),
+			Lat: coords[1].Float(),
+		}
+		if len(coords) > 2 {
+			geom.Point.Z = coords[2].Float()
+		}
+	}
+	return geom
+}
@@ -0,0 +1,437 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: hookservice.proto
+
+package hservicev1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// A geofence event
+type GeofenceEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Per-stream sequence number, echoed back in the ack
+	Seq      uint64                 `protobuf:"varint,1,opt,name=seq,proto3" json:"seq,omitempty"`
+	Command  string                 `protobuf:"bytes,2,opt,name=command,proto3" json:"command,omitempty"`
+	Detect   string                 `protobuf:"bytes,3,opt,name=detect,proto3" json:"detect,omitempty"`
+	Hook     string                 `protobuf:"bytes,4,opt,name=hook,proto3" json:"hook,omitempty"`
+	Key      string                 `protobuf:"bytes,5,opt,name=key,proto3" json:"key,omitempty"`
+	Id       string                 `protobuf:"bytes,6,opt,name=id,proto3" json:"id,omitempty"`
+	Group    string                 `protobuf:"bytes,7,opt,name=group,proto3" json:"group,omitempty"`
+	Time     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=time,proto3" json:"time,omitempty"`
+	Geometry *Geometry              `protobuf:"bytes,9,opt,name=geometry,proto3" json:"geometry,omitempty"`
+	Fields   *structpb.Struct       `protobuf:"bytes,10,opt,name=fields,proto3" json:"fields,omitempty"`
+	Meta     map[string]string      `protobuf:"bytes,11,rep,name=meta,proto3" json:"meta,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// The original json message
+	Json          string `protobuf:"bytes,12,opt,name=json,proto3" json:"json,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GeofenceEvent) Reset() {
+	*x = GeofenceEvent{}
+	mi := &file_hookservice_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GeofenceEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GeofenceEvent) ProtoMessage() {}
+
+func (x *GeofenceEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_hookservice_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GeofenceEvent.ProtoReflect.Descriptor instead.
+func (*GeofenceEvent) Descriptor() ([]byte, []int) {
+	return file_hookservice_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GeofenceEvent) GetSeq() uint64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *GeofenceEvent) GetCommand() string {
+	if x != nil {
+		return x.Command
+	}
+	return ""
+}
+
+func (x *GeofenceEvent) GetDetect() string {
+	if x != nil {
+		return x.Detect
+	}
+	return ""
+}
+
+func (x *GeofenceEvent) GetHook() string {
+	if x != nil {
+		return x.Hook
+	}
+	return ""
+}
+
+func (x *GeofenceEvent) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *GeofenceEvent) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *GeofenceEvent) GetGroup() string {
+	if x != nil {
+		return x.Group
+	}
+	return ""
+}
+
+func (x *GeofenceEvent) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *GeofenceEvent) GetGeometry() *Geometry {
+	if x != nil {
+		return x.Geometry
+	}
+	return nil
+}
+
+func (x *GeofenceEvent) GetFields() *structpb.Struct {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+func (x *GeofenceEvent) GetMeta() map[string]string {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+func (x *GeofenceEvent) GetJson() string {
+	if x != nil {
+		return x.Json
+	}
+	return ""
+}
+
+// The geometry of the object that triggered the event
+type Geometry struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Set when the object is a point
+	Point *Point `protobuf:"bytes,1,opt,name=point,proto3" json:"point,omitempty"`
+	// The object as GeoJSON, or the raw value for string objects
+	Geojson       string `protobuf:"bytes,2,opt,name=geojson,proto3" json:"geojson,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Geometry) Reset() {
+	*x = Geometry{}
+	mi := &file_hookservice_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Geometry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Geometry) ProtoMessage() {}
+
+func (x *Geometry) ProtoReflect() protoreflect.Message {
+	mi := &file_hookservice_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Geometry.ProtoReflect.Descriptor instead.
+func (*Geometry) Descriptor() ([]byte, []int) {
+	return file_hookservice_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Geometry) GetPoint() *Point {
+	if x != nil {
+		return x.Point
+	}
+	return nil
+}
+
+func (x *Geometry) GetGeojson() string {
+	if x != nil {
+		return x.Geojson
+	}
+	return ""
+}
+
+// A point
+type Point struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Lat           float64                `protobuf:"fixed64,1,opt,name=lat,proto3" json:"lat,omitempty"`
+	Lon           float64                `protobuf:"fixed64,2,opt,name=lon,proto3" json:"lon,omitempty"`
+	Z             float64                `protobuf:"fixed64,3,opt,name=z,proto3" json:"z,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Point) Reset() {
+	*x = Point{}
+	mi := &file_hookservice_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Point) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Point) ProtoMessage() {}
+
+func (x *Point) ProtoReflect() protoreflect.Message {
+	mi := &file_hookservice_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Point.ProtoReflect.Descriptor instead.
+func (*Point) Descriptor() ([]byte, []int) {
+	return file_hookservice_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Point) GetLat() float64 {
+	if x != nil {
+		return x.Lat
+	}
+	return 0
+}
+
+func (x *Point) GetLon() float64 {
+	if x != nil {
+		return x.Lon
+	}
+	return 0
+}
+
+func (x *Point) GetZ() float64 {
+	if x != nil {
+		return x.Z
+	}
+	return 0
+}
+
+// The response message acknowledging an event
+type Ack struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Seq           uint64                 `protobuf:"varint,1,opt,name=seq,proto3" json:"seq,omitempty"`
+	Ok            bool                   `protobuf:"varint,2,opt,name=ok,proto3" json:"ok,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Ack) Reset() {
+	*x = Ack{}
+	mi := &file_hookservice_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Ack) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Ack) ProtoMessage() {}
+
+func (x *Ack) ProtoReflect() protoreflect.Message {
+	mi := &file_hookservice_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Ack.ProtoReflect.Descriptor instead.
+func (*Ack) Descriptor() ([]byte, []int) {
+	return file_hookservice_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Ack) GetSeq() uint64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *Ack) GetOk() bool {
+	if x != nil {
+		return x.Ok
+	}
+	return false
+}
+
+func (x *Ack) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+var File_hookservice_proto protoreflect.FileDescriptor
+
+const file_hookservice_proto_rawDesc = "" +
+	"\n" +
+	"\x11hookservice.proto\x12\vhservice.v1\x1a\x1cgoogle/protobuf/struct.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xba\x03\n" +
+	"\rGeofenceEvent\x12\x10\n" +
+	"\x03seq\x18\x01 \x01(\x04R\x03seq\x12\x18\n" +
+	"\acommand\x18\x02 \x01(\tR\acommand\x12\x16\n" +
+	"\x06detect\x18\x03 \x01(\tR\x06detect\x12\x12\n" +
+	"\x04hook\x18\x04 \x01(\tR\x04hook\x12\x10\n" +
+	"\x03key\x18\x05 \x01(\tR\x03key\x12\x0e\n" +
+	"\x02id\x18\x06 \x01(\tR\x02id\x12\x14\n" +
+	"\x05group\x18\a \x01(\tR\x05group\x12.\n" +
+	"\x04time\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\x04time\x121\n" +
+	"\bgeometry\x18\t \x01(\v2\x15.hservice.v1.GeometryR\bgeometry\x12/\n" +
+	"\x06fields\x18\n" +
+	" \x01(\v2\x17.google.protobuf.StructR\x06fields\x128\n" +
+	"\x04meta\x18\v \x03(\v2$.hservice.v1.GeofenceEvent.MetaEntryR\x04meta\x12\x12\n" +
+	"\x04json\x18\f \x01(\tR\x04json\x1a7\n" +
+	"\tMetaEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"N\n" +
+	"\bGeometry\x12(\n" +
+	"\x05point\x18\x01 \x01(\v2\x12.hservice.v1.PointR\x05point\x12\x18\n" +
+	"\ageojson\x18\x02 \x01(\tR\ageojson\"9\n" +
+	"\x05Point\x12\x10\n" +
+	"\x03lat\x18\x01 \x01(\x01R\x03lat\x12\x10\n" +
+	"\x03lon\x18\x02 \x01(\x01R\x03lon\x12\f\n" +
+	"\x01z\x18\x03 \x01(\x01R\x01z\"=\n" +
+	"\x03Ack\x12\x10\n" +
+	"\x03seq\x18\x01 \x01(\x04R\x03seq\x12\x0e\n" +
+	"\x02ok\x18\x02 \x01(\bR\x02ok\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error2\x83\x01\n" +
+	"\vHookService\x126\n" +
+	"\x04Send\x12\x1a.hservice.v1.GeofenceEvent\x1a\x10.hservice.v1.Ack\"\x00\x12<\n" +
+	"\x06Stream\x12\x1a.hservice.v1.GeofenceEvent\x1a\x10.hservice.v1.Ack\"\x00(\x010\x01Bm\n" +
+	"\x18com.meridian.hservice.v1B\x10HookServiceProtoP\x01Z=github.com/aiqia-dev/meridian/internal/hservice/v1;hservicev1b\x06proto3"
+
+var (
+	file_hookservice_proto_rawDescOnce sync.Once
+	file_hookservice_proto_rawDescData []byte
+)
+
+func file_hookservice_proto_rawDescGZIP() []byte {
+	file_hookservice_proto_rawDescOnce.Do(func() {
+		file_hookservice_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_hookservice_proto_rawDesc), len(file_hookservice_proto_rawDesc)))
+	})
+	return file_hookservice_proto_rawDescData
+}
+
+var file_hookservice_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_hookservice_proto_goTypes = []any{
+	(*GeofenceEvent)(nil),         // 0: hservice.v1.GeofenceEvent
+	(*Geometry)(nil),              // 1: hservice.v1.Geometry
+	(*Point)(nil),                 // 2: hservice.v1.Point
+	(*Ack)(nil),                   // 3: hservice.v1.Ack
+	nil,                           // 4: hservice.v1.GeofenceEvent.MetaEntry
+	(*timestamppb.Timestamp)(nil), // 5: google.protobuf.Timestamp
+	(*structpb.Struct)(nil),       // 6: google.protobuf.Struct
+}
+var file_hookservice_proto_depIdxs = []int32{
+	5, // 0: hservice.v1.GeofenceEvent.time:type_name -> google.protobuf.Timestamp
+	1, // 1: hservice.v1.GeofenceEvent.geometry:type_name -> hservice.v1.Geometry
+	6, // 2: hservice.v1.GeofenceEvent.fields:type_name -> google.protobuf.Struct
+	4, // 3: hservice.v1.GeofenceEvent.meta:type_name -> hservice.v1.GeofenceEvent.MetaEntry
+	2, // 4: hservice.v1.Geometry.point:type_name -> hservice.v1.Point
+	0, // 5: hservice.v1.HookService.Send:input_type -> hservice.v1.GeofenceEvent
+	0, // 6: hservice.v1.HookService.Stream:input_type -> hservice.v1.GeofenceEvent
+	3, // 7: hservice.v1.HookService.Send:output_type -> hservice.v1.Ack
+	3, // 8: hservice.v1.HookService.Stream:output_type -> hservice.v1.Ack
+	7, // [7:9] is the sub-list for method output_type
+	5, // [5:7] is the sub-list for method input_type
+	5, // [5:5] is the sub-list for extension type_name
+	5, // [5:5] is the sub-list for extension extendee
+	0, // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_hookservice_proto_init() }
+func file_hookservice_proto_init() {
+	if File_hookservice_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_hookservice_proto_rawDesc), len(file_hookservice_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   5,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_hookservice_proto_goTypes,
+		DependencyIndexes: file_hookservice_proto_depIdxs,
+		MessageInfos:      file_hookservice_proto_msgTypes,
+	}.Build()
+	File_hookservice_proto = out.File
+	file_hookservice_proto_goTypes = nil
+	file_hookservice_proto_depIdxs = nil
+}
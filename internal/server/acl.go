@@ -0,0 +1,565 @@
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aiqia-dev/meridian/internal/glob"
+	"github.com/tidwall/resp"
+)
+
+// ACL users, in the manner of Redis.
+//
+//	ACL SETUSER username [rule ...]
+//	ACL DELUSER username [username ...]
+//	ACL LIST
+//	ACL WHOAMI
+//	AUTH username password
+//
+// The rules are on, off, >password, <password, #sha256, nopass, resetpass,
+// ~glob, allkeys, resetkeys, &glob, allchannels, resetchannels, +@category,
+// -@category, +command, -command, allcommands, nocommands and reset. The
+// categories are read, write, hooks, scripting and admin.
+//
+// The "default" user authenticates with the requirepass password and has all
+// permissions. The other users are persisted in the config file as their
+// ACL LIST lines.
+
+var errACLWrongPass = errors.New(
+	"WRONGPASS invalid username-password pair or user is disabled")
+
+var aclCategories = []string{"read", "write", "hooks", "scripting", "admin"}
+
+// aclUser is an ACL user. A user isn't changed once it's in the table, so
+// it's safe to use without locking.
+type aclUser struct {
+	name      string
+	on        bool
+	nopass    bool
+	passwords []string // sha256 hex
+	keys      []string // globs
+	channels  []string // pubsub channel globs
+	cmds      []string // command rules, in order
+}
+
+type aclTable struct {
+	mu    sync.RWMutex
+	users map[string]*aclUser
+}
+
+// aclCategory returns the category of a command, or an empty string for the
+// commands that every user can run.
+func aclCategory(msg *Message) string {
+	switch msg.Command() {
+	case "auth", "hello", "ping", "echo", "quit", "output", "healthz":
+		return ""
+	case "acl":
+		if len(msg.Args) > 1 && strings.ToLower(msg.Args[1]) == "whoami" {
+			return ""
+		}
+		return "admin"
+	case "get", "fget", "jget", "keys", "scan", "search", "nearby", "within",
+		"intersects", "bounds", "type", "exists", "fexists", "ttl", "stats",
+		"test":
+		return "read"
+	case "set", "fset", "del", "pdel", "drop", "rename", "renamenx",
+		"expire", "persist", "jset", "jdel", "flushdb":
+		return "write"
+	case "sethook", "delhook", "pdelhook", "hooks", "setchan", "delchan",
+		"pdelchan", "chans", "hookstats", "replay", "hookdlq", "hookpause",
+		"hookresume", "subscribe", "psubscribe", "unsubscribe", "punsubscribe",
+		"publish":
+		return "hooks"
+	case "eval", "evalro", "evalna", "evalsha", "evalrosha", "evalnasha",
+		"script":
+		return "scripting"
+	}
+	return "admin"
+}
+
+// aclCommandKeys returns the collections that a command accesses.
+func aclCommandKeys(msg *Message) []string {
+	args := msg.Args
+	// areaKeys returns the keys of the "GET key id" and "ROAM key pattern
+	// meters" areas.
+	areaKeys := func(args []string) []string {
+		var keys []string
+		for i := 0; i+1 < len(args); i++ {
+			switch strings.ToLower(args[i]) {
+			case "get", "roam":
+				keys = append(keys, args[i+1])
+			}
+		}
+		return keys
+	}
+	switch msg.Command() {
+	case "rename", "renamenx", "stats":
+		return args[1:]
+	case "sethook", "setchan":
+		for i := 2; i+1 < len(args); i++ {
+			switch strings.ToLower(args[i]) {
+			case "nearby", "within", "intersects":
+				return append([]string{args[i+1]}, areaKeys(args[i+2:])...)
+			}
+		}
+	case "test":
+		return areaKeys(args[1:])
+	case "nearby", "within", "intersects":
+		if len(args) > 1 {
+			return append([]string{args[1]}, areaKeys(args[2:])...)
+		}
+	case "get", "fget", "jget", "scan", "search", "bounds", "type", "exists",
+		"fexists", "ttl", "set", "fset", "del", "pdel", "drop", "expire",
+		"persist", "jset", "jdel":
+		if len(args) > 1 {
+			return args[1:2]
+		}
+	}
+	return nil
+}
+
+// aclCommandChannels returns the pubsub channels, or patterns, that a
+// command accesses.
+func aclCommandChannels(msg *Message) (channels []string, patterns bool) {
+	switch msg.Command() {
+	case "subscribe":
+		return msg.Args[1:], false
+	case "psubscribe":
+		return msg.Args[1:], true
+	case "publish":
+		if len(msg.Args) > 1 {
+			return msg.Args[1:2], false
+		}
+	}
+	return nil, false
+}
+
+// canRun returns true when the rules allow the command. The last matching
+// rule wins.
+func (u *aclUser) canRun(cmd, category string) bool {
+	var allowed bool
+	for _, rule := range u.cmds {
+		name := rule[1:]
+		if name == "@all" || name == "@"+category || name == cmd {
+			allowed = rule[0] == '+'
+		}
+	}
+	return allowed
+}
+
+// keyAllowed returns true when the key matches one of the globs. A nil user
+// is the default user.
+func (u *aclUser) keyAllowed(key string) bool {
+	if u == nil {
+		return true
+	}
+	for _, pattern := range u.keys {
+		if ok, _ := glob.Match(pattern, key); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// channelAllowed returns true when the channel matches one of the globs. A
+// pattern, as in PSUBSCRIBE, is only allowed when it's one of the globs, so
+// that it can't match other channels.
+func (u *aclUser) channelAllowed(channel string, pattern bool) bool {
+	for _, glb := range u.channels {
+		if glb == "*" || glb == channel {
+			return true
+		}
+		if !pattern {
+			if ok, _ := glob.Match(glb, channel); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ownsHook returns true when the user can see and change a hook, which is
+// when the user set the hook. A nil user is the default user, which owns
+// every hook.
+func (u *aclUser) ownsHook(hook *Hook) bool {
+	return u == nil || hook.owner == u.name
+}
+
+func (u *aclUser) allKeys() bool {
+	for _, pattern := range u.keys {
+		if pattern == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// authorize checks the permissions of a user for a command.
+func (u *aclUser) authorize(msg *Message) error {
+	category := aclCategory(msg)
+	if category == "" {
+		return nil
+	}
+	if !u.canRun(msg.Command(), category) {
+		return fmt.Errorf("NOPERM this user has no permissions to run "+
+			"the '%s' command", msg.Command())
+	}
+	if msg.Command() == "flushdb" && !u.allKeys() {
+		return errors.New("NOPERM this user has no permissions to access " +
+			"all keys")
+	}
+	for _, key := range aclCommandKeys(msg) {
+		if !u.keyAllowed(key) {
+			return errors.New("NOPERM this user has no permissions to " +
+				"access one of the keys used as arguments")
+		}
+	}
+	channels, patterns := aclCommandChannels(msg)
+	for _, channel := range channels {
+		if !u.channelAllowed(channel, patterns) {
+			return errors.New("NOPERM this user has no permissions to " +
+				"access one of the channels used as arguments")
+		}
+	}
+	return nil
+}
+
+func aclHash(password string) string {
+	sum := sha256.Sum256([]byte(password))
+	return hex.EncodeToString(sum[:])
+}
+
+// apply returns a copy of the user with the rules applied.
+func (u *aclUser) apply(rules []string) (*aclUser, error) {
+	nu := *u
+	nu.passwords = append([]string(nil), u.passwords...)
+	nu.keys = append([]string(nil), u.keys...)
+	nu.channels = append([]string(nil), u.channels...)
+	nu.cmds = append([]string(nil), u.cmds...)
+	removePassword := func(hash string) {
+		for i := 0; i < len(nu.passwords); i++ {
+			if nu.passwords[i] == hash {
+				nu.passwords = append(nu.passwords[:i], nu.passwords[i+1:]...)
+				i--
+			}
+		}
+	}
+	for _, rule := range rules {
+		if rule == "" {
+			return nil, errors.New("syntax error in ACL rule ''")
+		}
+		lrule := strings.ToLower(rule)
+		switch {
+		case lrule == "on":
+			nu.on = true
+		case lrule == "off":
+			nu.on = false
+		case lrule == "nopass":
+			nu.nopass = true
+			nu.passwords = nil
+		case lrule == "resetpass":
+			nu.nopass = false
+			nu.passwords = nil
+		case lrule == "allkeys":
+			nu.keys = []string{"*"}
+		case lrule == "resetkeys":
+			nu.keys = nil
+		case lrule == "allchannels":
+			nu.channels = []string{"*"}
+		case lrule == "resetchannels":
+			nu.channels = nil
+		case lrule == "allcommands":
+			nu.cmds = []string{"+@all"}
+		case lrule == "nocommands":
+			nu.cmds = nil
+		case lrule == "reset":
+			nu = aclUser{name: nu.name}
+		case rule[0] == '>':
+			hash := aclHash(rule[1:])
+			removePassword(hash)
+			nu.passwords = append(nu.passwords, hash)
+			nu.nopass = false
+		case rule[0] == '<':
+			removePassword(aclHash(rule[1:]))
+		case rule[0] == '#':
+			hash := strings.ToLower(rule[1:])
+			if _, err := hex.DecodeString(hash); err != nil ||
+				len(hash) != sha256.Size*2 {
+				return nil, fmt.Errorf("invalid password hash '%s'", rule)
+			}
+			removePassword(hash)
+			nu.passwords = append(nu.passwords, hash)
+			nu.nopass = false
+		case rule[0] == '~':
+			if len(rule) == 1 {
+				return nil, errInvalidArgument(rule)
+			}
+			nu.keys = append(nu.keys, rule[1:])
+		case rule[0] == '&':
+			if len(rule) == 1 {
+				return nil, errInvalidArgument(rule)
+			}
+			nu.channels = append(nu.channels, rule[1:])
+		case rule[0] == '+' || rule[0] == '-':
+			name := lrule[1:]
+			if strings.HasPrefix(name, "@") {
+				valid := name == "@all"
+				for _, category := range aclCategories {
+					valid = valid || name == "@"+category
+				}
+				if !valid {
+					return nil, fmt.Errorf("unknown command category '%s'",
+						name[1:])
+				}
+			} else if name == "" {
+				return nil, errInvalidArgument(rule)
+			}
+			if name == "@all" {
+				nu.cmds = nil
+			}
+			nu.cmds = append(nu.cmds, lrule)
+		default:
+			return nil, fmt.Errorf("syntax error in ACL rule '%s'", rule)
+		}
+	}
+	return &nu, nil
+}
+
+// String returns the user as an ACL LIST line.
+func (u *aclUser) String() string {
+	parts := []string{"user", u.name}
+	if u.on {
+		parts = append(parts, "on")
+	} else {
+		parts = append(parts, "off")
+	}
+	if u.nopass {
+		parts = append(parts, "nopass")
+	}
+	for _, hash := range u.passwords {
+		parts = append(parts, "#"+hash)
+	}
+	if len(u.keys) == 0 {
+		parts = append(parts, "resetkeys")
+	}
+	for _, pattern := range u.keys {
+		parts = append(parts, "~"+pattern)
+	}
+	for _, pattern := range u.channels {
+		parts = append(parts, "&"+pattern)
+	}
+	if len(u.cmds) == 0 {
+		parts = append(parts, "-@all")
+	}
+	parts = append(parts, u.cmds...)
+	return strings.Join(parts, " ")
+}
+
+// load loads the users from their ACL LIST lines.
+func (t *aclTable) load(lines []string) error {
+	users := make(map[string]*aclUser)
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[0] != "user" {
+			return fmt.Errorf("invalid acl user '%s'", line)
+		}
+		u, err := (&aclUser{name: fields[1]}).apply(fields[2:])
+		if err != nil {
+			return fmt.Errorf("invalid acl user '%s': %w", line, err)
+		}
+		users[u.name] = u
+	}
+	t.mu.Lock()
+	t.users = users
+	t.mu.Unlock()
+	return nil
+}
+
+func (t *aclTable) user(name string) *aclUser {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.users[name]
+}
+
+// lines returns the ACL LIST lines of the users, sorted by name.
+func (t *aclTable) lines() []string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	var lines []string
+	for _, u := range t.users {
+		lines = append(lines, u.String())
+	}
+	sort.Strings(lines)
+	return lines
+}
+
+// aclCredentials returns the username and password of an "AUTH username
+// password" command, or of an HTTP Basic authorization header.
+func aclCredentials(msg *Message) (username, password string, ok bool) {
+	if msg.Command() == "auth" && len(msg.Args) == 3 {
+		return msg.Args[1], msg.Args[2], true
+	}
+	if len(msg.Auth) > 6 && strings.EqualFold(msg.Auth[:6], "basic ") {
+		data, err := base64.StdEncoding.DecodeString(msg.Auth[6:])
+		if err != nil {
+			return "", "", false
+		}
+		username, password, ok = strings.Cut(string(data), ":")
+		return username, password, ok
+	}
+	return "", "", false
+}
+
+// aclAuth authenticates a client as a user.
+func (s *Server) aclAuth(client *Client, username, password string) error {
+	if username == "default" {
+		if requirePass := s.config.requirePass(); requirePass != "" &&
+			requirePass != strings.TrimSpace(password) {
+			return errACLWrongPass
+		}
+		client.authd = true
+		client.user = ""
+		return nil
+	}
+	u := s.acl.user(username)
+	if u == nil || !u.on {
+		return errACLWrongPass
+	}
+	if !u.nopass {
+		hash := aclHash(password)
+		var ok bool
+		for _, p := range u.passwords {
+			ok = ok || p == hash
+		}
+		if !ok {
+			return errACLWrongPass
+		}
+	}
+	client.authd = true
+	client.user = username
+	return nil
+}
+
+// aclAuthorize checks the permissions of the client user for a command, and
+// returns the user. The user is nil for the default user.
+func (s *Server) aclAuthorize(client *Client, msg *Message) (*aclUser, error) {
+	if client.user == "" {
+		return nil, nil
+	}
+	if aclCategory(msg) == "" {
+		return nil, nil
+	}
+	u := s.acl.user(client.user)
+	if u == nil || !u.on {
+		return nil, errors.New("NOPERM the user is disabled or deleted")
+	}
+	return u, u.authorize(msg)
+}
+
+// ACL SETUSER|DELUSER|LIST|WHOAMI
+func (s *Server) cmdACL(msg *Message, client *Client) (resp.Value, error) {
+	start := time.Now()
+	args := msg.Args
+	if len(args) < 2 {
+		return retrerr(errInvalidNumberOfArguments)
+	}
+	switch strings.ToLower(args[1]) {
+	case "setuser":
+		if len(args) < 3 {
+			return retrerr(errInvalidNumberOfArguments)
+		}
+		name := args[2]
+		if name == "default" {
+			return retrerr(errors.New(
+				"the default user is configured with requirepass"))
+		}
+		for i := 0; i < len(name); i++ {
+			if name[i] <= ' ' || name[i] > '~' {
+				return retrerr(errInvalidArgument(name))
+			}
+		}
+		s.acl.mu.Lock()
+		u := s.acl.users[name]
+		if u == nil {
+			u = &aclUser{name: name}
+		}
+		u, err := u.apply(args[3:])
+		if err == nil {
+			if s.acl.users == nil {
+				s.acl.users = make(map[string]*aclUser)
+			}
+			s.acl.users[name] = u
+		}
+		s.acl.mu.Unlock()
+		if err != nil {
+			return retrerr(err)
+		}
+		s.config.setACLUsers(s.acl.lines())
+		s.config.write(false)
+		return OKMessage(msg, start), nil
+	case "deluser":
+		if len(args) < 3 {
+			return retrerr(errInvalidNumberOfArguments)
+		}
+		var n int
+		s.acl.mu.Lock()
+		for _, name := range args[2:] {
+			if _, ok := s.acl.users[name]; ok {
+				delete(s.acl.users, name)
+				n++
+			}
+		}
+		s.acl.mu.Unlock()
+		if n > 0 {
+			s.config.setACLUsers(s.acl.lines())
+			s.config.write(false)
+		}
+		if msg.OutputType == JSON {
+			return resp.StringValue(fmt.Sprintf(`{"ok":true,"deleted":%d,`+
+				`"elapsed":"%s"}`, n, time.Since(start))), nil
+		}
+		return resp.IntegerValue(n), nil
+	case "list":
+		if len(args) != 2 {
+			return retrerr(errInvalidNumberOfArguments)
+		}
+		lines := append([]string{"user default on nopass ~* +@all"},
+			s.acl.lines()...)
+		if s.config.requirePass() != "" {
+			lines[0] = "user default on #" + aclHash(s.config.requirePass()) +
+				" ~* +@all"
+		}
+		if msg.OutputType == JSON {
+			data, _ := json.Marshal(lines)
+			return resp.StringValue(`{"ok":true,"users":` + string(data) +
+				`,"elapsed":"` + time.Since(start).String() + `"}`), nil
+		}
+		vals := make([]resp.Value, len(lines))
+		for i, line := range lines {
+			vals[i] = resp.StringValue(line)
+		}
+		return resp.ArrayValue(vals), nil
+	case "whoami":
+		if len(args) != 2 {
+			return retrerr(errInvalidNumberOfArguments)
+		}
+		name := client.user
+		if name == "" {
+			name = "default"
+		}
+		if msg.OutputType == JSON {
+			return resp.StringValue(`{"ok":true,"user":` + jsonString(name) +
+				`,"elapsed":"` + time.Since(start).String() + `"}`), nil
+		}
+		return resp.StringValue(name), nil
+	}
+	return retrerr(errInvalidArgument(args[1]))
+}
@@ -150,6 +150,9 @@ func (s *Server) writeAOF(args []string, d *commandDetails) error {
 		// just ignore writes if the command did not update
 		return nil
 	}
+	if d != nil && d.aofArgs != nil {
+		args = d.aofArgs
+	}
 
 	if s.shrinking {
 		nargs := make([]string, len(args))
@@ -169,6 +172,15 @@ func (s *Server) writeAOF(args []string, d *commandDetails) error {
 
 	// process geofences
 	if d != nil {
+		// cached tiles
+		s.invalidateTiles(d)
+
+		// keyspace notifications
+		s.notifyKeyspace(args, d)
+
+		// stale object timers
+		s.updateStaleTimers(d)
+
 		// webhook geofences
 		if s.config.followHost() == "" {
 			// for leader only
@@ -274,25 +286,58 @@ func (s *Server) getQueueCandidates(d *commandDetails) []*Hook {
 }
 
 func (s *Server) queueHooks(d *commandDetails) error {
-	// Create the slices that will store all messages and hooks
-	var cmsgs, wmsgs []string
-	var whooks []*Hook
-
 	// Compile a slice of potential hook recipients
+	var q hookMsgs
 	candidates := s.getQueueCandidates(d)
 	for _, hook := range candidates {
+		if !hook.scheduleActive(d.timestamp) {
+			// outside of the schedule window
+			continue
+		}
 		// Calculate all matching fence messages for all candidates and append
 		// them to the appropriate message slice
-		msgs := FenceMatch(hook.Name, hook.ScanWriter, hook.Fence, hook.Metas, d)
-		if len(msgs) > 0 {
-			if hook.channel {
-				cmsgs = append(cmsgs, msgs...)
-			} else {
-				wmsgs = append(wmsgs, msgs...)
-				whooks = append(whooks, hook)
-			}
+		q.add(hook, FenceMatch(hook.Name, hook.ScanWriter, hook.Fence,
+			hook.Metas, d))
+	}
+	return s.queueHookMsgs(&q)
+}
+
+// hookMsgs are the fence messages of channels and webhooks, waiting to be
+// queued.
+type hookMsgs struct {
+	cmsgs, wmsgs []string
+	whooks       []*Hook
+	wcounts      []int
+	wttls        map[string]time.Duration
+}
+
+// add adds the messages of a hook.
+func (q *hookMsgs) add(hook *Hook, msgs []string) {
+	if len(msgs) == 0 {
+		return
+	}
+	if hook.channel {
+		q.cmsgs = append(q.cmsgs, msgs...)
+		return
+	}
+	if hook.pause.paused {
+		if msgs = hook.pause.admit(msgs); len(msgs) == 0 {
+			return
 		}
 	}
+	if q.wttls == nil {
+		q.wttls = make(map[string]time.Duration)
+	}
+	q.wmsgs = append(q.wmsgs, msgs...)
+	q.whooks = append(q.whooks, hook)
+	q.wcounts = append(q.wcounts, len(msgs))
+	q.wttls[hook.Name] = hook.queueTTL()
+}
+
+// queueHookMsgs publishes the channel messages, and queues the webhook
+// messages.
+func (s *Server) queueHookMsgs(q *hookMsgs) error {
+	cmsgs, wmsgs := q.cmsgs, q.wmsgs
 
 	// Return nil if there are no messages to be sent
 	if len(cmsgs)+len(wmsgs) == 0 {
@@ -307,6 +352,15 @@ func (s *Server) queueHooks(d *commandDetails) error {
 		sortMsgs(wmsgs)
 	}
 
+	// Append the messages to the event log
+	var err error
+	if cmsgs, err = s.appendEventLog(cmsgs); err != nil {
+		return err
+	}
+	if wmsgs, err = s.appendEventLog(wmsgs); err != nil {
+		return err
+	}
+
 	// Publish all channel messages if any exist
 	if len(cmsgs) > 0 {
 		for _, m := range cmsgs {
@@ -315,11 +369,12 @@ func (s *Server) queueHooks(d *commandDetails) error {
 	}
 
 	// Queue the webhook messages in the buntdb database
-	err := s.qdb.Update(func(tx *buntdb.Tx) error {
+	err = s.qdb.Update(func(tx *buntdb.Tx) error {
 		for _, msg := range wmsgs {
 			s.qidx++ // increment the log id
 			key := hookLogPrefix + uint64ToString(s.qidx)
-			_, _, err := tx.Set(key, msg, hookLogSetDefaults)
+			opts := hookQueueOptions(q.wttls[gjson.Get(msg, "hook").String()])
+			_, _, err := tx.Set(key, msg, opts)
 			if err != nil {
 				return err
 			}
@@ -336,8 +391,8 @@ func (s *Server) queueHooks(d *commandDetails) error {
 	}
 	// all the messages have been queued.
 	// notify the hooks
-	for _, hook := range whooks {
-		hook.Signal()
+	for i, hook := range q.whooks {
+		hook.signalQueued(q.wcounts[i])
 	}
 	return nil
 }
@@ -198,6 +198,23 @@ func (s *Server) aofshrink() {
 					values = append(values, "ex",
 						strconv.FormatFloat(ex, 'f', 1, 64))
 				}
+				values = append(values, hook.retry.args()...)
+				values = append(values, hook.batch.args()...)
+				for _, secret := range hook.secrets {
+					values = append(values, "secret", secret)
+				}
+				if hook.owner != "" {
+					values = append(values, "owner", hook.owner)
+				}
+				if hook.stale > 0 {
+					values = append(values, "stale", formatSeconds(hook.stale))
+					if hook.staleExit {
+						values = append(values, "exit")
+					}
+				}
+				if hook.schedule != nil {
+					values = append(values, hook.schedule.args()...)
+				}
 				values = append(values, hook.Message.Args...)
 				// append the values to the aof buffer
 				aofbuf = append(aofbuf, '*')
@@ -210,7 +227,66 @@ func (s *Server) aofshrink() {
 					aofbuf = append(aofbuf, value...)
 					aofbuf = append(aofbuf, '\r', '\n')
 				}
+				if values := hook.pause.args(name); len(values) > 0 {
+					aofbuf = append(aofbuf, '*')
+					aofbuf = append(aofbuf, strconv.FormatInt(int64(len(values)), 10)...)
+					aofbuf = append(aofbuf, '\r', '\n')
+					for _, value := range values {
+						aofbuf = append(aofbuf, '$')
+						aofbuf = append(aofbuf, strconv.FormatInt(int64(len(value)), 10)...)
+						aofbuf = append(aofbuf, '\r', '\n')
+						aofbuf = append(aofbuf, value...)
+						aofbuf = append(aofbuf, '\r', '\n')
+					}
+				}
+			}()
+		}
+
+		// load the groups of the hooks, in chunks
+		var pivot *groupItem
+		for {
+			var groups [][]string
+			func() {
+				s.mu.Lock()
+				defer s.mu.Unlock()
+				var from interface{}
+				if pivot != nil {
+					from = pivot
+				}
+				s.groupHooks.Ascend(from, func(v interface{}) bool {
+					g := v.(*groupItem)
+					if pivot != nil && !byGroupHook(pivot, g) {
+						return true
+					}
+					if g.hookName != "" {
+						groups = append(groups, []string{"setgroup",
+							g.hookName, g.colKey, g.objID, g.groupID})
+					}
+					pivot = g
+					return len(groups) < maxids*maxkeys
+				})
 			}()
+			if len(groups) == 0 {
+				break
+			}
+			for _, values := range groups {
+				aofbuf = append(aofbuf, '*')
+				aofbuf = append(aofbuf, strconv.FormatInt(int64(len(values)), 10)...)
+				aofbuf = append(aofbuf, '\r', '\n')
+				for _, value := range values {
+					aofbuf = append(aofbuf, '$')
+					aofbuf = append(aofbuf, strconv.FormatInt(int64(len(value)), 10)...)
+					aofbuf = append(aofbuf, '\r', '\n')
+					aofbuf = append(aofbuf, value...)
+					aofbuf = append(aofbuf, '\r', '\n')
+				}
+			}
+			if len(aofbuf) > maxchunk {
+				if _, err := f.Write(aofbuf); err != nil {
+					return err
+				}
+				aofbuf = aofbuf[:0]
+			}
 		}
 		if len(aofbuf) > 0 {
 			if _, err := f.Write(aofbuf); err != nil {
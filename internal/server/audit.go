@@ -0,0 +1,385 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aiqia-dev/meridian/internal/admin"
+	"github.com/aiqia-dev/meridian/internal/log"
+)
+
+// The audit log records who changed what: the writes, the hook changes, the
+// administrative commands and the admin panel API calls. Each entry is a
+// JSON line, appended to the auditlog file, which is rotated once it's
+// larger than auditlog-maxsize, and forwarded to the auditlog-endpoint.
+
+// maxAuditQueue is the max number of entries waiting to be forwarded. The
+// oldest entries are dropped when the endpoint can't keep up.
+const maxAuditQueue = 10000
+
+type auditEntry struct {
+	Time  string   `json:"time"`
+	Addr  string   `json:"addr"`
+	User  string   `json:"user"`
+	Conn  string   `json:"conn"`
+	Cmd   string   `json:"cmd"`
+	Key   string   `json:"key,omitempty"`
+	ID    string   `json:"id,omitempty"`
+	Args  []string `json:"args,omitempty"`
+	OK    bool     `json:"ok"`
+	Error string   `json:"err,omitempty"`
+}
+
+type auditLog struct {
+	mu   sync.Mutex
+	path string // path of the open file
+	file *os.File
+	size int64
+
+	cond    *sync.Cond
+	entries []auditQueueEntry // forward queue
+	dropped int
+	closed  bool
+}
+
+type auditQueueEntry struct {
+	endpoint string
+	entry    string
+}
+
+// auditName returns the name of a command and its arguments. The CONFIG,
+// ACL, CLIENT and RATELIMIT subcommands are part of the name, before and
+// after CONFIG is rewritten into "config set".
+func auditName(msg *Message) (cmd string, args []string) {
+	cmd, args = msg.Command(), msg.Args[1:]
+	switch cmd {
+	case "config", "acl", "client", "ratelimit":
+		if len(args) > 0 {
+			cmd += " " + strings.ToLower(args[0])
+			args = args[1:]
+		}
+	}
+	return cmd, args
+}
+
+// auditCommand returns true for the commands that are audited. All the
+// commands of the admin panel are audited.
+func auditCommand(msg *Message, cmd string, args []string) bool {
+	if msg.admin != "" {
+		return true
+	}
+	switch cmd {
+	case "set", "fset", "del", "pdel", "drop", "rename", "renamenx", "expire",
+		"persist", "jset", "jdel", "flushdb",
+		"sethook", "delhook", "pdelhook", "setchan", "delchan", "pdelchan",
+		"hookpause", "hookresume", "config set", "config rewrite", "acl setuser", "acl deluser",
+		"client kill", "ratelimit set", "ratelimit del", "follow", "slaveof",
+		"readonly", "aofshrink", "shutdown", "eval", "evalsha", "evalna",
+		"evalnasha":
+		return true
+	case "hookdlq":
+		// REPLAY and PURGE
+		return len(args) > 1
+	}
+	return false
+}
+
+// auditArgs returns the key, the id and the arguments of an entry. The
+// object data of SET, FSET and JSET isn't recorded, and the secrets are
+// redacted.
+func auditArgs(msg *Message, cmd string, args []string) (key, id string,
+	_ []string,
+) {
+	switch cmd {
+	case "set", "fset", "jset":
+		if len(args) > 0 {
+			key = args[0]
+		}
+		if len(args) > 1 {
+			id = args[1]
+		}
+		return key, id, nil
+	case "del", "expire", "persist", "jdel":
+		if len(args) > 1 {
+			id = args[1]
+		}
+	case "sethook", "setchan":
+		args = redactHookArgs(msg.Args)[1:]
+		if len(args) > 1 && cmd == "sethook" {
+			args[1] = redactEndpoints(args[1])
+		}
+		// the hook name is the key
+		if len(args) > 0 {
+			key = args[0]
+		}
+		return key, "", args
+	case "delhook", "pdelhook", "delchan", "pdelchan", "hookdlq", "hookpause",
+		"hookresume":
+		if len(args) > 0 {
+			key = args[0]
+		}
+		return key, "", args
+	case "config set":
+		if len(args) > 1 {
+			switch strings.ToLower(args[0]) {
+			case RequirePass, LeaderAuth:
+				args = append([]string(nil), args...)
+				args[1] = redactedSecret
+			case AuditLogEndpoint:
+				args = append([]string(nil), args...)
+				args[1] = redactEndpoints(args[1])
+			}
+		}
+		return "", "", args
+	case "acl setuser":
+		args = append([]string(nil), args...)
+		for i := 1; i < len(args); i++ {
+			if args[i] != "" && strings.IndexByte("><#", args[i][0]) != -1 {
+				args[i] = args[i][:1] + redactedSecret
+			}
+		}
+		return "", "", args
+	}
+	if keys := aclCommandKeys(msg); len(keys) > 0 {
+		key = keys[0]
+	}
+	return key, id, args
+}
+
+// redactEndpoints redacts the passwords of comma separated endpoint urls.
+func redactEndpoints(endpoints string) string {
+	parts := strings.Split(endpoints, ",")
+	for i, part := range parts {
+		j := strings.Index(part, "://")
+		if j == -1 {
+			continue
+		}
+		rest := part[j+3:]
+		if end := strings.IndexAny(rest, "/?#"); end != -1 {
+			rest = rest[:end]
+		}
+		at := strings.LastIndexByte(rest, '@')
+		if at == -1 {
+			continue
+		}
+		if colon := strings.IndexByte(rest[:at], ':'); colon != -1 {
+			parts[i] = part[:j+3+colon+1] + redactedSecret + part[j+3+at:]
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+func connTypeString(t Type) string {
+	switch t {
+	case RESP:
+		return "resp"
+	case Telnet:
+		return "telnet"
+	case Native:
+		return "native"
+	case HTTP:
+		return "http"
+	case WebSocket:
+		return "websocket"
+	case GRPC:
+		return "grpc"
+	}
+	return "unknown"
+}
+
+// auditCmd records a command in the audit log, when it's audited.
+func (s *Server) auditCmd(client *Client, msg *Message, err error) {
+	cmd, args := auditName(msg)
+	if !auditCommand(msg, cmd, args) {
+		return
+	}
+	key, id, args := auditArgs(msg, cmd, args)
+	user := client.user
+	if msg.admin != "" {
+		user = "admin:" + msg.admin
+	} else if user == "" {
+		user = "default"
+	}
+	entry := auditEntry{
+		Addr: client.remoteAddr,
+		User: user,
+		Conn: connTypeString(msg.ConnType),
+		Cmd:  cmd,
+		Key:  key,
+		ID:   id,
+		Args: args,
+		OK:   err == nil,
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	s.audit(entry)
+}
+
+// auditAdminLogin records a login to the admin panel.
+func (s *Server) auditAdminLogin(client *Client, msg *Message,
+	config admin.Config,
+) {
+	var req struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+	}
+	json.Unmarshal(msg.Body, &req)
+	var err error
+	if config.Username == "" || config.Password == "" {
+		err = errors.New("admin panel not configured")
+	} else if !admin.ValidateCredentials(req.Username, req.Password, config) {
+		err = errors.New("invalid credentials")
+	}
+	entry := auditEntry{
+		Addr: client.remoteAddr,
+		User: "admin:" + req.Username,
+		Conn: connTypeString(msg.ConnType),
+		Cmd:  "admin login",
+		OK:   err == nil,
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	s.audit(entry)
+}
+
+// audit appends an entry to the audit log, and queues it for the endpoint.
+func (s *Server) audit(entry auditEntry) {
+	path := s.config.auditLog()
+	endpoint := s.config.auditLogEndpoint()
+	if path == "" && endpoint == "" {
+		return
+	}
+	entry.Time = time.Now().UTC().Format(time.RFC3339Nano)
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(entry); err != nil {
+		log.Errorf("audit log: %v", err)
+		return
+	}
+	data := bytes.TrimSuffix(buf.Bytes(), []byte{'\n'})
+	if path != "" {
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(s.dir, path)
+		}
+		err := s.auditlog.write(path, append(data, '\n'),
+			s.config.auditLogMaxSize(), s.config.auditLogMaxFiles())
+		if err != nil {
+			log.Errorf("audit log: %v", err)
+		}
+	}
+	if endpoint != "" {
+		s.auditlog.cond.L.Lock()
+		if len(s.auditlog.entries) == maxAuditQueue {
+			s.auditlog.entries = s.auditlog.entries[1:]
+			s.auditlog.dropped++
+		}
+		s.auditlog.entries = append(s.auditlog.entries,
+			auditQueueEntry{endpoint: endpoint, entry: string(data)})
+		s.auditlog.cond.Signal()
+		s.auditlog.cond.L.Unlock()
+	}
+}
+
+// write appends a line to the file, and rotates the file once it's larger
+// than maxSize. The rotated files are path.1, path.2, up to maxFiles.
+func (al *auditLog) write(path string, line []byte, maxSize int64,
+	maxFiles int,
+) error {
+	al.mu.Lock()
+	defer al.mu.Unlock()
+	if al.file != nil && al.path != path {
+		al.file.Close()
+		al.file = nil
+	}
+	if al.file == nil {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		if err != nil {
+			return err
+		}
+		fi, err := f.Stat()
+		if err != nil {
+			f.Close()
+			return err
+		}
+		al.file, al.path, al.size = f, path, fi.Size()
+	}
+	n, err := al.file.Write(line)
+	al.size += int64(n)
+	if err != nil {
+		return err
+	}
+	if maxSize <= 0 || al.size < maxSize {
+		return nil
+	}
+	al.file.Close()
+	al.file = nil
+	if maxFiles <= 0 {
+		return os.Remove(path)
+	}
+	for i := maxFiles - 1; i > 0; i-- {
+		old := fmt.Sprintf("%s.%d", path, i)
+		if _, err := os.Stat(old); err == nil {
+			if err := os.Rename(old, fmt.Sprintf("%s.%d", path, i+1)); err != nil {
+				return err
+			}
+		}
+	}
+	return os.Rename(path, path+".1")
+}
+
+// close closes the file.
+func (al *auditLog) close() {
+	al.mu.Lock()
+	if al.file != nil {
+		al.file.Close()
+		al.file = nil
+	}
+	al.mu.Unlock()
+}
+
+// forwardAuditLog sends the queued entries to the audit log endpoint.
+func (s *Server) forwardAuditLog(wg *sync.WaitGroup) {
+	defer wg.Done()
+	al := &s.auditlog
+	al.cond.L.Lock()
+	for {
+		if al.dropped > 0 {
+			log.Warnf("audit log: dropped %d entries, the endpoint is "+
+				"too slow", al.dropped)
+			al.dropped = 0
+		}
+		for len(al.entries) > 0 {
+			entry := al.entries[0]
+			al.entries = al.entries[1:]
+			al.cond.L.Unlock()
+			if err := s.epc.Send(entry.endpoint, entry.entry); err != nil {
+				log.Errorf("audit log: %s: %v", entry.endpoint, err)
+			}
+			al.cond.L.Lock()
+		}
+		if al.closed {
+			break
+		}
+		al.cond.Wait()
+	}
+	al.cond.L.Unlock()
+	al.close()
+}
+
+// stopAuditLog stops forwarding the entries, and closes the file.
+func (s *Server) stopAuditLog() {
+	s.auditlog.cond.L.Lock()
+	s.auditlog.closed = true
+	s.auditlog.cond.Broadcast()
+	s.auditlog.cond.L.Unlock()
+}
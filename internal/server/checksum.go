@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"time"
 
 	"github.com/tidwall/resp"
 	"github.com/aiqia-dev/meridian/internal/log"
@@ -151,7 +150,7 @@ func (s *Server) followCheckSome(addr string, followc int, auth string,
 		return 0, nil
 	}
 
-	conn, err := DialTimeout(addr, time.Second*2)
+	conn, err := s.dialLeader(addr)
 	if err != nil {
 		return 0, err
 	}
@@ -18,8 +18,10 @@ type Client struct {
 	replPort   int            // the known replication port for follower connections
 	replAddr   string         // the known replication addr for follower connections
 	authd      bool           // client has been authenticated
+	user       string         // ACL user, empty for the default user
 	outputType Type           // Null, JSON, or RESP
 	strictRESP bool           // client is in strict RESP mode
+	resp3      bool           // client negotiated RESP3 with HELLO
 	remoteAddr string         // original remote address
 	in         InputStream    // input stream
 	pr         PipelineReader // command reader
@@ -35,6 +37,7 @@ type Client struct {
 	last   time.Time          // last client request/response, unix nano
 
 	closer io.Closer // used to close the connection
+	push   *pushConn // push frames of a RESP3 client
 }
 
 // Write ...
@@ -0,0 +1,179 @@
+package server
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/aiqia-dev/meridian/internal/field"
+	"github.com/aiqia-dev/meridian/internal/object"
+	"github.com/tidwall/geojson"
+	"github.com/tidwall/geojson/geometry"
+	"github.com/tidwall/resp"
+)
+
+// Point clustering for the CLUSTER output, in the manner of supercluster.
+//
+//	WITHIN key CLUSTER radius zoom BOUNDS ...
+//
+// The objects are clustered in pixels at a zoom level, in the single pass
+// over the spatial index that the search already does. Each object joins the
+// nearest cluster whose seed is within the radius, or it becomes the seed of
+// a new cluster.
+
+const clusterMaxZoom = 24
+
+type cluster struct {
+	x, y    float64 // seed, in world pixels
+	lat     float64 // sum of the member latitudes
+	lon     float64 // sum of the member longitudes
+	count   int
+	maxDist float64 // farthest member from the seed, in pixels
+	id      string  // single member id
+	fields  field.List
+}
+
+// point returns the centroid of the cluster.
+func (c *cluster) point() geometry.Point {
+	return geometry.Point{
+		X: c.lon / float64(c.count),
+		Y: c.lat / float64(c.count),
+	}
+}
+
+// expansionZoom returns the zoom from which the members no longer fit in the
+// radius of the cluster.
+func (c *cluster) expansionZoom(radius float64, zoom int) int {
+	if c.maxDist == 0 {
+		return clusterMaxZoom
+	}
+	z := zoom + int(math.Floor(math.Log2(radius/c.maxDist))) + 1
+	if z > clusterMaxZoom {
+		return clusterMaxZoom
+	}
+	return z
+}
+
+type clusterer struct {
+	radius   float64 // pixels
+	zoom     int
+	cells    map[[2]int][]*cluster
+	clusters []*cluster
+}
+
+func newClusterer(radius float64, zoom int) *clusterer {
+	return &clusterer{
+		radius: radius,
+		zoom:   zoom,
+		cells:  make(map[[2]int][]*cluster),
+	}
+}
+
+// clusterPixel returns the web mercator position of a point in pixels, for
+// a world of 256 pixels at zoom 0.
+func clusterPixel(p geometry.Point, zoom int) (x, y float64) {
+	size := 256 * math.Exp2(float64(zoom))
+	lat := math.Max(math.Min(p.Y, 85.0511287798), -85.0511287798)
+	sin := math.Sin(lat * math.Pi / 180)
+	x = (p.X + 180) / 360 * size
+	y = (0.5 - math.Log((1+sin)/(1-sin))/(4*math.Pi)) * size
+	return x, y
+}
+
+func (cr *clusterer) add(o *object.Object) {
+	p := o.Geo().Center()
+	x, y := clusterPixel(p, cr.zoom)
+	cx, cy := int(math.Floor(x/cr.radius)), int(math.Floor(y/cr.radius))
+	var nearest *cluster
+	var nearestDist float64
+	for i := cx - 1; i <= cx+1; i++ {
+		for j := cy - 1; j <= cy+1; j++ {
+			for _, c := range cr.cells[[2]int{i, j}] {
+				d := math.Hypot(x-c.x, y-c.y)
+				if d <= cr.radius && (nearest == nil || d < nearestDist) {
+					nearest, nearestDist = c, d
+				}
+			}
+		}
+	}
+	if nearest == nil {
+		c := &cluster{x: x, y: y, lat: p.Y, lon: p.X, count: 1, id: o.ID(),
+			fields: o.Fields()}
+		cell := [2]int{cx, cy}
+		cr.cells[cell] = append(cr.cells[cell], c)
+		cr.clusters = append(cr.clusters, c)
+		return
+	}
+	nearest.lat += p.Y
+	nearest.lon += p.X
+	nearest.count++
+	nearest.maxDist = math.Max(nearest.maxDist, nearestDist)
+	nearest.id = ""
+	nearest.fields = field.List{}
+}
+
+// mvtObjs returns the clusters as tile points.
+func (cr *clusterer) mvtObjs() []mvtObj {
+	objs := make([]mvtObj, len(cr.clusters))
+	for i, c := range cr.clusters {
+		var mc *mvtCluster
+		if c.count > 1 {
+			mc = &mvtCluster{
+				count:         uint64(c.count),
+				expansionZoom: uint64(c.expansionZoom(cr.radius, cr.zoom)),
+			}
+		}
+		objs[i] = mvtObj{c.id, geojson.NewSimplePoint(c.point()), c.fields, mc}
+	}
+	return objs
+}
+
+// appendJSON appends the clusters as a JSON array. A single object keeps its
+// id, and a cluster has the zoom at which it expands.
+func (cr *clusterer) appendJSON(dst []byte) []byte {
+	dst = append(dst, '[')
+	for i, c := range cr.clusters {
+		if i > 0 {
+			dst = append(dst, ',')
+		}
+		dst = append(dst, '{')
+		if c.count == 1 {
+			dst = append(dst, `"id":`...)
+			dst = append(dst, jsonString(c.id)...)
+			dst = append(dst, ',')
+		}
+		dst = append(dst, `"point":`...)
+		dst = appendJSONSimplePoint(dst, geojson.NewSimplePoint(c.point()))
+		dst = append(dst, `,"count":`...)
+		dst = strconv.AppendInt(dst, int64(c.count), 10)
+		if c.count > 1 {
+			dst = append(dst, `,"expansion_zoom":`...)
+			dst = strconv.AppendInt(dst,
+				int64(c.expansionZoom(cr.radius, cr.zoom)), 10)
+		}
+		dst = append(dst, '}')
+	}
+	return append(dst, ']')
+}
+
+// respValues returns the clusters as [id, [lat, lon], count, zoom] arrays.
+// The id is empty for a cluster, and the zoom is zero for a single object.
+func (cr *clusterer) respValues() []resp.Value {
+	vals := make([]resp.Value, len(cr.clusters))
+	for i, c := range cr.clusters {
+		p := c.point()
+		var zoom int
+		if c.count > 1 {
+			zoom = c.expansionZoom(cr.radius, cr.zoom)
+		}
+		vals[i] = resp.ArrayValue([]resp.Value{
+			resp.StringValue(c.id),
+			resp.ArrayValue([]resp.Value{
+				resp.FloatValue(p.Y),
+				resp.FloatValue(p.X),
+			}),
+			resp.IntegerValue(c.count),
+			resp.IntegerValue(zoom),
+		})
+	}
+	return vals
+}
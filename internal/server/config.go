@@ -3,6 +3,7 @@ package server
 import (
 	"encoding/json"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"sync"
@@ -14,8 +15,15 @@ import (
 )
 
 const (
-	defaultKeepAlive     = 300 // seconds
+	defaultKeepAlive     = 300  // seconds
+	defaultTileCacheSize = 1000 // tiles
 	defaultProtectedMode = "yes"
+
+	defaultAuditLogMaxSize  = 64 * 1024 * 1024 // bytes
+	defaultAuditLogMaxFiles = 5
+
+	defaultSlowLogSlowerThan = 10000 // microseconds
+	defaultSlowLogMaxLen     = 128
 )
 
 // Config keys
@@ -36,9 +44,29 @@ const (
 	LogConfig       = "logconfig"
 	AnnounceIP      = "replica_announce_ip"
 	AnnouncePort    = "replica_announce_port"
+	EventLogMaxSize = "eventlog-maxsize"
+	EventLogMaxAge  = "eventlog-maxage"
+	TileCacheSize   = "tilecache-size"
+	ACLUsers        = "acl_users"
+	RateLimits      = "rate_limits"
+
+	CollectionMaxObjects = "collection-maxobjects"
+	UserMaxHooks         = "user-maxhooks"
+
+	AuditLog         = "auditlog"
+	AuditLogMaxSize  = "auditlog-maxsize"
+	AuditLogMaxFiles = "auditlog-maxfiles"
+	AuditLogEndpoint = "auditlog-endpoint"
+
+	SlowLogSlowerThan = "slowlog-log-slower-than"
+	SlowLogMaxLen     = "slowlog-max-len"
+
+	NotifyKeyspaceEvents = "notify-keyspace-events"
+
+	EndpointDir = "endpoint-dir"
 )
 
-var validProperties = []string{RequirePass, LeaderAuth, ProtectedMode, MaxMemory, AutoGC, KeepAlive, LogConfig, ReplicaPriority, AnnouncePort, AnnounceIP}
+var validProperties = []string{RequirePass, LeaderAuth, ProtectedMode, MaxMemory, AutoGC, KeepAlive, LogConfig, ReplicaPriority, AnnouncePort, AnnounceIP, EventLogMaxSize, EventLogMaxAge, TileCacheSize, AuditLog, AuditLogMaxSize, AuditLogMaxFiles, AuditLogEndpoint, CollectionMaxObjects, UserMaxHooks, SlowLogSlowerThan, SlowLogMaxLen, NotifyKeyspaceEvents, EndpointDir}
 
 // Config is a Meridian config
 type Config struct {
@@ -53,6 +81,8 @@ type Config struct {
 	_replicaPriority int64
 	_serverID        string
 	_readOnly        bool
+	_aclUsers        []string
+	_rateLimits      []string
 
 	_requirePassP   string
 	_requirePass    string
@@ -72,6 +102,37 @@ type Config struct {
 	_announceIP     string
 	_announcePortP  string
 	_announcePort   int64
+	_eventLogSizeP  string
+	_eventLogSize   int64
+	_eventLogAgeP   string
+	_eventLogAge    uint64
+	_tileCacheP     string
+	_tileCache      int64
+
+	_auditLogP         string
+	_auditLog          string
+	_auditLogSizeP     string
+	_auditLogSize      int64
+	_auditLogFilesP    string
+	_auditLogFiles     int64
+	_auditLogEndpointP string
+	_auditLogEndpoint  string
+
+	_colMaxObjectsP string
+	_colMaxObjects  int64
+	_userMaxHooksP  string
+	_userMaxHooks   int64
+
+	_slowLogSlowerThanP string
+	_slowLogSlowerThan  int64
+	_slowLogMaxLenP     string
+	_slowLogMaxLen      int64
+
+	_notifyKeyspaceP string
+	_notifyKeyspace  string
+
+	_endpointDirP string
+	_endpointDir  string
 }
 
 func loadConfig(path string) (*Config, error) {
@@ -102,11 +163,35 @@ func loadConfig(path string) (*Config, error) {
 		_logConfig:      gjson.Get(json, LogConfig).String(),
 		_announceIPP:    gjson.Get(json, AnnounceIP).String(),
 		_announcePortP:  gjson.Get(json, AnnouncePort).String(),
+		_eventLogSizeP:  gjson.Get(json, EventLogMaxSize).String(),
+		_eventLogAgeP:   gjson.Get(json, EventLogMaxAge).String(),
+		_tileCacheP:     gjson.Get(json, TileCacheSize).String(),
+
+		_auditLogP:         gjson.Get(json, AuditLog).String(),
+		_auditLogSizeP:     gjson.Get(json, AuditLogMaxSize).String(),
+		_auditLogFilesP:    gjson.Get(json, AuditLogMaxFiles).String(),
+		_auditLogEndpointP: gjson.Get(json, AuditLogEndpoint).String(),
+
+		_colMaxObjectsP: gjson.Get(json, CollectionMaxObjects).String(),
+		_userMaxHooksP:  gjson.Get(json, UserMaxHooks).String(),
+
+		_slowLogSlowerThanP: gjson.Get(json, SlowLogSlowerThan).String(),
+		_slowLogMaxLenP:     gjson.Get(json, SlowLogMaxLen).String(),
+
+		_notifyKeyspaceP: gjson.Get(json, NotifyKeyspaceEvents).String(),
+
+		_endpointDirP: gjson.Get(json, EndpointDir).String(),
 	}
 
 	if config._serverID == "" {
 		config._serverID = randomKey(16)
 	}
+	for _, line := range gjson.Get(json, ACLUsers).Array() {
+		config._aclUsers = append(config._aclUsers, line.String())
+	}
+	for _, line := range gjson.Get(json, RateLimits).Array() {
+		config._rateLimits = append(config._rateLimits, line.String())
+	}
 
 	// Need to be sure we look for existence vs not zero because zero is an intentional setting
 	// anything less than zero will be considered default and will result in no slave_priority
@@ -145,6 +230,45 @@ func loadConfig(path string) (*Config, error) {
 	if err := config.setProperty(AnnouncePort, config._announcePortP, true); err != nil {
 		return nil, err
 	}
+	if err := config.setProperty(EventLogMaxSize, config._eventLogSizeP, true); err != nil {
+		return nil, err
+	}
+	if err := config.setProperty(EventLogMaxAge, config._eventLogAgeP, true); err != nil {
+		return nil, err
+	}
+	if err := config.setProperty(TileCacheSize, config._tileCacheP, true); err != nil {
+		return nil, err
+	}
+	if err := config.setProperty(AuditLog, config._auditLogP, true); err != nil {
+		return nil, err
+	}
+	if err := config.setProperty(AuditLogMaxSize, config._auditLogSizeP, true); err != nil {
+		return nil, err
+	}
+	if err := config.setProperty(AuditLogMaxFiles, config._auditLogFilesP, true); err != nil {
+		return nil, err
+	}
+	if err := config.setProperty(AuditLogEndpoint, config._auditLogEndpointP, true); err != nil {
+		return nil, err
+	}
+	if err := config.setProperty(CollectionMaxObjects, config._colMaxObjectsP, true); err != nil {
+		return nil, err
+	}
+	if err := config.setProperty(UserMaxHooks, config._userMaxHooksP, true); err != nil {
+		return nil, err
+	}
+	if err := config.setProperty(SlowLogSlowerThan, config._slowLogSlowerThanP, true); err != nil {
+		return nil, err
+	}
+	if err := config.setProperty(SlowLogMaxLen, config._slowLogMaxLenP, true); err != nil {
+		return nil, err
+	}
+	if err := config.setProperty(NotifyKeyspaceEvents, config._notifyKeyspaceP, true); err != nil {
+		return nil, err
+	}
+	if err := config.setProperty(EndpointDir, config._endpointDirP, true); err != nil {
+		return nil, err
+	}
 	config.write(false)
 	return config, nil
 }
@@ -184,6 +308,51 @@ func (config *Config) write(writeProperties bool) {
 		} else {
 			config._announcePortP = strconv.FormatUint(uint64(config._announcePort), 10)
 		}
+		config._eventLogSizeP = formatMemSize(config._eventLogSize)
+		if config._eventLogAge == 0 {
+			config._eventLogAgeP = ""
+		} else {
+			config._eventLogAgeP = strconv.FormatUint(config._eventLogAge, 10)
+		}
+		if config._tileCache == defaultTileCacheSize {
+			config._tileCacheP = ""
+		} else {
+			config._tileCacheP = strconv.FormatInt(config._tileCache, 10)
+		}
+		config._auditLogP = config._auditLog
+		if config._auditLogSize == defaultAuditLogMaxSize {
+			config._auditLogSizeP = ""
+		} else {
+			config._auditLogSizeP = formatMemSize(config._auditLogSize)
+		}
+		if config._auditLogFiles == defaultAuditLogMaxFiles {
+			config._auditLogFilesP = ""
+		} else {
+			config._auditLogFilesP = strconv.FormatInt(config._auditLogFiles, 10)
+		}
+		config._auditLogEndpointP = config._auditLogEndpoint
+		if config._colMaxObjects == 0 {
+			config._colMaxObjectsP = ""
+		} else {
+			config._colMaxObjectsP = strconv.FormatInt(config._colMaxObjects, 10)
+		}
+		if config._userMaxHooks == 0 {
+			config._userMaxHooksP = ""
+		} else {
+			config._userMaxHooksP = strconv.FormatInt(config._userMaxHooks, 10)
+		}
+		if config._slowLogSlowerThan == defaultSlowLogSlowerThan {
+			config._slowLogSlowerThanP = ""
+		} else {
+			config._slowLogSlowerThanP = strconv.FormatInt(config._slowLogSlowerThan, 10)
+		}
+		if config._slowLogMaxLen == defaultSlowLogMaxLen {
+			config._slowLogMaxLenP = ""
+		} else {
+			config._slowLogMaxLenP = strconv.FormatInt(config._slowLogMaxLen, 10)
+		}
+		config._notifyKeyspaceP = config._notifyKeyspace
+		config._endpointDirP = config._endpointDir
 	}
 
 	m := make(map[string]interface{})
@@ -208,6 +377,12 @@ func (config *Config) write(writeProperties bool) {
 	if config._readOnly {
 		m[ReadOnly] = config._readOnly
 	}
+	if len(config._aclUsers) > 0 {
+		m[ACLUsers] = config._aclUsers
+	}
+	if len(config._rateLimits) > 0 {
+		m[RateLimits] = config._rateLimits
+	}
 	if config._requirePassP != "" {
 		m[RequirePass] = config._requirePassP
 	}
@@ -239,6 +414,45 @@ func (config *Config) write(writeProperties bool) {
 	if config._announcePortP != "" {
 		m[AnnouncePort] = config._announcePortP
 	}
+	if config._eventLogSizeP != "" {
+		m[EventLogMaxSize] = config._eventLogSizeP
+	}
+	if config._eventLogAgeP != "" {
+		m[EventLogMaxAge] = config._eventLogAgeP
+	}
+	if config._tileCacheP != "" {
+		m[TileCacheSize] = config._tileCacheP
+	}
+	if config._auditLogP != "" {
+		m[AuditLog] = config._auditLogP
+	}
+	if config._auditLogSizeP != "" {
+		m[AuditLogMaxSize] = config._auditLogSizeP
+	}
+	if config._auditLogFilesP != "" {
+		m[AuditLogMaxFiles] = config._auditLogFilesP
+	}
+	if config._auditLogEndpointP != "" {
+		m[AuditLogEndpoint] = config._auditLogEndpointP
+	}
+	if config._colMaxObjectsP != "" {
+		m[CollectionMaxObjects] = config._colMaxObjectsP
+	}
+	if config._userMaxHooksP != "" {
+		m[UserMaxHooks] = config._userMaxHooksP
+	}
+	if config._slowLogSlowerThanP != "" {
+		m[SlowLogSlowerThan] = config._slowLogSlowerThanP
+	}
+	if config._slowLogMaxLenP != "" {
+		m[SlowLogMaxLen] = config._slowLogMaxLenP
+	}
+	if config._notifyKeyspaceP != "" {
+		m[NotifyKeyspaceEvents] = config._notifyKeyspaceP
+	}
+	if config._endpointDirP != "" {
+		m[EndpointDir] = config._endpointDirP
+	}
 	data, err := json.MarshalIndent(m, "", "\t")
 	if err != nil {
 		panic(err)
@@ -373,6 +587,102 @@ func (config *Config) setProperty(name, value string, fromLoad bool) error {
 				config._announcePort = int64(announcePort)
 			}
 		}
+	case EventLogMaxSize:
+		sz, ok := parseMemSize(value)
+		if !ok {
+			invalid = true
+		} else {
+			config._eventLogSize = sz
+		}
+	case EventLogMaxAge:
+		if value == "" {
+			config._eventLogAge = 0
+		} else {
+			age, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				invalid = true
+			} else {
+				config._eventLogAge = age
+			}
+		}
+	case TileCacheSize:
+		if value == "" {
+			config._tileCache = defaultTileCacheSize
+		} else {
+			size, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				invalid = true
+			} else {
+				config._tileCache = int64(size)
+			}
+		}
+	case AuditLog:
+		config._auditLog = value
+	case AuditLogMaxSize:
+		if value == "" {
+			config._auditLogSize = defaultAuditLogMaxSize
+		} else if sz, ok := parseMemSize(value); !ok {
+			invalid = true
+		} else {
+			config._auditLogSize = sz
+		}
+	case AuditLogMaxFiles:
+		if value == "" {
+			config._auditLogFiles = defaultAuditLogMaxFiles
+		} else {
+			files, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				invalid = true
+			} else {
+				config._auditLogFiles = int64(files)
+			}
+		}
+	case AuditLogEndpoint:
+		config._auditLogEndpoint = value
+	case CollectionMaxObjects, UserMaxHooks:
+		var max uint64
+		if value != "" {
+			var err error
+			max, err = strconv.ParseUint(value, 10, 32)
+			if err != nil {
+				invalid = true
+			}
+		}
+		if name == CollectionMaxObjects {
+			config._colMaxObjects = int64(max)
+		} else {
+			config._userMaxHooks = int64(max)
+		}
+	case SlowLogSlowerThan:
+		if value == "" {
+			config._slowLogSlowerThan = defaultSlowLogSlowerThan
+		} else {
+			us, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				invalid = true
+			} else {
+				config._slowLogSlowerThan = us
+			}
+		}
+	case SlowLogMaxLen:
+		if value == "" {
+			config._slowLogMaxLen = defaultSlowLogMaxLen
+		} else {
+			n, err := strconv.ParseUint(value, 10, 32)
+			if err != nil {
+				invalid = true
+			} else {
+				config._slowLogMaxLen = int64(n)
+			}
+		}
+	case NotifyKeyspaceEvents:
+		if strings.Trim(value, keyspaceEventFlags) != "" {
+			invalid = true
+		} else {
+			config._notifyKeyspace = value
+		}
+	case EndpointDir:
+		config._endpointDir = value
 	}
 
 	if invalid {
@@ -422,6 +732,32 @@ func (config *Config) getProperty(name string) string {
 		return config._announceIP
 	case AnnouncePort:
 		return strconv.FormatUint(uint64(config._announcePort), 10)
+	case EventLogMaxSize:
+		return formatMemSize(config._eventLogSize)
+	case EventLogMaxAge:
+		return strconv.FormatUint(config._eventLogAge, 10)
+	case TileCacheSize:
+		return strconv.FormatInt(config._tileCache, 10)
+	case AuditLog:
+		return config._auditLog
+	case AuditLogMaxSize:
+		return formatMemSize(config._auditLogSize)
+	case AuditLogMaxFiles:
+		return strconv.FormatInt(config._auditLogFiles, 10)
+	case AuditLogEndpoint:
+		return config._auditLogEndpoint
+	case CollectionMaxObjects:
+		return strconv.FormatInt(config._colMaxObjects, 10)
+	case UserMaxHooks:
+		return strconv.FormatInt(config._userMaxHooks, 10)
+	case SlowLogSlowerThan:
+		return strconv.FormatInt(config._slowLogSlowerThan, 10)
+	case SlowLogMaxLen:
+		return strconv.FormatInt(config._slowLogMaxLen, 10)
+	case NotifyKeyspaceEvents:
+		return config._notifyKeyspace
+	case EndpointDir:
+		return config._endpointDir
 	}
 }
 
@@ -469,14 +805,36 @@ func (s *Server) cmdConfigSet(msg *Message) (res resp.Value, err error) {
 	if len(vs) != 0 {
 		return NOMessage, errInvalidNumberOfArguments
 	}
+	if name == AuditLogEndpoint && value != "" {
+		if err := s.epc.Validate(value); err != nil {
+			return NOMessage, clientErrorf("Invalid argument '%s' for "+
+				"CONFIG SET '%s'", value, name)
+		}
+	}
 	if err := s.config.setProperty(name, value, false); err != nil {
 		return NOMessage, err
 	}
-	if name == MaxMemory {
+	switch name {
+	case MaxMemory:
 		s.checkOutOfMemory()
+	case TileCacheSize:
+		s.tiles.resize(s.config.tileCacheSize())
+	case EndpointDir:
+		s.epc.SetLocalDir(s.endpointDir())
 	}
 	return OKMessage(msg, start), nil
 }
+
+// endpointDir returns the directory of the file and unix endpoints. A
+// relative directory is in the data directory.
+func (s *Server) endpointDir() string {
+	dir := s.config.endpointDir()
+	if dir != "" && !filepath.IsAbs(dir) {
+		dir = filepath.Join(s.dir, dir)
+	}
+	return dir
+}
+
 func (s *Server) cmdConfigRewrite(msg *Message) (res resp.Value, err error) {
 	start := time.Now()
 	vs := msg.Args[1:]
@@ -566,6 +924,78 @@ func (config *Config) announcePort() int {
 	config.mu.RUnlock()
 	return int(v)
 }
+func (config *Config) eventLogMaxSize() int64 {
+	config.mu.RLock()
+	v := config._eventLogSize
+	config.mu.RUnlock()
+	return v
+}
+func (config *Config) eventLogMaxAge() time.Duration {
+	config.mu.RLock()
+	v := config._eventLogAge
+	config.mu.RUnlock()
+	return time.Duration(v) * time.Second
+}
+func (config *Config) tileCacheSize() int {
+	config.mu.RLock()
+	v := config._tileCache
+	config.mu.RUnlock()
+	return int(v)
+}
+func (config *Config) auditLog() string {
+	config.mu.RLock()
+	v := config._auditLog
+	config.mu.RUnlock()
+	return v
+}
+func (config *Config) auditLogMaxSize() int64 {
+	config.mu.RLock()
+	v := config._auditLogSize
+	config.mu.RUnlock()
+	return v
+}
+func (config *Config) auditLogMaxFiles() int {
+	config.mu.RLock()
+	v := config._auditLogFiles
+	config.mu.RUnlock()
+	return int(v)
+}
+func (config *Config) auditLogEndpoint() string {
+	config.mu.RLock()
+	v := config._auditLogEndpoint
+	config.mu.RUnlock()
+	return v
+}
+func (config *Config) collectionMaxObjects() int {
+	config.mu.RLock()
+	v := config._colMaxObjects
+	config.mu.RUnlock()
+	return int(v)
+}
+func (config *Config) userMaxHooks() int {
+	config.mu.RLock()
+	v := config._userMaxHooks
+	config.mu.RUnlock()
+	return int(v)
+}
+func (config *Config) slowLogSlowerThan() time.Duration {
+	config.mu.RLock()
+	v := config._slowLogSlowerThan
+	config.mu.RUnlock()
+	return time.Duration(v) * time.Microsecond
+}
+func (config *Config) slowLogMaxLen() int {
+	config.mu.RLock()
+	v := config._slowLogMaxLen
+	config.mu.RUnlock()
+	return int(v)
+}
+func (config *Config) notifyKeyspaceEvents() string {
+	config.mu.RLock()
+	v := config._notifyKeyspace
+	config.mu.RUnlock()
+	return v
+}
 func (config *Config) setFollowHost(v string) {
 	config.mu.Lock()
 	config._followHost = v
@@ -581,3 +1011,31 @@ func (config *Config) setReadOnly(v bool) {
 	config._readOnly = v
 	config.mu.Unlock()
 }
+func (config *Config) aclUsers() []string {
+	config.mu.RLock()
+	v := config._aclUsers
+	config.mu.RUnlock()
+	return v
+}
+func (config *Config) setACLUsers(v []string) {
+	config.mu.Lock()
+	config._aclUsers = v
+	config.mu.Unlock()
+}
+func (config *Config) rateLimits() []string {
+	config.mu.RLock()
+	v := config._rateLimits
+	config.mu.RUnlock()
+	return v
+}
+func (config *Config) setRateLimits(v []string) {
+	config.mu.Lock()
+	config._rateLimits = v
+	config.mu.Unlock()
+}
+func (config *Config) endpointDir() string {
+	config.mu.RLock()
+	v := config._endpointDir
+	config.mu.RUnlock()
+	return v
+}
@@ -304,7 +304,7 @@ func (s *Server) cmdDEL(msg *Message) (resp.Value, commandDetails, error) {
 		old = col.Delete(id)
 		if old != nil {
 			if col.Count() == 0 {
-				s.cols.Delete(key)
+				s.deleteCol(key)
 			}
 			updated = true
 		} else if erron404 {
@@ -386,7 +386,7 @@ func (s *Server) cmdPDEL(msg *Message) (resp.Value, commandDetails, error) {
 			s.groupDisconnectObject(key, id)
 		}
 		if col.Count() == 0 {
-			s.cols.Delete(key)
+			s.deleteCol(key)
 		}
 	}
 
@@ -415,12 +415,18 @@ func (s *Server) cmdPDEL(msg *Message) (resp.Value, commandDetails, error) {
 func (s *Server) cmdDROPop(key string) *collection.Collection {
 	col, _ := s.cols.Get(key)
 	if col != nil {
-		s.cols.Delete(key)
+		s.deleteCol(key)
 	}
 	s.groupDisconnectCollection(key)
 	return col
 }
 
+// deleteCol removes a collection, and its count of expired objects.
+func (s *Server) deleteCol(key string) {
+	s.cols.Delete(key)
+	delete(s.colExpired, key)
+}
+
 // DROP key
 func (s *Server) cmdDROP(msg *Message) (resp.Value, commandDetails, error) {
 	start := time.Now()
@@ -496,12 +502,16 @@ func (s *Server) cmdRENAME(msg *Message) (resp.Value, commandDetails, error) {
 	if newCol == nil {
 		updated = true
 	} else if !nx {
-		s.cols.Delete(newKey)
+		s.deleteCol(newKey)
 		updated = true
 	}
 	if updated {
-		s.cols.Delete(key)
+		n := s.colExpired[key]
+		s.deleteCol(key)
 		s.cols.Set(newKey, col)
+		if n > 0 {
+			s.colExpired[newKey] = n
+		}
 	}
 
 	// >> Response
@@ -587,6 +597,7 @@ func (s *Server) cmdFLUSHDB(msg *Message) (resp.Value, commandDetails, error) {
 	s.hooksOut.Clear()
 	s.hookTree.Clear()
 	s.hookCross.Clear()
+	s.colExpired = nil
 
 	// >> Response
 
@@ -785,6 +796,8 @@ func (s *Server) cmdSET(msg *Message) (resp.Value, commandDetails, error) {
 	var flist field.List
 	if old := col.Get(id); old != nil {
 		flist = old.Fields()
+	} else if err := s.checkObjectQuota(key, col.Count()); err != nil {
+		return retwerr(err)
 	}
 	for _, f := range fields {
 		flist = flist.Set(f)
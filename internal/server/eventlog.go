@@ -0,0 +1,227 @@
+package server
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tidwall/buntdb"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/resp"
+	"github.com/tidwall/sjson"
+)
+
+const (
+	eventLogPrefix = "event:log:"
+	eventSeqKey    = "event:seq"
+)
+
+var errEventLogDisabled = errors.New("event log disabled")
+
+// loadEventLog reads the last event sequence number and the total size of
+// the event log from the queue database.
+func (s *Server) loadEventLog() error {
+	return s.qdb.View(func(tx *buntdb.Tx) error {
+		val, err := tx.Get(eventSeqKey)
+		if err != nil && err != buntdb.ErrNotFound {
+			return err
+		}
+		if err == nil {
+			s.eseq = stringToUint64(val)
+		}
+		var size int64
+		err = tx.AscendKeys(eventLogPrefix+"*", func(key, val string) bool {
+			size += int64(len(val))
+			return true
+		})
+		s.elogSize.Store(size)
+		return err
+	})
+}
+
+// appendEventLog appends the fence messages to the event log, assigning a
+// sequence number to each one, which is returned in their "seq" member. When
+// the event log is disabled the messages are returned unchanged, without a
+// sequence number. The caller must hold the server write lock.
+func (s *Server) appendEventLog(msgs []string) ([]string, error) {
+	maxSize := s.config.eventLogMaxSize()
+	if maxSize <= 0 || len(msgs) == 0 {
+		return msgs, nil
+	}
+	var opts *buntdb.SetOptions
+	if ttl := s.config.eventLogMaxAge(); ttl > 0 {
+		opts = &buntdb.SetOptions{Expires: true, TTL: ttl}
+	}
+	err := s.qdb.Update(func(tx *buntdb.Tx) error {
+		for i, msg := range msgs {
+			s.eseq++
+			msg, err := sjson.SetRaw(msg, "seq", strconv.FormatUint(s.eseq, 10))
+			if err != nil {
+				return err
+			}
+			key := eventLogPrefix + uint64ToString(s.eseq)
+			if _, _, err := tx.Set(key, msg, opts); err != nil {
+				return err
+			}
+			s.elogSize.Add(int64(len(msg)))
+			msgs[i] = msg
+		}
+		if _, _, err := tx.Set(eventSeqKey, uint64ToString(s.eseq), nil); err != nil {
+			return err
+		}
+		return s.trimEventLog(tx, maxSize)
+	})
+	return msgs, err
+}
+
+// trimEventLog deletes the oldest events until the log fits in maxSize.
+func (s *Server) trimEventLog(tx *buntdb.Tx, maxSize int64) error {
+	over := s.elogSize.Load() - maxSize
+	if over <= 0 {
+		return nil
+	}
+	var keys []string
+	err := tx.AscendKeys(eventLogPrefix+"*", func(key, val string) bool {
+		keys = append(keys, key)
+		over -= int64(len(val))
+		return over > 0
+	})
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		val, err := tx.Delete(key)
+		if err != nil {
+			if err == buntdb.ErrNotFound {
+				continue
+			}
+			return err
+		}
+		s.elogSize.Add(-int64(len(val)))
+	}
+	return nil
+}
+
+// queueItemExpired is called by the queue database for each expired item.
+func (s *Server) queueItemExpired(key, val string, tx *buntdb.Tx) error {
+	if strings.HasPrefix(key, eventLogPrefix) {
+		if _, err := tx.Delete(key); err != nil {
+			if err == buntdb.ErrNotFound {
+				return nil
+			}
+			return err
+		}
+		s.elogSize.Add(-int64(len(val)))
+		return nil
+	}
+	return s.hookLogExpired(key, val, tx)
+}
+
+// REPLAY name FROM seq
+func (s *Server) cmdReplay(msg *Message) (resp.Value, error) {
+	start := time.Now()
+	vs := msg.Args[1:]
+
+	var name, tok, sseq string
+	var ok bool
+	if vs, name, ok = tokenval(vs); !ok || name == "" {
+		return NOMessage, errInvalidNumberOfArguments
+	}
+	if vs, tok, ok = tokenval(vs); !ok || tok == "" {
+		return NOMessage, errInvalidNumberOfArguments
+	}
+	if strings.ToLower(tok) != "from" {
+		return NOMessage, errInvalidArgument(tok)
+	}
+	if vs, sseq, ok = tokenval(vs); !ok || sseq == "" {
+		return NOMessage, errInvalidNumberOfArguments
+	}
+	if len(vs) != 0 {
+		return NOMessage, errInvalidNumberOfArguments
+	}
+	seq, err := strconv.ParseUint(sseq, 10, 64)
+	if err != nil {
+		return NOMessage, errInvalidArgument(sseq)
+	}
+	if s.config.eventLogMaxSize() <= 0 {
+		return NOMessage, errEventLogDisabled
+	}
+	hook, _ := s.hooks.Get(&Hook{Name: name}).(*Hook)
+	if hook == nil || !msg.acl.ownsHook(hook) {
+		return NOMessage, errHookNotFound
+	}
+
+	// collect the logged events for the hook, which are ordered by sequence
+	var msgs []string
+	pivot := `{"hook":` + jsonString(name) + `,"seq":` +
+		strconv.FormatUint(seq, 10) + `}`
+	err = s.qdb.View(func(tx *buntdb.Tx) error {
+		return tx.AscendGreaterOrEqual("events", pivot,
+			func(key, val string) bool {
+				if gjson.Get(val, "hook").String() != name {
+					return false
+				}
+				msgs = append(msgs, val)
+				return true
+			},
+		)
+	})
+	if err != nil {
+		return NOMessage, err
+	}
+
+	if hook.channel {
+		// the events of a channel are returned only to the caller, as the
+		// other subscribers may not have missed them
+		return replayEvents(msg, msgs, start), nil
+	}
+	if len(msgs) > 0 {
+		err = s.qdb.Update(func(tx *buntdb.Tx) error {
+			opts := hookQueueOptions(hook.queueTTL())
+			for _, m := range msgs {
+				s.qidx++ // increment the log id
+				key := hookLogPrefix + uint64ToString(s.qidx)
+				if _, _, err := tx.Set(key, m, opts); err != nil {
+					return err
+				}
+			}
+			_, _, err := tx.Set("hook:idx", uint64ToString(s.qidx), nil)
+			return err
+		})
+		if err != nil {
+			return NOMessage, err
+		}
+		if hook.pause.paused {
+			hook.pause.queued += len(msgs)
+		}
+		hook.signalQueued(len(msgs))
+	}
+
+	if msg.OutputType == JSON {
+		return resp.StringValue(`{"ok":true,"count":` + strconv.Itoa(len(msgs)) +
+			`,"elapsed":"` + time.Since(start).String() + "\"}"), nil
+	}
+	return resp.IntegerValue(len(msgs)), nil
+}
+
+// replayEvents returns the replayed events of a channel.
+func replayEvents(msg *Message, msgs []string, start time.Time) resp.Value {
+	if msg.OutputType == JSON {
+		buf := []byte(`{"ok":true,"count":` + strconv.Itoa(len(msgs)) +
+			`,"events":[`)
+		for i, m := range msgs {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			buf = append(buf, m...)
+		}
+		buf = append(buf, `],"elapsed":"`+time.Since(start).String()+`"}`...)
+		return resp.StringValue(string(buf))
+	}
+	vals := make([]resp.Value, len(msgs))
+	for i, m := range msgs {
+		vals[i] = resp.StringValue(m)
+	}
+	return resp.ArrayValue(vals)
+}
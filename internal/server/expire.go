@@ -21,6 +21,8 @@ func (s *Server) backgroundExpiring(wg *sync.WaitGroup) {
 		now := time.Now()
 		s.backgroundExpireObjects(now)
 		s.backgroundExpireHooks(now)
+		s.backgroundStaleObjects(now)
+		s.backgroundHookSchedules(now)
 	})
 }
 
@@ -43,6 +45,14 @@ func (s *Server) backgroundExpireObjects(now time.Time) {
 		if err != nil {
 			log.Fatal(err)
 		}
+		d.expired = true
+		if col, _ := s.cols.Get(d.key); col != nil {
+			// the count is removed with the collection
+			if s.colExpired == nil {
+				s.colExpired = make(map[string]int64)
+			}
+			s.colExpired[d.key]++
+		}
 		if err := s.writeAOF(msg.Args, &d); err != nil {
 			log.Fatal(err)
 		}
@@ -97,6 +97,35 @@ func fenceMatch(
 			return nil
 		}
 	}
+	if details.command == "del" && details.expired {
+		// the last geometry and fields of the object
+		b := []byte(`{"command":"expired"`)
+		b = appendHookDetails(b, hookName, metas)
+		b = append(b, `,"key":`...)
+		b = appendJSONString(b, details.key)
+		b = append(b, `,"id":`...)
+		b = appendJSONString(b, details.obj.ID())
+		b = append(b, `,"time":`...)
+		b = append(b, jsonTimeFormat(details.timestamp)...)
+		b = append(b, `,"object":`...)
+		b = details.obj.Geo().AppendJSON(b)
+		if details.obj.Fields().Len() > 0 {
+			b = append(b, `,"fields":{`...)
+			var i int
+			details.obj.Fields().Scan(func(f field.Field) bool {
+				if i > 0 {
+					b = append(b, ',')
+				}
+				b = appendJSONString(b, f.Name())
+				b = append(b, ':')
+				b = append(b, f.Value().JSON()...)
+				i++
+				return true
+			})
+			b = append(b, '}')
+		}
+		return []string{string(append(b, '}'))}
+	}
 	if details.command == "del" {
 		return []string{
 			`{"command":"del"` + hookJSONString(hookName, metas) +
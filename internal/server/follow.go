@@ -48,7 +48,7 @@ func (s *Server) cmdFollow(msg *Message) (res resp.Value, err error) {
 		auth := s.config.leaderAuth()
 		if update {
 			s.mu.Unlock()
-			conn, err := DialTimeout(fmt.Sprintf("%s:%d", host, port), time.Second*2)
+			conn, err := s.dialLeader(fmt.Sprintf("%s:%d", host, port))
 			if err != nil {
 				s.mu.Lock()
 				return NOMessage, fmt.Errorf("cannot follow: %v", err)
@@ -239,7 +239,7 @@ func (s *Server) followStep(host string, port int, followc int) error {
 	addr := fmt.Sprintf("%s:%d", host, port)
 
 	// check if we are following self
-	conn, err := DialTimeout(addr, time.Second*2)
+	conn, err := s.dialLeader(addr)
 	if err != nil {
 		return fmt.Errorf("cannot follow: %v", err)
 	}
@@ -1,9 +1,18 @@
 package server
 
 import (
+	"fmt"
+	"time"
+
+	"github.com/aiqia-dev/meridian/internal/log"
 	"github.com/tidwall/btree"
+	"github.com/tidwall/resp"
 )
 
+// The groups of the hooks are written to the AOF with SETGROUP and DELGROUP,
+// so they survive a restart, and the followers keep the same groups as the
+// leader. The groups of the live geofences aren't written.
+
 func byGroupHook(va, vb interface{}) bool {
 	a, b := va.(*groupItem), vb.(*groupItem)
 	if a.hookName < b.hookName {
@@ -45,8 +54,7 @@ type groupItem struct {
 	groupID  string
 }
 
-func newGroupItem(hookName, colKey, objID string) *groupItem {
-	groupID := bsonID()
+func newGroupItem(hookName, colKey, objID, groupID string) *groupItem {
 	g := &groupItem{}
 	// create a single string allocation
 	ustr := hookName + colKey + objID + groupID
@@ -63,9 +71,10 @@ func newGroupItem(hookName, colKey, objID string) *groupItem {
 }
 
 func (s *Server) groupConnect(hookName, colKey, objID string) (groupID string) {
-	g := newGroupItem(hookName, colKey, objID)
+	g := newGroupItem(hookName, colKey, objID, bsonID())
 	s.groupHooks.Set(g)
 	s.groupObjects.Set(g)
+	s.groupWriteAOF("setgroup", hookName, colKey, objID, g.groupID)
 	return g.groupID
 }
 
@@ -75,10 +84,57 @@ func (s *Server) groupDisconnect(hookName, colKey, objID string) {
 		colKey:   colKey,
 		objID:    objID,
 	}
-	s.groupHooks.Delete(g)
+	if s.groupHooks.Delete(g) != nil {
+		s.groupWriteAOF("delgroup", hookName, colKey, objID)
+	}
 	s.groupObjects.Delete(g)
 }
 
+// groupWriteAOF writes a change of the groups of a hook to the AOF.
+func (s *Server) groupWriteAOF(args ...string) {
+	if args[1] == "" || s.config.followHost() != "" {
+		// a live geofence, or a follower, which gets the groups from the
+		// leader
+		return
+	}
+	if err := s.writeAOF(args, nil); err != nil {
+		log.Errorf("%s: %v", args[0], err)
+	}
+}
+
+// SETGROUP hook key id group
+// DELGROUP hook key id
+//
+// The groups are only changed from the AOF and the leader.
+func (s *Server) cmdGroupOp(msg *Message, client *Client) (
+	res resp.Value, d commandDetails, err error,
+) {
+	start := time.Now()
+	if client != nil {
+		return NOMessage, d, fmt.Errorf("unknown command '%s'", msg.Args[0])
+	}
+	args := msg.Args
+	if msg.Command() == "setgroup" {
+		if len(args) != 5 {
+			return NOMessage, d, errInvalidNumberOfArguments
+		}
+		g := newGroupItem(args[1], args[2], args[3], args[4])
+		s.groupHooks.Set(g)
+		s.groupObjects.Set(g)
+	} else {
+		if len(args) != 4 {
+			return NOMessage, d, errInvalidNumberOfArguments
+		}
+		g := &groupItem{hookName: args[1], colKey: args[2], objID: args[3]}
+		s.groupHooks.Delete(g)
+		s.groupObjects.Delete(g)
+	}
+	d.command = msg.Command()
+	d.updated = true
+	d.timestamp = time.Now()
+	return OKMessage(msg, start), d, nil
+}
+
 func (s *Server) groupGet(hookName, colKey, objID string) (groupID string) {
 	v := s.groupHooks.Get(&groupItem{
 		hookName: hookName,
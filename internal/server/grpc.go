@@ -0,0 +1,311 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	cservicev1 "github.com/aiqia-dev/meridian/internal/cservice/v1"
+	hservicev1 "github.com/aiqia-dev/meridian/internal/hservice/v1"
+	"github.com/tidwall/gjson"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// grpcService implements the gRPC command service. All commands go through
+// the same pipeline as the other transports, using json output.
+type grpcService struct {
+	cservicev1.UnimplementedCommandServiceServer
+	s      *Server
+	nextID atomic.Int64
+}
+
+// newGRPCServer returns a gRPC server with the command service registered.
+func (s *Server) newGRPCServer() *grpc.Server {
+	gs := grpc.NewServer()
+	cservicev1.RegisterCommandServiceServer(gs, &grpcService{s: s})
+	return gs
+}
+
+// newMessage creates the client and message for a gRPC call. The password
+// is read from the "authorization" metadata, with an optional "Bearer "
+// prefix.
+func (g *grpcService) newMessage(ctx context.Context, args []string) (
+	*Client, *Message, error,
+) {
+	if len(args) == 0 {
+		return nil, nil, status.Error(codes.InvalidArgument,
+			errInvalidNumberOfArguments.Error())
+	}
+	client := new(Client)
+	client.id = -int(g.nextID.Add(1))
+	client.opened = time.Now()
+	client.last = client.opened
+	if p, ok := peer.FromContext(ctx); ok {
+		client.remoteAddr = p.Addr.String()
+	}
+	if !strings.HasPrefix(client.remoteAddr, "127.0.0.1:") &&
+		!strings.HasPrefix(client.remoteAddr, "[::1]:") && g.s.isProtected() {
+		return nil, nil, status.Error(codes.PermissionDenied,
+			"protected mode is enabled")
+	}
+	msg := &Message{
+		Args:       args,
+		ConnType:   GRPC,
+		OutputType: JSON,
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if vals := md.Get("authorization"); len(vals) > 0 {
+			msg.Auth = strings.TrimPrefix(vals[0], "Bearer ")
+		}
+	}
+	return client, msg, nil
+}
+
+// exec runs a command and returns its json output.
+func (g *grpcService) exec(ctx context.Context, args ...string) (
+	gjson.Result, error,
+) {
+	client, msg, err := g.newMessage(ctx, args)
+	if err != nil {
+		return gjson.Result{}, err
+	}
+	g.s.statsTotalCommands.Add(1)
+	if err := g.s.handleInputCommand(client, msg); err != nil {
+		if err.Error() == goingLive {
+			return gjson.Result{}, status.Error(codes.InvalidArgument,
+				"live commands must use the Fence method")
+		}
+		return gjson.Result{}, status.Error(codes.Internal, err.Error())
+	}
+	if g.s.aofdirty.Load() {
+		func() {
+			g.s.mu.Lock()
+			defer g.s.mu.Unlock()
+			g.s.flushAOF(false)
+		}()
+		g.s.aofdirty.Store(false)
+	}
+	res := gjson.ParseBytes(client.out)
+	if !res.Get("ok").Bool() {
+		return res, grpcError(res.Get("err").String())
+	}
+	return res, nil
+}
+
+// grpcError converts a command error into a gRPC status error.
+func grpcError(errMsg string) error {
+	code := codes.InvalidArgument
+	switch errMsg {
+	case errKeyNotFound.Error(), errIDNotFound.Error(), errHookNotFound.Error():
+		code = codes.NotFound
+	case errIDAlreadyExists.Error():
+		code = codes.AlreadyExists
+	case "authentication required", "invalid password":
+		code = codes.Unauthenticated
+	case errNotLeader.Error(), errReadOnly.Error():
+		code = codes.FailedPrecondition
+	}
+	return status.Error(code, errMsg)
+}
+
+// fenceArgs returns the arguments of a geofenced search, with its FENCE
+// option turned on.
+func fenceArgs(req *cservicev1.FenceRequest) ([]string, error) {
+	switch search := req.GetSearch().(type) {
+	case *cservicev1.FenceRequest_Nearby:
+		if search.Nearby != nil {
+			search.Nearby.Fence = true
+			return req.Args(), nil
+		}
+	case *cservicev1.FenceRequest_Within:
+		if search.Within != nil {
+			search.Within.Fence = true
+			return req.Args(), nil
+		}
+	case *cservicev1.FenceRequest_Intersects:
+		if search.Intersects != nil {
+			search.Intersects.Fence = true
+			return req.Args(), nil
+		}
+	}
+	return nil, status.Error(codes.InvalidArgument, "missing search")
+}
+
+// forEachField iterates over the fields of an object in a json result. The
+// fields are either a json object, as returned by GET, or an array of values
+// matching the names listed at the top of a search result.
+func forEachField(names, fields gjson.Result,
+	iter func(name string, value gjson.Result) bool,
+) {
+	if fields.IsObject() {
+		fields.ForEach(func(key, val gjson.Result) bool {
+			return iter(key.String(), val)
+		})
+		return
+	}
+	vals := fields.Array()
+	names.ForEach(func(idx, name gjson.Result) bool {
+		i := int(idx.Int())
+		if i >= len(vals) {
+			return false
+		}
+		return iter(name.String(), vals[i])
+	})
+}
+
+// searchObject converts a json object from a search result.
+func searchObject(names, obj gjson.Result) *cservicev1.SearchObject {
+	so := &cservicev1.SearchObject{
+		Id:       obj.Get("id").String(),
+		Geometry: hservicev1.GeometryFromJSON(obj.Get("object")),
+		Distance: obj.Get("distance").Float(),
+	}
+	forEachField(names, obj.Get("fields"), func(name string, val gjson.Result) bool {
+		if so.Fields == nil {
+			so.Fields = make(map[string]string)
+		}
+		so.Fields[name] = val.String()
+		return true
+	})
+	return so
+}
+
+func (g *grpcService) search(ctx context.Context, args []string) (
+	*cservicev1.SearchResponse, error,
+) {
+	res, err := g.exec(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	out := &cservicev1.SearchResponse{
+		Count:  res.Get("count").Uint(),
+		Cursor: res.Get("cursor").Uint(),
+	}
+	names := res.Get("fields")
+	res.Get("objects").ForEach(func(_, obj gjson.Result) bool {
+		out.Objects = append(out.Objects, searchObject(names, obj))
+		return true
+	})
+	return out, nil
+}
+
+// Set sets the value of an id
+func (g *grpcService) Set(ctx context.Context, req *cservicev1.SetRequest,
+) (*cservicev1.SetResponse, error) {
+	if _, err := g.exec(ctx, req.Args()...); err != nil {
+		return nil, err
+	}
+	return &cservicev1.SetResponse{}, nil
+}
+
+// Get gets the object of an id
+func (g *grpcService) Get(ctx context.Context, req *cservicev1.GetRequest,
+) (*cservicev1.GetResponse, error) {
+	res, err := g.exec(ctx, req.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	obj := searchObject(gjson.Result{}, res)
+	obj.Id = req.GetId()
+	return &cservicev1.GetResponse{Object: obj}, nil
+}
+
+// Del deletes an id
+func (g *grpcService) Del(ctx context.Context, req *cservicev1.DelRequest,
+) (*cservicev1.DelResponse, error) {
+	if _, err := g.exec(ctx, req.Args()...); err != nil {
+		return nil, err
+	}
+	return &cservicev1.DelResponse{}, nil
+}
+
+// Scan incrementally iterates through a key
+func (g *grpcService) Scan(ctx context.Context, req *cservicev1.ScanRequest,
+) (*cservicev1.SearchResponse, error) {
+	return g.search(ctx, req.Args())
+}
+
+// Nearby searches for objects near a point
+func (g *grpcService) Nearby(ctx context.Context, req *cservicev1.NearbyRequest,
+) (*cservicev1.SearchResponse, error) {
+	return g.search(ctx, req.Args())
+}
+
+// Within searches for objects within an area
+func (g *grpcService) Within(ctx context.Context,
+	req *cservicev1.WithinRequest,
+) (*cservicev1.SearchResponse, error) {
+	return g.search(ctx, req.Args())
+}
+
+// Intersects searches for objects intersecting an area
+func (g *grpcService) Intersects(ctx context.Context,
+	req *cservicev1.IntersectsRequest,
+) (*cservicev1.SearchResponse, error) {
+	return g.search(ctx, req.Args())
+}
+
+// SetHook creates a webhook which points to a geofenced search
+func (g *grpcService) SetHook(ctx context.Context,
+	req *cservicev1.SetHookRequest,
+) (*cservicev1.SetHookResponse, error) {
+	if _, err := fenceArgs(req.GetFence()); err != nil {
+		return nil, err
+	}
+	if _, err := g.exec(ctx, req.Args()...); err != nil {
+		return nil, err
+	}
+	return &cservicev1.SetHookResponse{}, nil
+}
+
+// Execute executes any command, returning its json output
+func (g *grpcService) Execute(ctx context.Context,
+	req *cservicev1.ExecuteRequest,
+) (*cservicev1.ExecuteResponse, error) {
+	res, err := g.exec(ctx, req.GetArgs()...)
+	if err != nil {
+		return nil, err
+	}
+	return &cservicev1.ExecuteResponse{Json: res.Raw}, nil
+}
+
+// Fence opens a live geofence and streams its events
+func (g *grpcService) Fence(req *cservicev1.FenceRequest,
+	stream cservicev1.CommandService_FenceServer,
+) error {
+	args, err := fenceArgs(req)
+	if err != nil {
+		return err
+	}
+	ctx := stream.Context()
+	client, msg, err := g.newMessage(ctx, args)
+	if err != nil {
+		return err
+	}
+	g.s.statsTotalCommands.Add(1)
+	err = g.s.handleInputCommand(client, msg)
+	if err == nil {
+		// the command did not go live, which means it failed
+		return grpcError(gjson.GetBytes(client.out, "err").String())
+	}
+	lfs, ok := err.(liveFenceSwitches)
+	if !ok {
+		return status.Error(codes.Internal, err.Error())
+	}
+	err = g.s.watchLiveFence(lfs, msg, ctx.Done(), nil, func(msg string) error {
+		ev, err := hservicev1.EventFromJSON(msg)
+		if err != nil {
+			return err
+		}
+		return stream.Send(ev)
+	})
+	if err == errLiveFenceWrite {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,178 @@
+package server
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tidwall/buntdb"
+	"github.com/tidwall/resp"
+)
+
+// hookPauseMaxQueue is the default max number of events that are queued
+// while a hook is paused.
+const hookPauseMaxQueue = 100000
+
+// hookPause is the pause state of a hook. A paused hook doesn't deliver
+// messages, and its events are queued, up to maxQueue, or dropped. The
+// queued messages don't expire until the hook is resumed.
+type hookPause struct {
+	paused   bool
+	drop     bool  // drop the events instead of queueing them
+	maxQueue int   // max messages in the queue while paused
+	queued   int   // messages in the queue
+	dropped  int64 // events dropped since the hook was paused
+}
+
+// args returns the HOOKPAUSE arguments that reproduce the pause of a hook.
+func (p hookPause) args(name string) []string {
+	if !p.paused {
+		return nil
+	}
+	if p.drop {
+		return []string{"hookpause", name, "drop"}
+	}
+	return []string{"hookpause", name, "queue", strconv.Itoa(p.maxQueue)}
+}
+
+// admit returns the messages that are queued while the hook is paused.
+func (p *hookPause) admit(msgs []string) []string {
+	n := p.maxQueue - p.queued
+	if p.drop || n < 0 {
+		n = 0
+	}
+	if len(msgs) > n {
+		p.dropped += int64(len(msgs) - n)
+		msgs = msgs[:n]
+	}
+	p.queued += len(msgs)
+	return msgs
+}
+
+// HOOKPAUSE pattern [QUEUE max|DROP]
+// HOOKRESUME pattern
+func (s *Server) cmdHookPause(msg *Message) (
+	res resp.Value, d commandDetails, err error,
+) {
+	start := time.Now()
+	vs := msg.Args[1:]
+	resume := msg.Command() == "hookresume"
+
+	var pattern string
+	var ok bool
+	if vs, pattern, ok = tokenval(vs); !ok || pattern == "" {
+		return NOMessage, d, errInvalidNumberOfArguments
+	}
+	pause := hookPause{paused: !resume, maxQueue: hookPauseMaxQueue}
+	if !resume && len(vs) > 0 {
+		var mode string
+		vs, mode, _ = tokenval(vs)
+		switch strings.ToLower(mode) {
+		case "drop":
+			pause.drop = true
+		case "queue":
+			var smax string
+			if vs, smax, ok = tokenval(vs); !ok || smax == "" {
+				return NOMessage, d, errInvalidNumberOfArguments
+			}
+			n, err := strconv.ParseUint(smax, 10, 32)
+			if err != nil {
+				return NOMessage, d, errInvalidArgument(smax)
+			}
+			pause.maxQueue = int(n)
+		default:
+			return NOMessage, d, errInvalidArgument(mode)
+		}
+	}
+	if len(vs) != 0 {
+		return NOMessage, d, errInvalidNumberOfArguments
+	}
+
+	var count int
+	var qerr error
+	s.forEachHookByPattern(msg.acl, pattern, false, func(hook *Hook) bool {
+		hook.cond.L.Lock()
+		defer hook.cond.L.Unlock()
+		prev := hook.pause
+		if resume {
+			if prev.paused {
+				// the MSGTTL of the queued messages starts over
+				_, qerr = s.setHookQueueTTL(hook.Name, hook.retry.msgTTL)
+			}
+			hook.pause = hookPause{}
+		} else if prev.paused {
+			// keep the counters
+			hook.pause.drop = pause.drop
+			hook.pause.maxQueue = pause.maxQueue
+		} else {
+			// the queued messages don't expire while paused
+			hook.pause = pause
+			hook.pause.queued, qerr = s.setHookQueueTTL(hook.Name, 0)
+		}
+		if qerr != nil {
+			return false
+		}
+		if hook.pause != prev {
+			d.updated = true
+		}
+		hook.cond.Broadcast()
+		count++
+		return true
+	})
+	if qerr != nil {
+		return NOMessage, d, qerr
+	}
+	d.timestamp = time.Now()
+
+	switch msg.OutputType {
+	case JSON:
+		return resp.StringValue(`{"ok":true,"count":` + strconv.Itoa(count) +
+			`,"elapsed":"` + time.Since(start).String() + "\"}"), d, nil
+	case RESP:
+		return resp.IntegerValue(count), d, nil
+	}
+	return NOMessage, d, nil
+}
+
+// setHookQueueTTL sets the ttl of the messages in the queue of a hook, and
+// returns the number of messages. A zero ttl keeps the messages forever.
+func (s *Server) setHookQueueTTL(name string, ttl time.Duration) (int, error) {
+	pivot := `{"hook":` + jsonString(name) + `}`
+	var count int
+	err := s.qdb.Update(func(tx *buntdb.Tx) error {
+		var keys, vals []string
+		err := tx.AscendEqual("hooks", pivot, func(key, val string) bool {
+			keys = append(keys, key)
+			vals = append(vals, val)
+			return true
+		})
+		if err != nil {
+			return err
+		}
+		for i, key := range keys {
+			if _, _, err := tx.Set(key, vals[i], hookQueueOptions(ttl)); err != nil {
+				return err
+			}
+		}
+		count = len(keys)
+		return nil
+	})
+	return count, err
+}
+
+// hookQueueOptions returns the options of a message in the queue of a hook.
+func hookQueueOptions(ttl time.Duration) *buntdb.SetOptions {
+	if ttl <= 0 {
+		return nil
+	}
+	return &buntdb.SetOptions{Expires: true, TTL: ttl}
+}
+
+// queueTTL returns the ttl of the messages queued for the hook, which is zero
+// while the hook is paused.
+func (h *Hook) queueTTL() time.Duration {
+	if h.pause.paused {
+		return 0
+	}
+	return h.retry.msgTTL
+}
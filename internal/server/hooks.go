@@ -18,9 +18,109 @@ import (
 	"github.com/aiqia-dev/meridian/internal/log"
 )
 
-var hookLogSetDefaults = &buntdb.SetOptions{
-	Expires: true, // automatically delete after 30 seconds
-	TTL:     time.Second * 30,
+// hookDefaultRetry is the retry policy used by hooks that do not provide
+// their own RETRY, BACKOFF or MSGTTL options. Failed messages are retried
+// every 500ms until they are 30 seconds old.
+var hookDefaultRetry = hookRetryPolicy{
+	backoffMin: time.Second / 2,
+	backoffMax: time.Second / 2,
+	msgTTL:     time.Second * 30,
+}
+
+// hookRetryPolicy describes how undeliverable hook messages are retried
+// before they are dropped, or moved to the dead-letter queue.
+type hookRetryPolicy struct {
+	maxAttempts int           // max send attempts per message, 0 = unlimited
+	backoffMin  time.Duration // delay after the first failed attempt
+	backoffMax  time.Duration // upper limit of the exponential backoff
+	msgTTL      time.Duration // how long a message stays in the queue
+	dlq         hookDLQPolicy // dead-letter queue, off by default
+}
+
+// backoff returns the delay before the next attempt, following a number of
+// failed attempts.
+func (p hookRetryPolicy) backoff(attempts int) time.Duration {
+	delay := p.backoffMin
+	for i := 1; i < attempts && delay < p.backoffMax; i++ {
+		delay *= 2
+	}
+	if delay > p.backoffMax {
+		delay = p.backoffMax
+	}
+	return delay
+}
+
+// args returns the SETHOOK arguments that reproduce the policy. Values that
+// match the default policy are omitted.
+func (p hookRetryPolicy) args() []string {
+	var args []string
+	if p.maxAttempts != hookDefaultRetry.maxAttempts {
+		args = append(args, "retry", strconv.Itoa(p.maxAttempts))
+	}
+	if p.backoffMin != hookDefaultRetry.backoffMin ||
+		p.backoffMax != hookDefaultRetry.backoffMax {
+		args = append(args, "backoff", formatSeconds(p.backoffMin),
+			formatSeconds(p.backoffMax))
+	}
+	if p.msgTTL != hookDefaultRetry.msgTTL {
+		args = append(args, "msgttl", formatSeconds(p.msgTTL))
+	}
+	if p.dlq.maxLen > 0 {
+		args = append(args, "dlq", strconv.Itoa(p.dlq.maxLen),
+			formatSeconds(p.dlq.ttl))
+	}
+	return args
+}
+
+var errHookOwner = errors.New("OWNER is only allowed for the default user")
+var errHookNotOwned = errors.New(
+	"NOPERM the hook is owned by another user")
+
+// redactedSecret replaces hook secrets in command output.
+const redactedSecret = "********"
+
+// redactHookArgs returns a copy of SETHOOK arguments with the secrets
+// replaced by redactedSecret.
+func redactHookArgs(args []string) []string {
+	args = append([]string(nil), args...)
+	for i := 3; i < len(args)-1; i++ {
+		switch strings.ToLower(args[i]) {
+		case "nearby", "within", "intersects":
+			// the options always precede the search
+			return args
+		case "secret":
+			args[i+1] = redactedSecret
+			i++
+		}
+	}
+	return args
+}
+
+// hookBatchPolicy describes how hook messages are grouped into batches.
+type hookBatchPolicy struct {
+	maxEvents int           // max messages per batch, 0 = no batching
+	maxDelay  time.Duration // max time to wait for a batch to fill up
+}
+
+// args returns the SETHOOK arguments that reproduce the policy.
+func (p hookBatchPolicy) args() []string {
+	if p.maxEvents == 0 {
+		return nil
+	}
+	return []string{"batch", strconv.Itoa(p.maxEvents),
+		formatSeconds(p.maxDelay)}
+}
+
+func formatSeconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', -1, 64)
+}
+
+func parseSeconds(s string) (time.Duration, error) {
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || v <= 0 {
+		return 0, errInvalidArgument(s)
+	}
+	return time.Duration(v * float64(time.Second)), nil
 }
 
 func byHookName(a, b interface{}) bool {
@@ -60,6 +160,13 @@ func (s *Server) cmdSetHook(msg *Message) (
 	var types map[string]bool
 	var expires float64
 	var expiresSet bool
+	retry := hookDefaultRetry
+	var batch hookBatchPolicy
+	var secrets []string
+	var owner string
+	var stale time.Duration
+	var staleExit bool
+	var schedule *hookSchedule
 	metaMap := make(map[string]string)
 	for {
 		commandvs = vs
@@ -93,6 +200,117 @@ func (s *Server) cmdSetHook(msg *Message) (
 			expires = v
 			expiresSet = true
 			continue
+		case "retry":
+			var s string
+			if vs, s, ok = tokenval(vs); !ok || s == "" {
+				return NOMessage, d, errInvalidNumberOfArguments
+			}
+			n, err := strconv.ParseUint(s, 10, 32)
+			if err != nil {
+				return NOMessage, d, errInvalidArgument(s)
+			}
+			retry.maxAttempts = int(n)
+			continue
+		case "backoff":
+			var smin, smax string
+			if vs, smin, ok = tokenval(vs); !ok || smin == "" {
+				return NOMessage, d, errInvalidNumberOfArguments
+			}
+			if vs, smax, ok = tokenval(vs); !ok || smax == "" {
+				return NOMessage, d, errInvalidNumberOfArguments
+			}
+			if retry.backoffMin, err = parseSeconds(smin); err != nil {
+				return NOMessage, d, err
+			}
+			if retry.backoffMax, err = parseSeconds(smax); err != nil {
+				return NOMessage, d, err
+			}
+			if retry.backoffMax < retry.backoffMin {
+				return NOMessage, d, errInvalidArgument(smax)
+			}
+			continue
+		case "batch":
+			var sevents, sdelay string
+			if vs, sevents, ok = tokenval(vs); !ok || sevents == "" {
+				return NOMessage, d, errInvalidNumberOfArguments
+			}
+			if vs, sdelay, ok = tokenval(vs); !ok || sdelay == "" {
+				return NOMessage, d, errInvalidNumberOfArguments
+			}
+			n, err := strconv.ParseUint(sevents, 10, 32)
+			if err != nil || n == 0 {
+				return NOMessage, d, errInvalidArgument(sevents)
+			}
+			batch.maxEvents = int(n)
+			if batch.maxDelay, err = parseSeconds(sdelay); err != nil {
+				return NOMessage, d, err
+			}
+			continue
+		case "secret":
+			var secret string
+			if vs, secret, ok = tokenval(vs); !ok || secret == "" {
+				return NOMessage, d, errInvalidNumberOfArguments
+			}
+			if channel || len(secrets) == 2 {
+				return NOMessage, d, errInvalidArgument(cmd)
+			}
+			secrets = append(secrets, secret)
+			continue
+		case "owner":
+			if vs, owner, ok = tokenval(vs); !ok || owner == "" {
+				return NOMessage, d, errInvalidNumberOfArguments
+			}
+			if msg.acl != nil {
+				return NOMessage, d, errHookOwner
+			}
+			continue
+		case "stale":
+			var s string
+			if vs, s, ok = tokenval(vs); !ok || s == "" {
+				return NOMessage, d, errInvalidNumberOfArguments
+			}
+			if stale, err = parseSeconds(s); err != nil {
+				return NOMessage, d, err
+			}
+			if len(vs) > 0 && strings.ToLower(vs[0]) == "exit" {
+				staleExit = true
+				vs = vs[1:]
+			}
+			continue
+		case "schedule", "active":
+			if schedule != nil {
+				return NOMessage, d, errInvalidArgument(cmd)
+			}
+			if schedule, vs, err = parseHookSchedule(cmdlc, vs); err != nil {
+				return NOMessage, d, err
+			}
+			continue
+		case "msgttl":
+			var s string
+			if vs, s, ok = tokenval(vs); !ok || s == "" {
+				return NOMessage, d, errInvalidNumberOfArguments
+			}
+			if retry.msgTTL, err = parseSeconds(s); err != nil {
+				return NOMessage, d, err
+			}
+			continue
+		case "dlq":
+			var smax, sttl string
+			if vs, smax, ok = tokenval(vs); !ok || smax == "" {
+				return NOMessage, d, errInvalidNumberOfArguments
+			}
+			if vs, sttl, ok = tokenval(vs); !ok || sttl == "" {
+				return NOMessage, d, errInvalidNumberOfArguments
+			}
+			n, err := strconv.ParseUint(smax, 10, 32)
+			if err != nil || n == 0 {
+				return NOMessage, d, errInvalidArgument(smax)
+			}
+			retry.dlq.maxLen = int(n)
+			if retry.dlq.ttl, err = parseSeconds(sttl); err != nil {
+				return NOMessage, d, err
+			}
+			continue
 		case "nearby":
 			types = nearbyTypes
 		case "within", "intersects":
@@ -110,6 +328,16 @@ func (s *Server) cmdSetHook(msg *Message) (
 	if !args.fence {
 		return NOMessage, d, errors.New("missing FENCE argument")
 	}
+	if stale > 0 && args.roam.on {
+		return NOMessage, d, errors.New("STALE is not allowed with ROAM")
+	}
+	if msg.acl != nil {
+		// the hooks of an ACL user are owned by the user
+		owner = msg.acl.name
+	}
+	if err := s.checkHookQuota(owner, name); err != nil {
+		return NOMessage, d, err
+	}
 	args.cmd = cmdlc
 	cmsg := &Message{}
 	*cmsg = *msg
@@ -132,6 +360,14 @@ func (s *Server) cmdSetHook(msg *Message) (
 		channel:   channel,
 		cond:      sync.NewCond(&sync.Mutex{}),
 		counter:   &s.statsTotalMsgsSent,
+		retry:     retry,
+		batch:     batch,
+		secrets:   secrets,
+		owner:     owner,
+		stats:     newHookStats(),
+		stale:     stale,
+		staleExit: staleExit,
+		schedule:  schedule,
 	}
 	if expiresSet {
 		hook.expires =
@@ -151,6 +387,9 @@ func (s *Server) cmdSetHook(msg *Message) (
 	}
 	prevHook, _ := s.hooks.Get(&Hook{Name: name}).(*Hook)
 	if prevHook != nil {
+		if !msg.acl.ownsHook(prevHook) {
+			return NOMessage, d, errHookNotOwned
+		}
 		if prevHook.channel != channel {
 			return NOMessage, d,
 				errors.New("hooks and channels cannot share the same name")
@@ -170,6 +409,10 @@ func (s *Server) cmdSetHook(msg *Message) (
 			}
 		}
 		prevHook.Close()
+		// a new definition of a paused hook stays paused
+		prevHook.cond.L.Lock()
+		hook.pause = prevHook.pause
+		prevHook.cond.L.Unlock()
 		s.hooks.Delete(prevHook)
 		s.hooksOut.Delete(prevHook)
 		if !prevHook.expires.IsZero() {
@@ -180,11 +423,34 @@ func (s *Server) cmdSetHook(msg *Message) (
 
 	d.updated = true
 	d.timestamp = time.Now()
+	if msg.acl != nil {
+		// record the owner in the aof
+		i := 3
+		if channel {
+			i = 2
+		}
+		d.aofArgs = make([]string, 0, len(msg.Args)+2)
+		d.aofArgs = append(d.aofArgs, msg.Args[:i]...)
+		d.aofArgs = append(d.aofArgs, "owner", owner)
+		d.aofArgs = append(d.aofArgs, msg.Args[i:]...)
+	}
 
 	s.hooks.Set(hook)
+	s.hookDLQs.set(name, retry.dlq)
 	if hook.Fence.detect == nil || hook.Fence.detect["outside"] {
 		s.hooksOut.Set(hook)
 	}
+	s.stale.setHook(name, hook)
+	delete(s.scheduled, name)
+	if hook.schedule != nil {
+		if s.scheduled == nil {
+			s.scheduled = make(map[string]*Hook)
+		}
+		s.scheduled[name] = hook
+	}
+	if hook.stale > 0 && s.loadedAndReady.Load() {
+		s.scheduleStaleHook(hook, time.Now())
+	}
 
 	// remove previous hook from spatial index
 	if prevHook != nil && prevHook.Fence != nil && prevHook.Fence.obj != nil {
@@ -249,11 +515,20 @@ func (s *Server) cmdDELHOOKop(name string, channel bool) (updated bool) {
 	// remove hook from maps
 	s.hooks.Delete(hook)
 	s.hooksOut.Delete(hook)
+	s.hookDLQs.set(hook.Name, hookDLQPolicy{})
 	if !hook.expires.IsZero() {
 		s.hookExpires.Delete(hook)
 	}
 	// remove any hook / object connections
 	s.groupDisconnectHook(hook.Name)
+	s.stale.setHook(hook.Name, nil)
+	delete(s.scheduled, hook.Name)
+	// remove pending and dead-lettered messages
+	if !hook.channel {
+		if _, err := s.purgeHookQueue(hook.Name, true); err != nil {
+			log.Errorf("delhook: %v", err)
+		}
+	}
 	// remove hook from spatial index
 	if hook.Fence != nil && hook.Fence.obj != nil {
 		rect := hook.Fence.obj.Rect()
@@ -287,7 +562,10 @@ func (s *Server) cmdDelHook(msg *Message) (
 		return NOMessage, d, errInvalidNumberOfArguments
 	}
 
-	d.updated = s.cmdDELHOOKop(name, channel)
+	if hook, _ := s.hooks.Get(&Hook{Name: name}).(*Hook); hook != nil &&
+		msg.acl.ownsHook(hook) {
+		d.updated = s.cmdDELHOOKop(name, channel)
+	}
 	d.timestamp = time.Now()
 
 	switch msg.OutputType {
@@ -320,7 +598,7 @@ func (s *Server) cmdPDelHook(msg *Message) (
 
 	count := 0
 	var hooks []*Hook
-	s.forEachHookByPattern(pattern, channel, func(hook *Hook) bool {
+	s.forEachHookByPattern(msg.acl, pattern, channel, func(hook *Hook) bool {
 		hooks = append(hooks, hook)
 		return true
 	})
@@ -344,8 +622,10 @@ func (s *Server) cmdPDelHook(msg *Message) (
 	return
 }
 
+// forEachHookByPattern iterates over the hooks, or channels, that match a
+// pattern and that are owned by an ACL user.
 func (s *Server) forEachHookByPattern(
-	pattern string, channel bool, iter func(hook *Hook) bool,
+	acl *aclUser, pattern string, channel bool, iter func(hook *Hook) bool,
 ) {
 	g := glob.Parse(pattern, false)
 	hasUpperLimit := g.Limits[1] != ""
@@ -354,7 +634,7 @@ func (s *Server) forEachHookByPattern(
 		if hasUpperLimit && hook.Name > g.Limits[1] {
 			return false
 		}
-		if hook.channel == channel {
+		if hook.channel == channel && acl.ownsHook(hook) {
 			match, _ := glob.Match(pattern, hook.Name)
 			if match {
 				return iter(hook)
@@ -391,7 +671,7 @@ func (s *Server) cmdHooks(msg *Message) (
 			buf.WriteString(`"hooks":[`)
 		}
 		var i int
-		s.forEachHookByPattern(pattern, channel, func(hook *Hook) bool {
+		s.forEachHookByPattern(msg.acl, pattern, channel, func(hook *Hook) bool {
 			var ttl = -1
 			if !hook.expires.IsZero() {
 				ttl = int(hook.expires.Sub(start).Seconds())
@@ -406,6 +686,20 @@ func (s *Server) cmdHooks(msg *Message) (
 			buf.WriteString(`"name":` + jsonString(hook.Name))
 			buf.WriteString(`,"key":` + jsonString(hook.Key))
 			buf.WriteString(`,"ttl":` + strconv.Itoa(ttl))
+			if hook.owner != "" {
+				buf.WriteString(`,"owner":` + jsonString(hook.owner))
+			}
+			if hook.stale > 0 {
+				buf.WriteString(`,"stale":` + formatSeconds(hook.stale))
+			}
+			if hook.schedule != nil {
+				active, _ := hook.schedule.window(start)
+				buf.WriteString(`,"schedule":` + hook.schedule.json())
+				buf.WriteString(`,"active":` + strconv.FormatBool(active))
+			}
+			if hook.pause.paused {
+				buf.WriteString(`,"paused":true`)
+			}
 			if !channel {
 				buf.WriteString(`,"endpoints":[`)
 				for i, endpoint := range hook.Endpoints {
@@ -415,6 +709,16 @@ func (s *Server) cmdHooks(msg *Message) (
 					buf.WriteString(jsonString(endpoint))
 				}
 				buf.WriteString(`]`)
+				if len(hook.secrets) > 0 {
+					buf.WriteString(`,"secrets":[`)
+					for i := range hook.secrets {
+						if i > 0 {
+							buf.WriteByte(',')
+						}
+						buf.WriteString(jsonString(redactedSecret))
+					}
+					buf.WriteString(`]`)
+				}
 			}
 			buf.WriteString(`,"command":[`)
 			for i, v := range hook.Message.Args {
@@ -441,7 +745,7 @@ func (s *Server) cmdHooks(msg *Message) (
 		return resp.StringValue(buf.String()), nil
 	case RESP:
 		var vals []resp.Value
-		s.forEachHookByPattern(pattern, channel, func(hook *Hook) bool {
+		s.forEachHookByPattern(msg.acl, pattern, channel, func(hook *Hook) bool {
 			var hvals []resp.Value
 			hvals = append(hvals, resp.StringValue(hook.Name))
 			hvals = append(hvals, resp.StringValue(hook.Key))
@@ -461,6 +765,30 @@ func (s *Server) cmdHooks(msg *Message) (
 				metas = append(metas, resp.StringValue(meta.Value))
 			}
 			hvals = append(hvals, resp.ArrayValue(metas))
+			var opts []resp.Value
+			if hook.owner != "" {
+				opts = append(opts, resp.StringValue("owner"),
+					resp.StringValue(hook.owner))
+			}
+			if hook.stale > 0 {
+				opts = append(opts, resp.StringValue("stale"),
+					resp.StringValue(formatSeconds(hook.stale)))
+			}
+			if hook.schedule != nil {
+				active, _ := hook.schedule.window(start)
+				var svals []resp.Value
+				for _, arg := range hook.schedule.args() {
+					svals = append(svals, resp.StringValue(arg))
+				}
+				opts = append(opts, resp.StringValue("schedule"),
+					resp.ArrayValue(svals), resp.StringValue("active"),
+					resp.StringValue(strconv.FormatBool(active)))
+			}
+			if hook.pause.paused {
+				opts = append(opts, resp.StringValue("paused"),
+					resp.StringValue("true"))
+			}
+			hvals = append(hvals, resp.ArrayValue(opts))
 			vals = append(vals, resp.ArrayValue(hvals))
 			return true
 		})
@@ -488,6 +816,20 @@ type Hook struct {
 	expires    time.Time
 	counter    *atomic.Int64 // counter that grows when a message was sent
 	sig        int
+	retry      hookRetryPolicy
+	stats      *hookStats
+	failKey    string // log entry that is currently failing to send
+	attempts   int    // number of failed attempts for failKey
+	batch      hookBatchPolicy
+	queued     int           // messages queued since the last batch was sent
+	secrets    []string      // secrets for signing deliveries, max two
+	owner      string        // ACL user that set the hook
+	stale      time.Duration // max time without updates inside the fence
+	staleExit  bool          // send an exit event after the stale event
+	schedule   *hookSchedule // window of the events, nil = always
+	schedOpen  bool          // the window was active on the last check
+	window     hookWindow    // cached schedule window
+	pause      hookPause     // HOOKPAUSE state
 }
 
 // Expires returns when the hook expires. Required by the expire.Item interface.
@@ -500,10 +842,19 @@ func (h *Hook) Equals(hook *Hook) bool {
 	if h.Key != hook.Key ||
 		h.Name != hook.Name ||
 		len(h.Endpoints) != len(hook.Endpoints) ||
-		len(h.Metas) != len(hook.Metas) {
+		len(h.Metas) != len(hook.Metas) ||
+		len(h.secrets) != len(hook.secrets) || h.owner != hook.owner {
 		return false
 	}
-	if !h.expires.Equal(hook.expires) {
+	for i, secret := range h.secrets {
+		if secret != hook.secrets[i] {
+			return false
+		}
+	}
+	if !h.expires.Equal(hook.expires) || h.retry != hook.retry ||
+		h.batch != hook.batch || h.stale != hook.stale ||
+		h.staleExit != hook.staleExit ||
+		!hookSchedulesEqual(h.schedule, hook.schedule) {
 		return false
 	}
 	for i, endpoint := range h.Endpoints {
@@ -582,12 +933,21 @@ func (h *Hook) Close() {
 // Signal can be called at any point to wake up the hook and
 // notify the manager that there may be something new in the queue.
 func (h *Hook) Signal() {
+	h.signalQueued(0)
+}
+
+// signalQueued wakes up the hook and notifies the manager that a number of
+// messages were added to the queue.
+func (h *Hook) signalQueued(n int) {
 	if h.channel {
 		// nothing to signal for channels
 		return
 	}
 	h.cond.L.Lock()
 	h.sig++
+	if h.batch.maxEvents > 1 {
+		h.queued += n
+	}
 	h.cond.Broadcast()
 	h.cond.L.Unlock()
 }
@@ -604,6 +964,17 @@ func (h *Hook) manager() {
 			// the hook has closed, end manager
 			return
 		}
+		if h.pause.paused {
+			// wait until the hook is resumed
+			h.cond.Wait()
+			continue
+		}
+		if h.queued > 0 && h.queued < h.batch.maxEvents {
+			// give the batch a chance to fill up
+			h.waitBatch(time.Now().Add(h.batch.maxDelay))
+			continue
+		}
+		h.queued = 0
 		sig = h.sig
 		// unlock/logk the hook and send outgoing messages
 		if !func() bool {
@@ -611,8 +982,8 @@ func (h *Hook) manager() {
 			defer h.cond.L.Lock()
 			return h.proc()
 		}() {
-			// a send failed, try again in a moment
-			time.Sleep(time.Second / 2)
+			// a send failed, try again after the backoff delay
+			h.waitUntil(time.Now().Add(h.retry.backoff(h.attempts)))
 			continue
 		}
 		if sig != h.sig {
@@ -624,6 +995,35 @@ func (h *Hook) manager() {
 	}
 }
 
+// waitUntil waits on the hook condition until the deadline passes or the
+// hook is closed. The caller must hold the hook lock.
+func (h *Hook) waitUntil(deadline time.Time) {
+	t := time.AfterFunc(time.Until(deadline), func() {
+		h.cond.L.Lock()
+		h.cond.Broadcast()
+		h.cond.L.Unlock()
+	})
+	defer t.Stop()
+	for !h.closed && time.Now().Before(deadline) {
+		h.cond.Wait()
+	}
+}
+
+// waitBatch waits until the batch is full, the deadline passes or the hook
+// is closed. The caller must hold the hook lock.
+func (h *Hook) waitBatch(deadline time.Time) {
+	t := time.AfterFunc(time.Until(deadline), func() {
+		h.cond.L.Lock()
+		h.queued = 0
+		h.cond.Broadcast()
+		h.cond.L.Unlock()
+	})
+	defer t.Stop()
+	for !h.closed && h.queued > 0 && h.queued < h.batch.maxEvents {
+		h.cond.Wait()
+	}
+}
+
 // proc processes queued hook logs.
 // returning true will indicate that all log entries have been
 // successfully handled.
@@ -672,48 +1072,91 @@ func (h *Hook) proc() (ok bool) {
 		return false
 	}
 
-	// send each val. on failure reinsert that one and all of the following
-	for i, key := range keys {
-		val := vals[i]
+	// send the vals in batches. on failure reinsert that batch and all of
+	// the following
+	size := max(h.batch.maxEvents, 1)
+	for i := 0; i < len(keys); i += size {
+		key := keys[i]
+		batch := vals[i:min(i+size, len(vals))]
 		idx := stringToUint64(key[len(hookLogPrefix):])
 		var sent bool
 		for _, endpoint := range h.Endpoints {
-			err := h.epm.Send(endpoint, val)
+			var err error
+			if h.batch.maxEvents > 0 {
+				err = h.epm.SendBatch(endpoint, batch, h.secrets...)
+			} else {
+				err = h.epm.Send(endpoint, batch[0], h.secrets...)
+			}
 			if err != nil {
 				log.Debugf("Endpoint connect/send error: %v: %v: %v",
 					idx, endpoint, err)
+				h.stats.addFailed(endpoint, err)
 				continue
 			}
 			log.Debugf("Endpoint send ok: %v: %v: %v", idx, endpoint, err)
 			sent = true
-			h.counter.Add(1)
+			h.counter.Add(int64(len(batch)))
+			h.stats.addSent(endpoint, len(batch))
 			break
 		}
-		if !sent {
-			// failed to send. try to reinsert the remaining.
-			// if this fails we lose log entries.
-			keys = keys[i:]
-			vals = vals[i:]
-			ttls = ttls[i:]
-			h.db.Update(func(tx *buntdb.Tx) error {
-				for i, key := range keys {
-					val := vals[i]
-					ttl := ttls[i] - time.Since(start)
-					if ttl > 0 {
-						opts := &buntdb.SetOptions{
-							Expires: true,
-							TTL:     ttl,
-						}
-						_, _, err := tx.Set(key, val, opts)
-						if err != nil {
-							return err
-						}
+		if sent {
+			h.failKey, h.attempts = "", 0
+			continue
+		}
+		if h.failKey != key {
+			h.failKey, h.attempts = key, 0
+		}
+		h.attempts++
+		if h.retry.maxAttempts > 0 && h.attempts >= h.retry.maxAttempts {
+			// out of attempts, move on to the next batch
+			log.Debugf("Endpoint dead letter: %v: %v", idx, h.Name)
+			err := h.db.Update(func(tx *buntdb.Tx) error {
+				for j, val := range batch {
+					err := setHookDeadLetter(tx, keys[i+j], val, h.retry.dlq)
+					if err != nil {
+						return err
 					}
 				}
 				return nil
 			})
-			return false
+			if err != nil {
+				log.Error(err)
+			}
+			h.failKey, h.attempts = "", 0
+			continue
 		}
+		// failed to send. reinsert the remaining, the ones that have
+		// outlived their ttl are moved to the dead-letter queue.
+		keys = keys[i:]
+		vals = vals[i:]
+		ttls = ttls[i:]
+		err := h.db.Update(func(tx *buntdb.Tx) error {
+			for i, key := range keys {
+				val := vals[i]
+				ttl := ttls[i] - time.Since(start)
+				if ttl <= 0 {
+					err := setHookDeadLetter(tx, key, val, h.retry.dlq)
+					if err != nil {
+						return err
+					}
+					continue
+				}
+				opts := &buntdb.SetOptions{
+					Expires: true,
+					TTL:     ttl,
+				}
+				_, _, err := tx.Set(key, val, opts)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			// if this fails we lose log entries.
+			log.Error(err)
+		}
+		return false
 	}
 	return true
 }
@@ -0,0 +1,394 @@
+package server
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tidwall/buntdb"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/resp"
+)
+
+const hookDLQPrefix = "hook:dlq:"
+
+// hookStats holds the delivery statistics of a hook, per endpoint.
+type hookStats struct {
+	mu        sync.Mutex
+	endpoints map[string]*hookEndpointStats
+}
+
+type hookEndpointStats struct {
+	sent        int64
+	failed      int64
+	lastErr     string
+	lastErrTime time.Time
+}
+
+func newHookStats() *hookStats {
+	return &hookStats{endpoints: make(map[string]*hookEndpointStats)}
+}
+
+func (hs *hookStats) get(endpoint string) *hookEndpointStats {
+	es := hs.endpoints[endpoint]
+	if es == nil {
+		es = new(hookEndpointStats)
+		hs.endpoints[endpoint] = es
+	}
+	return es
+}
+
+func (hs *hookStats) addSent(endpoint string, n int) {
+	hs.mu.Lock()
+	hs.get(endpoint).sent += int64(n)
+	hs.mu.Unlock()
+}
+
+func (hs *hookStats) addFailed(endpoint string, err error) {
+	hs.mu.Lock()
+	es := hs.get(endpoint)
+	es.failed++
+	es.lastErr = err.Error()
+	es.lastErrTime = time.Now()
+	hs.mu.Unlock()
+}
+
+// snapshot returns a copy of the stats for the provided endpoints.
+func (hs *hookStats) snapshot(endpoints []string) []hookEndpointStats {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+	all := make([]hookEndpointStats, len(endpoints))
+	for i, endpoint := range endpoints {
+		if es := hs.endpoints[endpoint]; es != nil {
+			all[i] = *es
+		}
+	}
+	return all
+}
+
+// hookDLQPolicy describes the dead-letter queue of a hook, which keeps up
+// to maxLen undeliverable messages, for ttl. A hook without a dead-letter
+// queue drops the messages.
+type hookDLQPolicy struct {
+	maxLen int
+	ttl    time.Duration
+}
+
+// hookDLQTable holds the dead-letter queue policies by hook name, for the
+// messages that expire in the queue database, outside of the server lock.
+type hookDLQTable struct {
+	mu       sync.Mutex
+	policies map[string]hookDLQPolicy
+}
+
+func (t *hookDLQTable) set(name string, p hookDLQPolicy) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if p.maxLen == 0 {
+		delete(t.policies, name)
+		return
+	}
+	if t.policies == nil {
+		t.policies = make(map[string]hookDLQPolicy)
+	}
+	t.policies[name] = p
+}
+
+func (t *hookDLQTable) get(name string) hookDLQPolicy {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.policies[name]
+}
+
+// setHookDeadLetter moves a hook log entry to the dead-letter queue, or
+// drops it when the hook has no dead-letter queue. The oldest messages are
+// dropped once the queue is full.
+func setHookDeadLetter(tx *buntdb.Tx, key, val string, p hookDLQPolicy,
+) error {
+	if p.maxLen == 0 {
+		return nil
+	}
+	opts := &buntdb.SetOptions{Expires: true, TTL: p.ttl}
+	_, _, err := tx.Set(hookDLQPrefix+key[len(hookLogPrefix):], val, opts)
+	if err != nil {
+		return err
+	}
+	var keys []string
+	pivot := `{"hook":` + jsonString(gjson.Get(val, "hook").String()) + `}`
+	err = tx.AscendEqual("hookdlq", pivot, func(key, val string) bool {
+		keys = append(keys, key)
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	for len(keys) > p.maxLen {
+		if _, err := tx.Delete(keys[0]); err != nil &&
+			err != buntdb.ErrNotFound {
+			return err
+		}
+		keys = keys[1:]
+	}
+	return nil
+}
+
+// hookLogExpired is called by the queue database for each expired entry.
+// Hook log entries are moved to the dead-letter queue of their hook.
+func (s *Server) hookLogExpired(key, val string, tx *buntdb.Tx) error {
+	if _, err := tx.Delete(key); err != nil && err != buntdb.ErrNotFound {
+		return err
+	}
+	if strings.HasPrefix(key, hookLogPrefix) {
+		dlq := s.hookDLQs.get(gjson.Get(val, "hook").String())
+		return setHookDeadLetter(tx, key, val, dlq)
+	}
+	return nil
+}
+
+// hookQueueCounts returns the number of pending and dead-lettered messages
+// for a hook.
+func (s *Server) hookQueueCounts(name string) (pending, dead int, err error) {
+	pivot := `{"hook":` + jsonString(name) + `}`
+	err = s.qdb.View(func(tx *buntdb.Tx) error {
+		err := tx.AscendEqual("hooks", pivot, func(key, val string) bool {
+			pending++
+			return true
+		})
+		if err != nil {
+			return err
+		}
+		return tx.AscendEqual("hookdlq", pivot, func(key, val string) bool {
+			dead++
+			return true
+		})
+	})
+	return pending, dead, err
+}
+
+// purgeHookQueue deletes all dead-lettered messages for a hook. The pending
+// messages are deleted too when the pending param is true.
+func (s *Server) purgeHookQueue(name string, pending bool) (int, error) {
+	pivot := `{"hook":` + jsonString(name) + `}`
+	var count int
+	err := s.qdb.Update(func(tx *buntdb.Tx) error {
+		var keys []string
+		iter := func(key, val string) bool {
+			keys = append(keys, key)
+			return true
+		}
+		if err := tx.AscendEqual("hookdlq", pivot, iter); err != nil {
+			return err
+		}
+		count = len(keys)
+		if pending {
+			if err := tx.AscendEqual("hooks", pivot, iter); err != nil {
+				return err
+			}
+		}
+		for _, key := range keys {
+			if _, err := tx.Delete(key); err != nil &&
+				err != buntdb.ErrNotFound {
+				return err
+			}
+		}
+		return nil
+	})
+	return count, err
+}
+
+// replayHookQueue moves all dead-lettered messages for a hook back into the
+// hook log.
+func (s *Server) replayHookQueue(hook *Hook) (int, error) {
+	pivot := `{"hook":` + jsonString(hook.Name) + `}`
+	var count int
+	err := s.qdb.Update(func(tx *buntdb.Tx) error {
+		var keys, vals []string
+		err := tx.AscendEqual("hookdlq", pivot, func(key, val string) bool {
+			keys = append(keys, key)
+			vals = append(vals, val)
+			return true
+		})
+		if err != nil {
+			return err
+		}
+		opts := hookQueueOptions(hook.queueTTL())
+		for i, key := range keys {
+			if _, err := tx.Delete(key); err != nil {
+				return err
+			}
+			s.qidx++ // increment the log id
+			key := hookLogPrefix + uint64ToString(s.qidx)
+			if _, _, err := tx.Set(key, vals[i], opts); err != nil {
+				return err
+			}
+		}
+		count = len(keys)
+		_, _, err = tx.Set("hook:idx", uint64ToString(s.qidx), nil)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	if hook.pause.paused {
+		hook.pause.queued += count
+	}
+	hook.Signal()
+	return count, nil
+}
+
+// HOOKSTATS [pattern]
+func (s *Server) cmdHookStats(msg *Message) (resp.Value, error) {
+	start := time.Now()
+	vs := msg.Args[1:]
+
+	pattern := "*"
+	var ok bool
+	if len(vs) > 0 {
+		if vs, pattern, ok = tokenval(vs); !ok || pattern == "" {
+			return NOMessage, errInvalidNumberOfArguments
+		}
+	}
+	if len(vs) != 0 {
+		return NOMessage, errInvalidNumberOfArguments
+	}
+
+	var hooks []*Hook
+	s.forEachHookByPattern(msg.acl, pattern, false, func(hook *Hook) bool {
+		hooks = append(hooks, hook)
+		return true
+	})
+
+	var buf bytes.Buffer
+	var vals []resp.Value
+	for i, hook := range hooks {
+		pending, dead, err := s.hookQueueCounts(hook.Name)
+		if err != nil {
+			return NOMessage, err
+		}
+		stats := hook.stats.snapshot(hook.Endpoints)
+		switch msg.OutputType {
+		case JSON:
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			buf.WriteString(`{"name":` + jsonString(hook.Name))
+			buf.WriteString(`,"pending":` + strconv.Itoa(pending))
+			buf.WriteString(`,"dead":` + strconv.Itoa(dead))
+			buf.WriteString(`,"paused":` + strconv.FormatBool(hook.pause.paused))
+			if hook.pause.paused {
+				buf.WriteString(`,"paused_queued":` +
+					strconv.Itoa(hook.pause.queued))
+				buf.WriteString(`,"paused_dropped":` +
+					strconv.FormatInt(hook.pause.dropped, 10))
+			}
+			buf.WriteString(`,"endpoints":[`)
+			for i, es := range stats {
+				if i > 0 {
+					buf.WriteByte(',')
+				}
+				buf.WriteString(`{"endpoint":` + jsonString(hook.Endpoints[i]))
+				buf.WriteString(`,"sent":` + strconv.FormatInt(es.sent, 10))
+				buf.WriteString(`,"failed":` + strconv.FormatInt(es.failed, 10))
+				buf.WriteString(`,"last_error":` + jsonString(es.lastErr))
+				if !es.lastErrTime.IsZero() {
+					buf.WriteString(`,"last_error_time":` +
+						jsonString(es.lastErrTime.Format(time.RFC3339)))
+				}
+				buf.WriteByte('}')
+			}
+			buf.WriteString(`]}`)
+		case RESP:
+			var evals []resp.Value
+			for i, es := range stats {
+				evals = append(evals, resp.ArrayValue([]resp.Value{
+					resp.StringValue(hook.Endpoints[i]),
+					resp.StringValue("sent"), resp.IntegerValue(int(es.sent)),
+					resp.StringValue("failed"), resp.IntegerValue(int(es.failed)),
+					resp.StringValue("last_error"), resp.StringValue(es.lastErr),
+				}))
+			}
+			vals = append(vals, resp.ArrayValue([]resp.Value{
+				resp.StringValue(hook.Name),
+				resp.StringValue("pending"), resp.IntegerValue(pending),
+				resp.StringValue("dead"), resp.IntegerValue(dead),
+				resp.StringValue("paused"), resp.BoolValue(hook.pause.paused),
+				resp.StringValue("endpoints"), resp.ArrayValue(evals),
+			}))
+		}
+	}
+	if msg.OutputType == JSON {
+		return resp.StringValue(`{"ok":true,"hooks":[` + buf.String() +
+			`],"elapsed":"` + time.Since(start).String() + "\"}"), nil
+	}
+	return resp.ArrayValue(vals), nil
+}
+
+// HOOKDLQ name [REPLAY|PURGE]
+func (s *Server) cmdHookDLQ(msg *Message) (resp.Value, error) {
+	start := time.Now()
+	vs := msg.Args[1:]
+
+	var name, op string
+	var ok bool
+	if vs, name, ok = tokenval(vs); !ok || name == "" {
+		return NOMessage, errInvalidNumberOfArguments
+	}
+	if len(vs) > 0 {
+		if vs, op, ok = tokenval(vs); !ok || op == "" {
+			return NOMessage, errInvalidNumberOfArguments
+		}
+	}
+	if len(vs) != 0 {
+		return NOMessage, errInvalidNumberOfArguments
+	}
+	hook, _ := s.hooks.Get(&Hook{Name: name}).(*Hook)
+	if hook == nil || hook.channel || !msg.acl.ownsHook(hook) {
+		return NOMessage, errHookNotFound
+	}
+
+	var count int
+	var err error
+	switch strings.ToLower(op) {
+	default:
+		return NOMessage, errInvalidArgument(op)
+	case "":
+		// list the dead-lettered messages
+		var msgs []string
+		pivot := `{"hook":` + jsonString(name) + `}`
+		err = s.qdb.View(func(tx *buntdb.Tx) error {
+			return tx.AscendEqual("hookdlq", pivot,
+				func(key, val string) bool {
+					msgs = append(msgs, val)
+					return true
+				},
+			)
+		})
+		if err != nil {
+			return NOMessage, err
+		}
+		if msg.OutputType == JSON {
+			return resp.StringValue(`{"ok":true,"messages":[` +
+				strings.Join(msgs, ",") + `],"elapsed":"` +
+				time.Since(start).String() + "\"}"), nil
+		}
+		vals := make([]resp.Value, len(msgs))
+		for i, m := range msgs {
+			vals[i] = resp.StringValue(m)
+		}
+		return resp.ArrayValue(vals), nil
+	case "replay":
+		count, err = s.replayHookQueue(hook)
+	case "purge":
+		count, err = s.purgeHookQueue(name, false)
+	}
+	if err != nil {
+		return NOMessage, err
+	}
+	if msg.OutputType == JSON {
+		return resp.StringValue(`{"ok":true,"count":` + strconv.Itoa(count) +
+			`,"elapsed":"` + time.Since(start).String() + "\"}"), nil
+	}
+	return resp.IntegerValue(count), nil
+}
@@ -278,6 +278,8 @@ func (s *Server) cmdJset(msg *Message) (res resp.Value, d commandDetails, err er
 		geoobj = objIsSpatial(o.Geo())
 		json = o.Geo().String()
 		fields = o.Fields()
+	} else if err := s.checkObjectQuota(key, col.Count()); err != nil {
+		return NOMessage, d, err
 	}
 	if raw {
 		// set as raw block
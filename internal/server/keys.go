@@ -30,7 +30,7 @@ func (s *Server) cmdKEYS(msg *Message) (resp.Value, error) {
 		s.cols.Scan(
 			func(key string, _ *collection.Collection) bool {
 				match, _ := glob.Match(pattern, key)
-				if match {
+				if match && msg.acl.keyAllowed(key) {
 					keys = append(keys, key)
 				}
 				return true
@@ -43,7 +43,7 @@ func (s *Server) cmdKEYS(msg *Message) (resp.Value, error) {
 					return false
 				}
 				match, _ := glob.Match(pattern, key)
-				if match {
+				if match && msg.acl.keyAllowed(key) {
 					keys = append(keys, key)
 				}
 				return true
@@ -0,0 +1,87 @@
+package server
+
+import (
+	"strings"
+	"time"
+)
+
+// Keyspace notifications publish the changes of a collection to the
+// "__keyspace__:<key>" channel, enabled with the notify-keyspace-events
+// flags:
+//
+//	K  keyspace notifications, required for the other flags
+//	g  generic events: del, drop, rename_from, rename_to, expire, persist
+//	w  object writes: set, fset, jset, jdel
+//	x  expired events, when an object expires
+//	A  alias for "gwx"
+//
+// The message is a JSON object with the event, the key, and the id of the
+// object, when there's one. The events are published by the leader, and
+// forwarded to the subscribers of the followers.
+
+const keyspaceEventFlags = "KgwxA"
+
+// keyspaceEventClass returns the flag of an event.
+func keyspaceEventClass(event string) byte {
+	switch event {
+	case "set", "fset", "jset", "jdel":
+		return 'w'
+	case "expired":
+		return 'x'
+	}
+	return 'g'
+}
+
+// notifyKeyspace publishes the keyspace events of a command.
+func (s *Server) notifyKeyspace(args []string, d *commandDetails) {
+	flags := s.config.notifyKeyspaceEvents()
+	if !strings.Contains(flags, "K") || !s.loadedAndReady.Load() ||
+		s.config.followHost() != "" {
+		return
+	}
+	if d.parent {
+		for _, d := range d.children {
+			s.notifyKeyspace(args, d)
+		}
+		return
+	}
+	publish := func(event, key, id string) {
+		class := keyspaceEventClass(event)
+		if !strings.Contains(flags, "A") &&
+			strings.IndexByte(flags, class) == -1 {
+			return
+		}
+		msg := `{"event":` + jsonString(event) + `,"key":` + jsonString(key)
+		if id != "" {
+			msg += `,"id":` + jsonString(id)
+		}
+		ts := d.timestamp
+		if ts.IsZero() {
+			ts = time.Now()
+		}
+		msg += `,"time":` + jsonTimeFormat(ts) + `}`
+		s.Publish("__keyspace__:"+key, msg)
+	}
+	var id string
+	if d.obj != nil {
+		id = d.obj.ID()
+	}
+	switch d.command {
+	case "set", "fset", "del", "expire", "persist":
+		event := d.command
+		if d.expired {
+			event = "expired"
+		}
+		publish(event, d.key, id)
+	case "drop":
+		publish("drop", d.key, "")
+	case "rename":
+		publish("rename_from", d.key, "")
+		publish("rename_to", d.newKey, "")
+	case "":
+		// JSET and JDEL
+		if len(args) > 0 && d.obj != nil {
+			publish(strings.ToLower(args[0]), d.key, id)
+		}
+	}
+}
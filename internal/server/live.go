@@ -96,6 +96,9 @@ func (s *Server) goLive(
 	defer func() {
 		log.Info("not live " + addr)
 	}()
+	if msg.ConnType == HTTP && msg.EventStream {
+		return s.goLiveSSE(inerr, conn, msg)
+	}
 	switch lfs := inerr.(type) {
 	default:
 		return errors.New("invalid live type switches")
@@ -110,44 +113,11 @@ func (s *Server) goLive(
 	}
 
 	// everything below is for live geofences
-	lb := &liveBuffer{
-		cond: sync.NewCond(&sync.Mutex{}),
-	}
-	var err error
-	var sw *scanWriter
-	var wr bytes.Buffer
 	lfs := inerr.(liveFenceSwitches)
-	lb.globs = lfs.globs
-	lb.key = lfs.key
-	lb.fence = &lfs
-	s.mu.RLock()
-	sw, err = s.newScanWriter(
-		&wr, msg, lfs.key, lfs.output, lfs.precision, lfs.globs, false,
-		lfs.cursor, lfs.limit, lfs.wheres, lfs.whereins, lfs.whereevals,
-		lfs.nofields, lfs.mvt, lfs.tileX, lfs.tileY, lfs.tileZ)
-	s.mu.RUnlock()
-
-	// everything below if for live SCAN, NEARBY, WITHIN, INTERSECTS
-	if err != nil {
-		return err
-	}
-	s.lcond.L.Lock()
-	s.lives[lb] = true
-	s.lcond.L.Unlock()
-	defer func() {
-		s.lcond.L.Lock()
-		delete(s.lives, lb)
-		s.lcond.L.Unlock()
-		conn.Close()
-	}()
-
-	var mustQuit bool
+	done := make(chan struct{})
 	go func() {
 		defer func() {
-			lb.cond.L.Lock()
-			mustQuit = true
-			lb.cond.Broadcast()
-			lb.cond.L.Unlock()
+			close(done)
 			conn.Close()
 		}()
 		for {
@@ -172,21 +142,83 @@ func (s *Server) goLive(
 			}
 		}
 	}()
+	defer conn.Close()
 	outputType := msg.OutputType
 	connType := msg.ConnType
 	if websocket {
 		outputType = JSON
 	}
-	var livemsg []byte
-	switch outputType {
-	case JSON:
-		livemsg = redcon.AppendBulkString(nil, `{"ok":true,"live":true}`)
-	case RESP:
-		livemsg = redcon.AppendOK(nil)
+	ready := func() error {
+		var livemsg []byte
+		switch outputType {
+		case JSON:
+			livemsg = redcon.AppendBulkString(nil, `{"ok":true,"live":true}`)
+		case RESP:
+			livemsg = redcon.AppendOK(nil)
+		}
+		return writeLiveMessage(conn, livemsg, false, connType, websocket)
 	}
-	if err := writeLiveMessage(conn, livemsg, false, connType, websocket); err != nil {
+	err := s.watchLiveFence(lfs, msg, done, ready, func(msg string) error {
+		return writeLiveMessage(conn, []byte(msg), true, connType, websocket)
+	})
+	if err == errLiveFenceWrite {
 		return nil // nil return is fine here
 	}
+	return err
+}
+
+// errLiveFenceWrite is returned by watchLiveFence when the ready or send
+// callbacks fail.
+var errLiveFenceWrite = errors.New("live fence write failed")
+
+// watchLiveFence registers a live geofence and calls send for each fence
+// message until the done channel is closed or a callback fails. The ready
+// callback, when provided, is called once the fence is registered.
+func (s *Server) watchLiveFence(lfs liveFenceSwitches, msg *Message,
+	done <-chan struct{}, ready func() error, send func(msg string) error,
+) error {
+	lb := &liveBuffer{
+		cond: sync.NewCond(&sync.Mutex{}),
+	}
+	var err error
+	var sw *scanWriter
+	var wr bytes.Buffer
+	lb.globs = lfs.globs
+	lb.key = lfs.key
+	lb.fence = &lfs
+	s.mu.RLock()
+	sw, err = s.newScanWriter(
+		&wr, msg, lfs.key, lfs.output, lfs.precision, lfs.globs, false,
+		lfs.cursor, lfs.limit, lfs.wheres, lfs.whereins, lfs.whereevals,
+		lfs.nofields, lfs.mvt, lfs.tileX, lfs.tileY, lfs.tileZ)
+	s.mu.RUnlock()
+
+	// everything below if for live SCAN, NEARBY, WITHIN, INTERSECTS
+	if err != nil {
+		return err
+	}
+	s.lcond.L.Lock()
+	s.lives[lb] = true
+	s.lcond.L.Unlock()
+	defer func() {
+		s.lcond.L.Lock()
+		delete(s.lives, lb)
+		s.lcond.L.Unlock()
+	}()
+
+	var mustQuit bool
+	go func() {
+		<-done
+		lb.cond.L.Lock()
+		mustQuit = true
+		lb.cond.Broadcast()
+		lb.cond.L.Unlock()
+	}()
+	if ready != nil {
+		if err := ready(); err != nil {
+			return errLiveFenceWrite
+		}
+	}
 	for {
 		lb.cond.L.Lock()
 		if mustQuit {
@@ -209,8 +241,8 @@ func (s *Server) goLive(
 				msgs = FenceMatch("", sw, fence, nil, details)
 			}()
 			for _, msg := range msgs {
-				if err := writeLiveMessage(conn, []byte(msg), true, connType, websocket); err != nil {
-					return nil // nil return is fine here
+				if err := send(msg); err != nil {
+					return errLiveFenceWrite
 				}
 			}
 			s.statsTotalMsgsSent.Add(int64(len(msgs)))
@@ -80,8 +80,12 @@ func (s *Server) sendMonitor(err error, msg *Message, c *Client, lua bool) {
 		return
 	}
 
+	args := msg.Args
+	if msg.Command() == "sethook" {
+		args = redactHookArgs(args)
+	}
 	var line []byte
-	for i, arg := range msg.Args {
+	for i, arg := range args {
 		if i > 0 {
 			line = append(line, ' ')
 		}
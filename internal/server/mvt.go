@@ -1,21 +1,67 @@
 package server
 
 import (
+	"math"
 	"net/url"
 	"strings"
 
+	"github.com/aiqia-dev/meridian/internal/field"
 	"github.com/tidwall/geojson"
 	"github.com/tidwall/geojson/geometry"
 	"github.com/tidwall/mvt"
 )
 
 type mvtObj struct {
-	id  string
-	obj geojson.Object
+	id      string
+	obj     geojson.Object
+	fields  field.List
+	cluster *mvtCluster
+}
+
+// mvtCluster is a point standing for a cluster of objects
+type mvtCluster struct {
+	count         uint64
+	expansionZoom uint64
+}
+
+// mvtOpts are the rendering options of a layer
+type mvtOpts struct {
+	tolerance float64  // simplification tolerance, in pixels
+	fields    []string // fields added as feature tags
+}
+
+// mvtTolerance returns the simplification tolerance, in pixels, for a zoom.
+// Lower zooms drop more detail, and from zoom 16 all points are kept.
+func mvtTolerance(z int) float64 {
+	if z >= 16 {
+		return 0
+	}
+	return float64(16-z) / 16
+}
+
+// mvtSimplify converts a series of points to tile pixels, dropping the
+// points that are closer than the tolerance to the previously kept point.
+// The first and last points are always kept.
+func mvtSimplify(npoints int, pointAt func(i int) geometry.Point,
+	tileX, tileY, tileZ int, tol float64,
+) [][2]float64 {
+	pts := make([][2]float64, 0, npoints)
+	for i := 0; i < npoints; i++ {
+		p := pointAt(i)
+		x, y := mvt.LatLonXY(p.Y, p.X, tileX, tileY, tileZ)
+		if tol > 0 && i > 0 && i < npoints-1 {
+			last := pts[len(pts)-1]
+			if math.Hypot(x-last[0], y-last[1]) < tol {
+				continue
+			}
+		}
+		pts = append(pts, [2]float64{x, y})
+	}
+	return pts
 }
 
 func mvtDrawRing(f *mvt.Feature, tileX, tileY, tileZ int, ring geometry.Series,
-	hole bool,
+	hole bool, tol float64,
 ) {
 	npoints := ring.NumPoints()
 	if npoints < 3 {
@@ -23,25 +69,52 @@ func mvtDrawRing(f *mvt.Feature, tileX, tileY, tileZ int, ring geometry.Series,
 	}
 	cw := ring.Clockwise()
 	reverse := (cw && hole) || (!cw && !hole)
+	pointAt := ring.PointAt
 	if reverse {
-		p := ring.PointAt(npoints - 1)
-		f.MoveTo(mvt.LatLonXY(p.Y, p.X, tileX, tileY, tileZ))
-		for i := npoints - 2; i >= 0; i-- {
-			p := ring.PointAt(i)
-			f.LineTo(mvt.LatLonXY(p.Y, p.X, tileX, tileY, tileZ))
-		}
-	} else {
-		p := ring.PointAt(0)
-		f.MoveTo(mvt.LatLonXY(p.Y, p.X, tileX, tileY, tileZ))
-		for i := 1; i < npoints; i++ {
-			p := ring.PointAt(i)
-			f.LineTo(mvt.LatLonXY(p.Y, p.X, tileX, tileY, tileZ))
+		pointAt = func(i int) geometry.Point {
+			return ring.PointAt(npoints - 1 - i)
 		}
 	}
+	pts := mvtSimplify(npoints, pointAt, tileX, tileY, tileZ, tol)
+	if len(pts) < 3 {
+		return
+	}
+	f.MoveTo(pts[0][0], pts[0][1])
+	for _, p := range pts[1:] {
+		f.LineTo(p[0], p[1])
+	}
 	f.ClosePath()
 }
 
-func mvtAddFeature(l *mvt.Layer, tileX, tileY, tileZ int, o mvtObj) {
+// mvtAddTags adds the selected fields of an object as feature tags.
+func mvtAddTags(f *mvt.Feature, o mvtObj, names []string) {
+	for _, name := range names {
+		fld := o.fields.Get(name)
+		if fld.Name() == "" {
+			continue
+		}
+		v := fld.Value()
+		switch v.Kind() {
+		case field.Number:
+			f.AddTag(name, v.Num())
+		case field.True:
+			f.AddTag(name, true)
+		case field.False:
+			f.AddTag(name, false)
+		case field.Null:
+		default:
+			f.AddTag(name, v.Data())
+		}
+	}
+}
+
+func mvtAddFeature(l *mvt.Layer, tileX, tileY, tileZ int, o mvtObj,
+	opts *mvtOpts,
+) {
+	var tol float64
+	if opts != nil {
+		tol = opts.tolerance
+	}
 	var f *mvt.Feature
 	switch g := o.obj.(type) {
 	case *geojson.Point:
@@ -61,37 +134,47 @@ func mvtAddFeature(l *mvt.Layer, tileX, tileY, tileZ int, o mvtObj) {
 		if npoints < 2 {
 			return
 		}
-		p := line.PointAt(0)
-		f.MoveTo(mvt.LatLonXY(p.Y, p.X, tileX, tileY, tileZ))
-		for i := 1; i < npoints; i++ {
-			p := line.PointAt(i)
-			f.LineTo(mvt.LatLonXY(p.Y, p.X, tileX, tileY, tileZ))
+		pts := mvtSimplify(npoints, line.PointAt, tileX, tileY, tileZ, tol)
+		f.MoveTo(pts[0][0], pts[0][1])
+		for _, p := range pts[1:] {
+			f.LineTo(p[0], p[1])
 		}
 		f.AddTag("type", "linestring")
 	case *geojson.Rect:
 		f = l.AddFeature(mvt.Polygon)
-		mvtDrawRing(f, tileX, tileY, tileZ, g.Base(), false)
+		mvtDrawRing(f, tileX, tileY, tileZ, g.Base(), false, tol)
 		f.AddTag("type", "polygon")
 	case *geojson.Polygon:
 		f = l.AddFeature(mvt.Polygon)
 		poly := g.Base()
-		mvtDrawRing(f, tileX, tileY, tileZ, poly.Exterior, false)
+		mvtDrawRing(f, tileX, tileY, tileZ, poly.Exterior, false, tol)
 		for _, hole := range poly.Holes {
-			mvtDrawRing(f, tileX, tileY, tileZ, hole, true)
+			mvtDrawRing(f, tileX, tileY, tileZ, hole, true, tol)
 		}
 		f.AddTag("type", "polygon")
 	case *geojson.Feature:
-		mvtAddFeature(l, tileX, tileY, tileZ, mvtObj{o.id, g.Base()})
+		mvtAddFeature(l, tileX, tileY, tileZ, mvtObj{o.id, g.Base(), o.fields, nil},
+			opts)
 		return
 	default:
 		if g, ok := g.(geojson.Collection); ok {
 			for _, g := range g.Children() {
-				mvtAddFeature(l, tileX, tileY, tileZ, mvtObj{o.id, g})
+				mvtAddFeature(l, tileX, tileY, tileZ,
+					mvtObj{o.id, g, o.fields, nil}, opts)
 			}
 		}
 		return
 	}
+	if o.cluster != nil {
+		f.AddTag("cluster", true)
+		f.AddTag("point_count", o.cluster.count)
+		f.AddTag("expansion_zoom", o.cluster.expansionZoom)
+		return
+	}
 	f.AddTag("id", o.id)
+	if opts != nil {
+		mvtAddTags(f, o, opts.fields)
+	}
 }
 
 func mvtRender(tileX, tileY, tileZ int, objs []mvtObj) []byte {
@@ -99,7 +182,27 @@ func mvtRender(tileX, tileY, tileZ int, objs []mvtObj) []byte {
 	l := tile.AddLayer("meridian")
 	l.SetExtent(4096)
 	for _, obj := range objs {
-		mvtAddFeature(l, tileX, tileY, tileZ, obj)
+		mvtAddFeature(l, tileX, tileY, tileZ, obj, nil)
+	}
+	return tile.Render()
+}
+
+// mvtLayer is a named layer of a multi-layer tile
+type mvtLayer struct {
+	name string
+	objs []mvtObj
+	opts mvtOpts
+}
+
+// mvtRenderLayers renders a tile with one layer per collection.
+func mvtRenderLayers(tileX, tileY, tileZ int, layers []mvtLayer) []byte {
+	var tile mvt.Tile
+	for _, layer := range layers {
+		l := tile.AddLayer(layer.name)
+		l.SetExtent(4096)
+		for _, obj := range layer.objs {
+			mvtAddFeature(l, tileX, tileY, tileZ, obj, &layer.opts)
+		}
 	}
 	return tile.Render()
 }
@@ -120,10 +223,12 @@ func mvtFilterHTTPArgs(msg *Message, query string) (modified bool) {
 	}
 	var limit string
 	var sparse string
+	var radius string
 	if query != "" {
 		q, _ := url.ParseQuery(query)
 		sparse = q.Get("sparse")
 		limit = q.Get("limit")
+		radius = q.Get("cluster")
 	}
 	msg._command = ""
 	msg.Args = []string{"INTERSECTS", parts[0]}
@@ -134,6 +239,9 @@ func mvtFilterHTTPArgs(msg *Message, query string) (modified bool) {
 	} else {
 		msg.Args = append(msg.Args, "LIMIT", "100000000")
 	}
+	if radius != "" {
+		msg.Args = append(msg.Args, "CLUSTER", radius, parts[1])
+	}
 	msg.Args = append(msg.Args, "MVT", parts[2], parts[3], parts[1])
 	return true
 }
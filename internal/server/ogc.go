@@ -0,0 +1,442 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aiqia-dev/meridian/internal/admin"
+	"github.com/aiqia-dev/meridian/internal/collection"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/tidwall/gjson"
+)
+
+// OGC API - Features. The collections are the keys of the database and the
+// features are their objects.
+//
+//	/conformance
+//	/collections
+//	/collections/{key}
+//	/collections/{key}/items?bbox=&limit=&offset=&filter=
+//	/collections/{key}/items/{id}
+
+const (
+	ogcDefaultLimit = 10
+	ogcMaxLimit     = 10000
+	ogcCRS84        = "http://www.opengis.net/def/crs/OGC/1.3/CRS84"
+)
+
+var ogcConformance = []string{
+	"http://www.opengis.net/spec/ogcapi-features-1/1.0/conf/core",
+	"http://www.opengis.net/spec/ogcapi-features-1/1.0/conf/geojson",
+}
+
+type ogcLink struct {
+	Href  string `json:"href"`
+	Rel   string `json:"rel"`
+	Type  string `json:"type,omitempty"`
+	Title string `json:"title,omitempty"`
+}
+
+type ogcExtent struct {
+	Spatial struct {
+		BBox [][4]float64 `json:"bbox"`
+		CRS  string       `json:"crs"`
+	} `json:"spatial"`
+}
+
+type ogcCollection struct {
+	ID       string     `json:"id"`
+	Title    string     `json:"title"`
+	ItemType string     `json:"itemType"`
+	CRS      []string   `json:"crs"`
+	Extent   *ogcExtent `json:"extent,omitempty"`
+	Links    []ogcLink  `json:"links"`
+}
+
+type ogcFeature struct {
+	Type       string                     `json:"type"`
+	ID         string                     `json:"id"`
+	Geometry   json.RawMessage            `json:"geometry"`
+	Properties map[string]json.RawMessage `json:"properties"`
+	Links      []ogcLink                  `json:"links,omitempty"`
+}
+
+type ogcFeatureCollection struct {
+	Type           string       `json:"type"`
+	Features       []ogcFeature `json:"features"`
+	NumberReturned int          `json:"numberReturned"`
+	TimeStamp      string       `json:"timeStamp"`
+	Links          []ogcLink    `json:"links"`
+}
+
+// ogcError is an exception response
+type ogcError struct {
+	status      string
+	Code        string `json:"code"`
+	Description string `json:"description"`
+}
+
+func ogcErrorf(status, code, format string, args ...any) *ogcError {
+	return &ogcError{status: status, Code: code,
+		Description: fmt.Sprintf(format, args...)}
+}
+
+// isOGCPath returns true when the http path is part of the OGC API.
+func isOGCPath(path string) bool {
+	if i := strings.IndexByte(path, '?'); i != -1 {
+		path = path[:i]
+	}
+	return path == "conformance" || path == "collections" ||
+		strings.HasPrefix(path, "collections/")
+}
+
+// adminConfig returns the configuration of the admin panel.
+func (s *Server) adminConfig() admin.Config {
+	config := admin.Config{
+		Username: s.opts.AdminUser,
+		Password: s.opts.AdminPassword,
+	}
+	if s.opts.AdminJWTSecret != "" {
+		config.JWTSecret, _ = admin.SecretFromString(s.opts.AdminJWTSecret)
+	} else {
+		config.JWTSecret, _ = admin.GenerateRandomSecret()
+	}
+	return config
+}
+
+// httpAuthorized checks the Authorization header, which may hold the
+// credentials of an ACL user, the requirepass password, optionally as a
+// bearer token, or an admin panel token. It returns the ACL user, which is
+// nil for the default user.
+func (s *Server) httpAuthorized(msg *Message) (*aclUser, bool) {
+	if username, password, ok := aclCredentials(msg); ok {
+		var client Client
+		if err := s.aclAuth(&client, username, password); err != nil {
+			return nil, false
+		}
+		if client.user == "" {
+			return nil, true
+		}
+		u := s.acl.user(client.user)
+		return u, u != nil
+	}
+	pass := s.config.requirePass()
+	if pass == "" && s.opts.AdminUser == "" {
+		return nil, true
+	}
+	if pass != "" && msg.Auth != "" &&
+		(msg.Auth == pass || strings.TrimPrefix(msg.Auth, "Bearer ") == pass) {
+		return nil, true
+	}
+	if s.opts.AdminUser != "" {
+		if _, err := admin.ValidateCommandAuth(msg.Auth, s.adminConfig()); err == nil {
+			return nil, true
+		}
+	}
+	return nil, false
+}
+
+// handleOGC serves an OGC API request.
+func (s *Server) handleOGC(client *Client, msg *Message, query string) error {
+	start := time.Now()
+	res, contentType, err := func() (any, string, *ogcError) {
+		acl, ok := s.httpAuthorized(msg)
+		if !ok {
+			return nil, "", ogcErrorf("401 Unauthorized", "Unauthorized",
+				"authentication required")
+		}
+		q, qerr := url.ParseQuery(query)
+		if qerr != nil {
+			return nil, "", ogcErrorf("400 Bad Request",
+				"InvalidParameterValue", "invalid query")
+		}
+		parts := strings.Split(msg.Args[0], "/")
+		for i := range parts {
+			var perr error
+			if parts[i], perr = url.PathUnescape(parts[i]); perr != nil {
+				return nil, "", ogcErrorf("400 Bad Request",
+					"InvalidParameterValue", "invalid path")
+			}
+		}
+		if acl != nil && parts[0] == "collections" {
+			// the collections are listed with KEYS and read with SCAN
+			args := []string{"keys", "*"}
+			if len(parts) > 1 {
+				args = []string{"scan", parts[1]}
+			}
+			if err := acl.authorize(&Message{Args: args}); err != nil {
+				return nil, "", ogcErrorf("403 Forbidden", "Forbidden",
+					"%s", err)
+			}
+		}
+		base := "/"
+		if msg.Host != "" {
+			base = "http://" + msg.Host + "/"
+		}
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+		if s.config.followHost() != "" && !s.caughtUpOnce() {
+			return nil, "", ogcErrorf("503 Service Unavailable",
+				"ServiceUnavailable", "catching up to leader")
+		}
+		switch {
+		case len(parts) == 1 && parts[0] == "conformance":
+			return map[string][]string{"conformsTo": ogcConformance},
+				"application/json", nil
+		case len(parts) == 1:
+			return s.ogcCollections(acl, base), "application/json", nil
+		case len(parts) == 2:
+			col, err := s.ogcCollection(base, parts[1])
+			return col, "application/json", err
+		case len(parts) == 3 && parts[2] == "items":
+			fc, err := s.ogcItems(msg, base, parts[1], q)
+			return fc, "application/geo+json", err
+		case len(parts) == 4 && parts[2] == "items":
+			f, err := s.ogcItem(msg, base, parts[1], parts[3])
+			return f, "application/geo+json", err
+		}
+		return nil, "", ogcErrorf("404 Not Found", "NotFound",
+			"resource not found")
+	}()
+	status := "200 OK"
+	if err != nil {
+		status, res, contentType = err.status, err, "application/json"
+	}
+	body, _ := json.Marshal(res)
+	_, werr := fmt.Fprintf(client, ""+
+		"HTTP/1.1 %s\r\n"+
+		"Connection: close\r\n"+
+		"Content-Type: %s\r\n"+
+		"Content-Length: %d\r\n"+
+		"Access-Control-Allow-Origin: *\r\n"+
+		"\r\n", status, contentType, len(body))
+	if werr != nil {
+		return werr
+	}
+	_, werr = client.Write(body)
+	cmdDurations.With(prometheus.Labels{"cmd": "ogc"}).Observe(
+		time.Since(start).Seconds())
+	return werr
+}
+
+func ogcCollectionInfo(base, key string, col *collection.Collection,
+) ogcCollection {
+	href := base + "collections/" + url.PathEscape(key)
+	info := ogcCollection{
+		ID:       key,
+		Title:    key,
+		ItemType: "feature",
+		CRS:      []string{ogcCRS84},
+		Links: []ogcLink{
+			{Href: href, Rel: "self", Type: "application/json"},
+			{Href: href + "/items", Rel: "items", Type: "application/geo+json"},
+		},
+	}
+	if col.Count() > 0 {
+		minX, minY, maxX, maxY := col.Bounds()
+		info.Extent = new(ogcExtent)
+		info.Extent.Spatial.BBox = [][4]float64{{minX, minY, maxX, maxY}}
+		info.Extent.Spatial.CRS = ogcCRS84
+	}
+	return info
+}
+
+// ogcCollections returns the collections that an ACL user can read.
+func (s *Server) ogcCollections(acl *aclUser, base string) any {
+	cols := []ogcCollection{}
+	s.cols.Scan(func(key string, col *collection.Collection) bool {
+		if acl.keyAllowed(key) {
+			cols = append(cols, ogcCollectionInfo(base, key, col))
+		}
+		return true
+	})
+	return map[string]any{
+		"collections": cols,
+		"links": []ogcLink{{Href: base + "collections", Rel: "self",
+			Type: "application/json"}},
+	}
+}
+
+func (s *Server) ogcCollection(base, key string) (any, *ogcError) {
+	col, ok := s.cols.Get(key)
+	if !ok {
+		return nil, ogcErrorf("404 Not Found", "NotFound",
+			"collection not found")
+	}
+	return ogcCollectionInfo(base, key, col), nil
+}
+
+// ogcItems runs a SCAN, or an INTERSECTS when a bbox is given, and returns
+// the objects as a feature collection.
+func (s *Server) ogcItems(msg *Message, base, key string, q url.Values,
+) (any, *ogcError) {
+	if _, ok := s.cols.Get(key); !ok {
+		return nil, ogcErrorf("404 Not Found", "NotFound",
+			"collection not found")
+	}
+	limit, offset := ogcDefaultLimit, 0
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return nil, ogcErrorf("400 Bad Request", "InvalidParameterValue",
+				"invalid limit '%s'", v)
+		}
+		limit = min(n, ogcMaxLimit)
+	}
+	if v := q.Get("offset"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return nil, ogcErrorf("400 Bad Request", "InvalidParameterValue",
+				"invalid offset '%s'", v)
+		}
+		offset = n
+	}
+	var bbox []string
+	if v := q.Get("bbox"); v != "" {
+		// minLon,minLat,maxLon,maxLat
+		bbox = strings.Split(v, ",")
+		if len(bbox) != 4 {
+			return nil, ogcErrorf("400 Bad Request", "InvalidParameterValue",
+				"invalid bbox '%s'", v)
+		}
+		for _, c := range bbox {
+			if _, err := strconv.ParseFloat(c, 64); err != nil {
+				return nil, ogcErrorf("400 Bad Request",
+					"InvalidParameterValue", "invalid bbox '%s'", v)
+			}
+		}
+	}
+	cmd := "SCAN"
+	if bbox != nil {
+		cmd = "INTERSECTS"
+	}
+	args := []string{cmd, key, "CURSOR", strconv.Itoa(offset),
+		"LIMIT", strconv.Itoa(limit)}
+	if filter := q.Get("filter"); filter != "" {
+		args = append(args, "WHERE", filter)
+	}
+	if bbox != nil {
+		args = append(args, "BOUNDS", bbox[1], bbox[0], bbox[3], bbox[2])
+	}
+	smsg := &Message{Args: args, ConnType: msg.ConnType, OutputType: JSON}
+	var out string
+	var err error
+	if bbox != nil {
+		v, cerr := s.cmdINTERSECTS(smsg)
+		out, err = v.String(), cerr
+	} else {
+		v, cerr := s.cmdScan(smsg)
+		out, err = v.String(), cerr
+	}
+	if err == nil && !gjson.Get(out, "ok").Bool() {
+		err = fmt.Errorf("%s", gjson.Get(out, "err").String())
+	}
+	if err != nil {
+		return nil, ogcErrorf("400 Bad Request", "InvalidParameterValue",
+			"%s", err.Error())
+	}
+	res := gjson.Parse(out)
+	fc := ogcFeatureCollection{
+		Type:      "FeatureCollection",
+		Features:  []ogcFeature{},
+		TimeStamp: time.Now().UTC().Format(time.RFC3339),
+	}
+	names := res.Get("fields")
+	res.Get("objects").ForEach(func(_, obj gjson.Result) bool {
+		fc.Features = append(fc.Features, ogcNewFeature(names, obj))
+		return true
+	})
+	fc.NumberReturned = len(fc.Features)
+
+	href := base + "collections/" + url.PathEscape(key) + "/items"
+	link := func(offset int) string {
+		lq := url.Values{}
+		for k, v := range q {
+			lq[k] = v
+		}
+		lq.Set("limit", strconv.Itoa(limit))
+		lq.Set("offset", strconv.Itoa(offset))
+		return href + "?" + lq.Encode()
+	}
+	fc.Links = []ogcLink{
+		{Href: link(offset), Rel: "self", Type: "application/geo+json"},
+	}
+	if cursor := res.Get("cursor").Int(); cursor > 0 {
+		fc.Links = append(fc.Links, ogcLink{Href: link(int(cursor)),
+			Rel: "next", Type: "application/geo+json"})
+	}
+	if offset > 0 {
+		fc.Links = append(fc.Links, ogcLink{Href: link(max(offset-limit, 0)),
+			Rel: "prev", Type: "application/geo+json"})
+	}
+	fc.Links = append(fc.Links, ogcLink{
+		Href: base + "collections/" + url.PathEscape(key),
+		Rel:  "collection", Type: "application/json",
+	})
+	return fc, nil
+}
+
+func (s *Server) ogcItem(msg *Message, base, key, id string,
+) (any, *ogcError) {
+	smsg := &Message{Args: []string{"GET", key, id, "WITHFIELDS"},
+		ConnType: msg.ConnType, OutputType: JSON}
+	v, err := s.cmdGET(smsg)
+	out := v.String()
+	if err == nil && !gjson.Get(out, "ok").Bool() {
+		err = fmt.Errorf("%s", gjson.Get(out, "err").String())
+	}
+	if err != nil {
+		if err.Error() == errKeyNotFound.Error() ||
+			err.Error() == errIDNotFound.Error() {
+			return nil, ogcErrorf("404 Not Found", "NotFound",
+				"feature not found")
+		}
+		return nil, ogcErrorf("400 Bad Request", "InvalidParameterValue",
+			"%s", err.Error())
+	}
+	f := ogcNewFeature(gjson.Result{}, gjson.Parse(out))
+	f.ID = id
+	href := base + "collections/" + url.PathEscape(key)
+	f.Links = []ogcLink{
+		{Href: href + "/items/" + url.PathEscape(id), Rel: "self",
+			Type: "application/geo+json"},
+		{Href: href, Rel: "collection", Type: "application/json"},
+	}
+	return f, nil
+}
+
+// ogcNewFeature converts an object of a json result into a feature. A
+// GeoJSON Feature keeps its properties, and the fields are added to them.
+// Objects that are not GeoJSON, such as strings, have a null geometry.
+func ogcNewFeature(names, obj gjson.Result) ogcFeature {
+	f := ogcFeature{
+		Type:       "Feature",
+		ID:         obj.Get("id").String(),
+		Geometry:   json.RawMessage("null"),
+		Properties: map[string]json.RawMessage{},
+	}
+	geom := obj.Get("object")
+	if geom.IsObject() {
+		if geom.Get("type").String() == "Feature" {
+			geom.Get("properties").ForEach(func(key, val gjson.Result) bool {
+				f.Properties[key.String()] = json.RawMessage(val.Raw)
+				return true
+			})
+			geom = geom.Get("geometry")
+		}
+		if geom.IsObject() {
+			f.Geometry = json.RawMessage(geom.Raw)
+		}
+	} else if geom.Exists() {
+		f.Properties["value"] = json.RawMessage(geom.Raw)
+	}
+	forEachField(names, obj.Get("fields"), func(name string, val gjson.Result) bool {
+		f.Properties[name] = json.RawMessage(val.Raw)
+		return true
+	})
+	return f
+}
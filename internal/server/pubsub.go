@@ -252,6 +252,15 @@ func (s *Server) liveSubscription(
 				"PING / QUIT allowed in this context\r\n"))
 		}
 	}
+	writeNoPermErr := func(err error) {
+		switch outputType {
+		case JSON:
+			write([]byte(`{"ok":false,"err":` + jsonString(err.Error()) +
+				`,"elapsed":"` + time.Since(start).String() + `"}`))
+		case RESP:
+			write([]byte("-" + err.Error() + "\r\n"))
+		}
+	}
 	writeSubscribe := func(command, channel string, num int) {
 		switch outputType {
 		case JSON:
@@ -348,6 +357,9 @@ func (s *Server) liveSubscription(
 		}
 	}()
 
+	// the commands in this context are checked with the user of the first
+	// command
+	acl := msg.acl
 	msgs := []*Message{msg}
 	for {
 		for _, msg := range msgs {
@@ -376,6 +388,12 @@ func (s *Server) liveSubscription(
 			if len(msg.Args) < 2 {
 				writeWrongNumberOfArgsErr(msg.Command())
 			}
+			if acl != nil {
+				if err := acl.authorize(msg); err != nil {
+					writeNoPermErr(err)
+					continue
+				}
+			}
 			for i := 1; i < len(msg.Args); i++ {
 				channel := msg.Args[i]
 				if un {
@@ -0,0 +1,315 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tidwall/resp"
+)
+
+// Rate limits, as token buckets, and quotas.
+//
+//	RATELIMIT SET ip|user target category rate [burst]
+//	RATELIMIT DEL ip|user target category
+//	RATELIMIT LIST
+//
+// The target is a client IP or an authenticated user, or "*" for each of
+// them. The category is an ACL category, or "all". The rate is the number
+// of commands per second, and the burst is the size of the bucket, which is
+// the rate by default. A rule for a target takes precedence over the "*"
+// rule of the same kind and category.
+//
+// The rules are persisted in the config file as their RATELIMIT LIST lines.
+
+// maxRateBuckets is the number of buckets that starts a sweep of the idle
+// buckets.
+const maxRateBuckets = 10000
+
+type rateRule struct {
+	kind     string // "ip" or "user"
+	target   string // ip, user, or "*"
+	category string // ACL category, or "all"
+	rate     float64
+	burst    float64
+}
+
+type rateBucket struct {
+	rule   *rateRule
+	tokens float64
+	last   time.Time
+}
+
+type rateLimiter struct {
+	mu      sync.Mutex
+	rules   map[string]*rateRule   // by kind, target and category
+	buckets map[string]*rateBucket // by rule and client ip or user
+}
+
+func rateRuleKey(kind, target, category string) string {
+	return kind + " " + target + " " + category
+}
+
+// String returns the rule as a RATELIMIT LIST line.
+func (r *rateRule) String() string {
+	return rateRuleKey(r.kind, r.target, r.category) + " " +
+		strconv.FormatFloat(r.rate, 'f', -1, 64) + " " +
+		strconv.FormatFloat(r.burst, 'f', -1, 64)
+}
+
+// parseRateRule parses the arguments of RATELIMIT SET.
+func parseRateRule(args []string) (*rateRule, error) {
+	if len(args) != 4 && len(args) != 5 {
+		return nil, errInvalidNumberOfArguments
+	}
+	r := &rateRule{
+		kind:     strings.ToLower(args[0]),
+		target:   args[1],
+		category: strings.ToLower(args[2]),
+	}
+	if r.kind != "ip" && r.kind != "user" {
+		return nil, errInvalidArgument(args[0])
+	}
+	if r.target == "" || strings.ContainsAny(r.target, " \t\r\n") {
+		return nil, errInvalidArgument(args[1])
+	}
+	if r.category != "all" {
+		var ok bool
+		for _, category := range aclCategories {
+			ok = ok || category == r.category
+		}
+		if !ok {
+			return nil, errInvalidArgument(args[2])
+		}
+	}
+	rate, err := strconv.ParseFloat(args[3], 64)
+	if err != nil || rate <= 0 || math.IsInf(rate, 0) {
+		return nil, errInvalidArgument(args[3])
+	}
+	r.rate, r.burst = rate, math.Max(1, math.Ceil(rate))
+	if len(args) == 5 {
+		burst, err := strconv.ParseUint(args[4], 10, 32)
+		if err != nil || burst == 0 {
+			return nil, errInvalidArgument(args[4])
+		}
+		r.burst = float64(burst)
+	}
+	return r, nil
+}
+
+// load loads the rules from their RATELIMIT LIST lines.
+func (rl *rateLimiter) load(lines []string) error {
+	rules := make(map[string]*rateRule)
+	for _, line := range lines {
+		r, err := parseRateRule(strings.Fields(line))
+		if err != nil {
+			return fmt.Errorf("invalid rate limit '%s': %w", line, err)
+		}
+		rules[rateRuleKey(r.kind, r.target, r.category)] = r
+	}
+	rl.mu.Lock()
+	rl.rules = rules
+	rl.buckets = nil
+	rl.mu.Unlock()
+	return nil
+}
+
+// lines returns the RATELIMIT LIST lines of the rules, sorted.
+func (rl *rateLimiter) lines() []string {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	lines := []string{}
+	for _, r := range rl.rules {
+		lines = append(lines, r.String())
+	}
+	sort.Strings(lines)
+	return lines
+}
+
+// take takes a token from the bucket of each rule that applies to a command.
+// When one of the buckets is empty, it returns the rule and how long to wait
+// for a token.
+func (rl *rateLimiter) take(ip, user, category string,
+	now time.Time,
+) (limited *rateRule, wait time.Duration) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	if len(rl.rules) == 0 {
+		return nil, 0
+	}
+	if len(rl.buckets) > maxRateBuckets {
+		rl.sweep(now)
+	}
+	for _, id := range [][2]string{{"ip", ip}, {"user", user}} {
+		for _, cat := range []string{category, "all"} {
+			r := rl.rules[rateRuleKey(id[0], id[1], cat)]
+			if r == nil {
+				r = rl.rules[rateRuleKey(id[0], "*", cat)]
+				if r == nil {
+					continue
+				}
+			}
+			key := rateRuleKey(r.kind, r.target, r.category) + " " + id[1]
+			b := rl.buckets[key]
+			if b == nil {
+				if rl.buckets == nil {
+					rl.buckets = make(map[string]*rateBucket)
+				}
+				b = &rateBucket{rule: r, tokens: r.burst, last: now}
+				rl.buckets[key] = b
+			}
+			b.tokens = math.Min(r.burst,
+				b.tokens+now.Sub(b.last).Seconds()*r.rate)
+			b.last = now
+			if b.tokens < 1 {
+				return r, time.Duration((1 - b.tokens) / r.rate *
+					float64(time.Second))
+			}
+			b.tokens--
+		}
+	}
+	return nil, 0
+}
+
+// sweep deletes the buckets that are full again.
+func (rl *rateLimiter) sweep(now time.Time) {
+	for key, b := range rl.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*b.rule.rate >= b.rule.burst {
+			delete(rl.buckets, key)
+		}
+	}
+}
+
+// rateLimit checks the rate limits of the client for a command.
+func (s *Server) rateLimit(client *Client, msg *Message) error {
+	category := aclCategory(msg)
+	if category == "" {
+		return nil
+	}
+	ip := client.remoteAddr
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+	user := client.user
+	if user == "" {
+		user = "default"
+	}
+	r, wait := s.limiter.take(ip, user, category, time.Now())
+	if r == nil {
+		return nil
+	}
+	return fmt.Errorf("RATELIMITED too many '%s' commands, retry in %s",
+		r.category, wait.Round(time.Millisecond))
+}
+
+// checkObjectQuota returns an error when a new object would exceed the max
+// number of objects of the collection. The quota isn't checked when the AOF
+// is loaded, or on followers.
+func (s *Server) checkObjectQuota(key string, count int) error {
+	max := s.config.collectionMaxObjects()
+	if max <= 0 || count < max || !s.loadedAndReady.Load() ||
+		s.config.followHost() != "" {
+		return nil
+	}
+	return fmt.Errorf("QUOTA collection '%s' reached the max of %d objects",
+		key, max)
+}
+
+// checkHookQuota returns an error when a new hook of a user would exceed the
+// max number of hooks per user. The hooks and channels count, except for the
+// one that is replaced. The hooks without an owner belong to the default
+// user.
+func (s *Server) checkHookQuota(owner, name string) error {
+	max := s.config.userMaxHooks()
+	if max <= 0 || !s.loadedAndReady.Load() || s.config.followHost() != "" {
+		return nil
+	}
+	var count int
+	s.hooks.Ascend(nil, func(v interface{}) bool {
+		hook := v.(*Hook)
+		if hook.owner == owner && hook.Name != name {
+			count++
+		}
+		return true
+	})
+	if count < max {
+		return nil
+	}
+	user := owner
+	if user == "" {
+		user = "default"
+	}
+	return fmt.Errorf("QUOTA user '%s' reached the max of %d hooks",
+		user, max)
+}
+
+// RATELIMIT SET|DEL|LIST
+func (s *Server) cmdRATELIMIT(msg *Message) (resp.Value, error) {
+	start := time.Now()
+	args := msg.Args
+	if len(args) < 2 {
+		return retrerr(errInvalidNumberOfArguments)
+	}
+	switch strings.ToLower(args[1]) {
+	case "set":
+		r, err := parseRateRule(args[2:])
+		if err != nil {
+			return retrerr(err)
+		}
+		s.limiter.mu.Lock()
+		if s.limiter.rules == nil {
+			s.limiter.rules = make(map[string]*rateRule)
+		}
+		s.limiter.rules[rateRuleKey(r.kind, r.target, r.category)] = r
+		s.limiter.buckets = nil
+		s.limiter.mu.Unlock()
+		s.config.setRateLimits(s.limiter.lines())
+		s.config.write(false)
+		return OKMessage(msg, start), nil
+	case "del":
+		if len(args) != 5 {
+			return retrerr(errInvalidNumberOfArguments)
+		}
+		key := rateRuleKey(strings.ToLower(args[2]), args[3],
+			strings.ToLower(args[4]))
+		var n int
+		s.limiter.mu.Lock()
+		if _, ok := s.limiter.rules[key]; ok {
+			delete(s.limiter.rules, key)
+			s.limiter.buckets = nil
+			n = 1
+		}
+		s.limiter.mu.Unlock()
+		if n > 0 {
+			s.config.setRateLimits(s.limiter.lines())
+			s.config.write(false)
+		}
+		if msg.OutputType == JSON {
+			return resp.StringValue(fmt.Sprintf(`{"ok":true,"deleted":%d,`+
+				`"elapsed":"%s"}`, n, time.Since(start))), nil
+		}
+		return resp.IntegerValue(n), nil
+	case "list":
+		if len(args) != 2 {
+			return retrerr(errInvalidNumberOfArguments)
+		}
+		lines := s.limiter.lines()
+		if msg.OutputType == JSON {
+			data, _ := json.Marshal(lines)
+			return resp.StringValue(`{"ok":true,"rules":` + string(data) +
+				`,"elapsed":"` + time.Since(start).String() + `"}`), nil
+		}
+		vals := make([]resp.Value, len(lines))
+		for i, line := range lines {
+			vals[i] = resp.StringValue(line)
+		}
+		return resp.ArrayValue(vals), nil
+	}
+	return retrerr(errInvalidArgument(args[1]))
+}
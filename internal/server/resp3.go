@@ -0,0 +1,491 @@
+package server
+
+import (
+	"errors"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/aiqia-dev/meridian/core"
+	"github.com/tidwall/redcon"
+	"github.com/tidwall/resp"
+)
+
+// RESP3 protocol, negotiated with HELLO 3.
+//
+// Commands build RESP2 replies, which are written to RESP3 clients with the
+// native types: maps for key/value replies, doubles for coordinates and
+// distances, and null for missing values. Live fences and subscriptions on a
+// RESP3 connection don't take over the connection. Their messages are push
+// frames, written between the replies of the other commands.
+
+// HELLO [protover [AUTH username password] [SETNAME clientname]]
+func (s *Server) cmdHELLO(msg *Message, client *Client) (resp.Value, error) {
+	args := msg.Args
+	proto := 2
+	if msg.RESP3 {
+		proto = 3
+	}
+	var auth bool
+	var username, password, name string
+	if len(args) > 1 {
+		n, err := strconv.Atoi(args[1])
+		if err != nil {
+			return retrerr(errors.New(
+				"Protocol version is not an integer or out of range"))
+		}
+		if n != 2 && (n != 3 || msg.ConnType != RESP) {
+			return retrerr(errors.New("NOPROTO unsupported protocol version"))
+		}
+		proto = n
+		for i := 2; i < len(args); i++ {
+			switch strings.ToLower(args[i]) {
+			case "auth":
+				if i+2 >= len(args) {
+					return retrerr(errInvalidNumberOfArguments)
+				}
+				auth = true
+				username, password = args[i+1], args[i+2]
+				i += 2
+			case "setname":
+				if i+1 >= len(args) {
+					return retrerr(errInvalidNumberOfArguments)
+				}
+				name = args[i+1]
+				for j := 0; j < len(name); j++ {
+					if name[j] < '!' || name[j] > '~' {
+						return retrerr(clientErrorf(
+							"Client names cannot contain spaces, newlines or special characters.",
+						))
+					}
+				}
+				i++
+			default:
+				return retrerr(errInvalidArgument(args[i]))
+			}
+		}
+	}
+	if auth {
+		if err := s.aclAuth(client, username, password); err != nil {
+			return retrerr(err)
+		}
+	} else if s.config.requirePass() != "" && !client.authd {
+		return retrerr(errors.New("authentication required"))
+	}
+	if name != "" {
+		client.mu.Lock()
+		client.name = name
+		client.mu.Unlock()
+	}
+	msg.RESP3 = proto == 3
+
+	role := "master"
+	if s.config.followHost() != "" {
+		role = "replica"
+	}
+	return resp.ArrayValue([]resp.Value{
+		resp.StringValue("server"), resp.StringValue("meridian"),
+		resp.StringValue("version"), resp.StringValue(core.Version),
+		resp.StringValue("proto"), resp.IntegerValue(proto),
+		resp.StringValue("id"), resp.IntegerValue(client.id),
+		resp.StringValue("mode"), resp.StringValue("standalone"),
+		resp.StringValue("role"), resp.StringValue(role),
+		resp.StringValue("modules"), resp.ArrayValue(nil),
+	}), nil
+}
+
+// appendRESP3Reply appends the reply of a command for a RESP3 client.
+func appendRESP3Reply(dst []byte, msg *Message, v resp.Value) []byte {
+	if v.IsNull() || v.Type() != resp.Array && v.Type() != resp.BulkString {
+		return appendRESP3(dst, v)
+	}
+	switch msg.Command() {
+	case "hello", "server":
+		return appendRESP3Map(dst, v.Array(), true)
+	case "stats":
+		vals := v.Array()
+		dst = redcon.AppendArray(dst, len(vals))
+		for _, v := range vals {
+			if v.IsNull() {
+				dst = appendRESP3(dst, v)
+			} else {
+				dst = appendRESP3Map(dst, v.Array(), true)
+			}
+		}
+		return dst
+	case "info":
+		var pairs []resp.Value
+		for _, kv := range strings.Split(v.String(), "\r\n") {
+			kv = strings.TrimSpace(kv)
+			if !strings.HasPrefix(kv, "#") {
+				if split := strings.SplitN(kv, ":", 2); len(split) == 2 {
+					pairs = append(pairs, resp.StringValue(split[0]),
+						resp.StringValue(split[1]))
+				}
+			}
+		}
+		return appendRESP3Map(dst, pairs, true)
+	case "get":
+		var withfields bool
+		for i := 3; i < len(msg.Args); i++ {
+			if strings.ToLower(msg.Args[i]) == "withfields" {
+				withfields = true
+			}
+		}
+		if !withfields {
+			return appendRESP3Doubles(dst, v)
+		}
+		vals := v.Array()
+		dst = redcon.AppendArray(dst, len(vals))
+		for i, v := range vals {
+			if i == 0 {
+				dst = appendRESP3Doubles(dst, v)
+			} else {
+				dst = appendRESP3Map(dst, v.Array(), false)
+			}
+		}
+		return dst
+	case "scan", "search", "nearby", "within", "intersects":
+		vals := v.Array()
+		if len(vals) != 2 || vals[1].Type() != resp.Array {
+			break
+		}
+		items := vals[1].Array()
+		dst = redcon.AppendArray(dst, 2)
+		dst = appendRESP3(dst, vals[0])
+		dst = redcon.AppendArray(dst, len(items))
+		for _, item := range items {
+			dst = appendRESP3SearchItem(dst, item, msg.output)
+		}
+		return dst
+	}
+	return appendRESP3(dst, v)
+}
+
+// appendRESP3SearchItem appends an item of a search result. The item is an
+// id, or an array that starts with the id and is followed by the geometry,
+// the fields and the distance.
+func appendRESP3SearchItem(dst []byte, item resp.Value, output outputT) []byte {
+	if item.Type() != resp.Array {
+		return appendRESP3(dst, item)
+	}
+	vals := item.Array()
+	dst = redcon.AppendArray(dst, len(vals))
+	for i, v := range vals {
+		switch {
+		case i == 0:
+			dst = appendRESP3(dst, v)
+		case v.Type() == resp.Array && i == 1:
+			dst = appendRESP3Doubles(dst, v)
+		case v.Type() == resp.Array:
+			dst = appendRESP3Map(dst, v.Array(), false)
+		case v.Type() == resp.BulkString && (i > 1 || output == outputIDs):
+			dst = appendRESP3Doubles(dst, v)
+		default:
+			dst = appendRESP3(dst, v)
+		}
+	}
+	return dst
+}
+
+// appendRESP3 appends a value as is, except for the null.
+func appendRESP3(dst []byte, v resp.Value) []byte {
+	if v.IsNull() {
+		return append(dst, "_\r\n"...)
+	}
+	if v.Type() == resp.Array {
+		vals := v.Array()
+		dst = redcon.AppendArray(dst, len(vals))
+		for _, v := range vals {
+			dst = appendRESP3(dst, v)
+		}
+		return dst
+	}
+	data, _ := v.MarshalRESP()
+	return append(dst, data...)
+}
+
+// appendRESP3Doubles appends a value with the numeric strings, such as the
+// coordinates of a point, as doubles.
+func appendRESP3Doubles(dst []byte, v resp.Value) []byte {
+	switch v.Type() {
+	case resp.Array:
+		if v.IsNull() {
+			break
+		}
+		vals := v.Array()
+		dst = redcon.AppendArray(dst, len(vals))
+		for _, v := range vals {
+			dst = appendRESP3Doubles(dst, v)
+		}
+		return dst
+	case resp.BulkString:
+		if !v.IsNull() && isJSONNumber(v.String()) {
+			f, err := strconv.ParseFloat(v.String(), 64)
+			if err == nil {
+				return appendRESP3Double(dst, f)
+			}
+		}
+	}
+	return appendRESP3(dst, v)
+}
+
+// appendRESP3Map appends name/value pairs as a map. With typed, the values
+// that are integers, floats or bools are appended with their type, otherwise
+// only the numbers are converted, to doubles.
+func appendRESP3Map(dst []byte, pairs []resp.Value, typed bool) []byte {
+	dst = append(dst, '%')
+	dst = strconv.AppendInt(dst, int64(len(pairs)/2), 10)
+	dst = append(dst, '\r', '\n')
+	for i := 0; i+1 < len(pairs); i += 2 {
+		dst = appendRESP3(dst, pairs[i])
+		v := pairs[i+1]
+		if !typed || v.Type() != resp.BulkString || v.IsNull() {
+			dst = appendRESP3Doubles(dst, v)
+			continue
+		}
+		switch tv := tryParseType(v.String()).(type) {
+		case int64:
+			dst = redcon.AppendInt(dst, tv)
+		case float64:
+			dst = appendRESP3Double(dst, tv)
+		case bool:
+			if tv {
+				dst = append(dst, "#t\r\n"...)
+			} else {
+				dst = append(dst, "#f\r\n"...)
+			}
+		default:
+			dst = appendRESP3(dst, v)
+		}
+	}
+	return dst
+}
+
+func appendRESP3Double(dst []byte, f float64) []byte {
+	dst = append(dst, ',')
+	switch {
+	case math.IsInf(f, 1):
+		dst = append(dst, "inf"...)
+	case math.IsInf(f, -1):
+		dst = append(dst, "-inf"...)
+	case math.IsNaN(f):
+		dst = append(dst, "nan"...)
+	default:
+		dst = strconv.AppendFloat(dst, f, 'f', -1, 64)
+	}
+	return append(dst, '\r', '\n')
+}
+
+// appendRESP3Push appends a push frame of strings, followed by an optional
+// count.
+func appendRESP3Push(dst []byte, count int, strs ...string) []byte {
+	n := len(strs)
+	if count >= 0 {
+		n++
+	}
+	dst = append(dst, '>')
+	dst = strconv.AppendInt(dst, int64(n), 10)
+	dst = append(dst, '\r', '\n')
+	for _, str := range strs {
+		dst = redcon.AppendBulkString(dst, str)
+	}
+	if count >= 0 {
+		dst = redcon.AppendInt(dst, int64(count))
+	}
+	return dst
+}
+
+// pushConn writes the push frames of the live fences and subscriptions of a
+// RESP3 connection. The frames are held while the connection has replies
+// that are not written yet, so that a subscription is confirmed before its
+// messages.
+type pushConn struct {
+	mu     sync.Mutex // guards writes to the connection
+	conn   net.Conn
+	target *subtarget // its cond also guards the fields below
+	frames []byte     // pending frames of live fences
+	held   bool
+	done   chan struct{} // closed with the connection
+	subs   [2]map[string]bool
+}
+
+// pushConn returns the push connection of a client, starting it on first
+// use.
+func (s *Server) pushConn(client *Client, conn net.Conn) *pushConn {
+	if client.push != nil {
+		return client.push
+	}
+	pc := &pushConn{
+		conn:   conn,
+		target: newSubtarget(),
+		done:   make(chan struct{}),
+		subs: [2]map[string]bool{
+			make(map[string]bool), make(map[string]bool),
+		},
+	}
+	client.push = pc
+	go pc.run(s)
+	return pc
+}
+
+func (pc *pushConn) write(data []byte) error {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	_, err := pc.conn.Write(data)
+	return err
+}
+
+// hold holds the frames until release is called.
+func (pc *pushConn) hold() {
+	pc.target.cond.L.Lock()
+	pc.held = true
+	pc.target.cond.L.Unlock()
+}
+
+func (pc *pushConn) release() {
+	pc.target.cond.L.Lock()
+	pc.held = false
+	pc.target.cond.Broadcast()
+	pc.target.cond.L.Unlock()
+}
+
+func (pc *pushConn) push(frame []byte) error {
+	pc.target.cond.L.Lock()
+	defer pc.target.cond.L.Unlock()
+	if pc.target.closed {
+		return errLiveFenceWrite
+	}
+	pc.frames = append(pc.frames, frame...)
+	pc.target.cond.Broadcast()
+	return nil
+}
+
+// run writes the frames until the connection is closed.
+func (pc *pushConn) run(s *Server) {
+	pc.target.cond.L.Lock()
+	defer pc.target.cond.L.Unlock()
+	for !pc.target.closed {
+		if pc.held || len(pc.frames) == 0 && len(pc.target.msgs) == 0 {
+			pc.target.cond.Wait()
+			continue
+		}
+		data, msgs := pc.frames, pc.target.msgs
+		pc.frames, pc.target.msgs = nil, nil
+		pc.target.cond.L.Unlock()
+		for _, m := range msgs {
+			if m.kind == pubsubPattern {
+				data = appendRESP3Push(data, -1, "pmessage", m.pattern,
+					m.channel, m.message)
+			} else {
+				data = appendRESP3Push(data, -1, "message", m.channel,
+					m.message)
+			}
+		}
+		s.statsTotalMsgsSent.Add(int64(len(msgs)))
+		err := pc.write(data)
+		pc.target.cond.L.Lock()
+		if err != nil {
+			break
+		}
+	}
+}
+
+// close unsubscribes the connection and stops its live fences.
+func (pc *pushConn) close(s *Server) {
+	for kind, subs := range pc.subs {
+		for channel := range subs {
+			s.pubsub.unregister(kind, channel, pc.target)
+		}
+	}
+	pc.target.cond.L.Lock()
+	pc.target.closed = true
+	pc.target.cond.Broadcast()
+	pc.target.cond.L.Unlock()
+	close(pc.done)
+}
+
+// goLivePush runs a live command of a RESP3 client in the background, with
+// its messages as push frames. The live commands that need to take over the
+// connection, such as MONITOR, return their error.
+func (s *Server) goLivePush(inerr error, client *Client, conn net.Conn,
+	msg *Message,
+) error {
+	switch lfs := inerr.(type) {
+	case liveFenceSwitches:
+		pc := s.pushConn(client, conn)
+		errc := make(chan error, 2)
+		go func() {
+			ready := func() error {
+				errc <- nil
+				return nil
+			}
+			errc <- s.watchLiveFence(lfs, msg, pc.done, ready,
+				func(m string) error {
+					return pc.push(appendRESP3Push(nil, -1, "fence", m))
+				})
+		}()
+		if err := <-errc; err != nil {
+			v, _ := resp.ErrorValue(errors.New("ERR " + err.Error())).MarshalRESP()
+			client.out = append(client.out, v...)
+			return nil
+		}
+		pc.hold()
+		client.out = append(client.out, "+OK\r\n"...)
+		return nil
+	case liveSubscriptionSwitches:
+		pc := s.pushConn(client, conn)
+		kind := pubsubChannel
+		if msg.Command() == "psubscribe" {
+			kind = pubsubPattern
+		}
+		pc.hold()
+		for _, channel := range msg.Args[1:] {
+			pc.subs[kind][channel] = true
+			s.pubsub.register(kind, channel, pc.target)
+			client.out = appendRESP3Push(client.out,
+				len(pc.subs[0])+len(pc.subs[1]), msg.Command(), channel)
+		}
+		return nil
+	}
+	return inerr
+}
+
+// pushUnsubscribe handles UNSUBSCRIBE and PUNSUBSCRIBE for a RESP3 client,
+// returning the push frames that confirm it. With no channels, all channels
+// of the kind are unsubscribed.
+func (s *Server) pushUnsubscribe(client *Client, msg *Message) []byte {
+	kind := pubsubChannel
+	if msg.Command() == "punsubscribe" {
+		kind = pubsubPattern
+	}
+	channels := msg.Args[1:]
+	var subs map[string]bool
+	if client.push != nil {
+		subs = client.push.subs[kind]
+	}
+	if len(channels) == 0 {
+		for channel := range subs {
+			channels = append(channels, channel)
+		}
+	}
+	var count int
+	if client.push != nil {
+		count = len(client.push.subs[0]) + len(client.push.subs[1])
+	}
+	var data []byte
+	if len(channels) == 0 {
+		return appendRESP3Push(data, count, msg.Command(), "")
+	}
+	for _, channel := range channels {
+		if subs[channel] {
+			delete(subs, channel)
+			s.pubsub.unregister(kind, channel, client.push.target)
+			count--
+		}
+		data = appendRESP3Push(data, count, msg.Command(), channel)
+	}
+	return data
+}
@@ -32,6 +32,7 @@ const (
 	outputPoints
 	outputHashes
 	outputBounds
+	outputCluster
 )
 
 type scanWriter struct {
@@ -66,6 +67,7 @@ type scanWriter struct {
 	tileX          int
 	tileY          int
 	tileZ          int
+	clusters       *clusterer
 }
 
 type ScanWriterParams struct {
@@ -90,15 +92,16 @@ func (s *Server) newScanWriter(
 	default:
 		return nil, errors.New("invalid output type")
 	case outputIDs, outputObjects, outputCount, outputBounds, outputPoints,
-		outputHashes:
+		outputHashes, outputCluster:
 	}
 	if limit == 0 {
-		if output == outputCount {
+		if output == outputCount || output == outputCluster {
 			limit = math.MaxUint64
 		} else {
 			limit = limitItems
 		}
 	}
+	msg.output = output
 	sw := &scanWriter{
 		s:           s,
 		wr:          wr,
@@ -138,6 +141,10 @@ func (sw *scanWriter) hasFieldsOutput() bool {
 }
 
 func (sw *scanWriter) writeFoot() {
+	sw.msg.iters, sw.msg.items = sw.numberIters, sw.numberItems
+	if sw.output == outputCount || sw.clusters != nil {
+		sw.msg.items = sw.count
+	}
 	if sw.mvt {
 		sw.wr.WriteString(`,"mvt":"`)
 	} else {
@@ -167,6 +174,8 @@ func (sw *scanWriter) writeFoot() {
 				sw.wr.WriteString(`,"bounds":[`)
 			case outputHashes:
 				sw.wr.WriteString(`,"hashes":[`)
+			case outputCluster:
+				sw.wr.WriteString(`,"clusters":`)
 			case outputCount:
 
 			}
@@ -175,7 +184,13 @@ func (sw *scanWriter) writeFoot() {
 	}
 	var mvtTile []byte
 	if sw.mvt {
-		mvtTile = mvtRender(sw.tileX, sw.tileY, sw.tileZ, sw.mvtObjs)
+		mvtTile = mvtRender(sw.tileX, sw.tileY, sw.tileZ, sw.mvtItems())
+	} else if sw.clusters != nil {
+		if sw.msg.OutputType == JSON {
+			sw.wr.Write(sw.clusters.appendJSON(nil))
+		} else {
+			sw.values = sw.clusters.respValues()
+		}
 	} else {
 		for _, opts := range sw.filled {
 			sw.writeFilled(opts)
@@ -194,7 +209,7 @@ func (sw *scanWriter) writeFoot() {
 			switch sw.output {
 			default:
 				sw.wr.WriteByte(']')
-			case outputCount:
+			case outputCount, outputCluster:
 			}
 		}
 		sw.wr.WriteString(`,"count":` + strconv.FormatUint(sw.count, 10))
@@ -356,6 +371,10 @@ func (sw *scanWriter) pushObject(opts ScanWriterParams) (keepGoing bool,
 	if sw.output == outputCount {
 		return sw.count < sw.limit, nil
 	}
+	if sw.clusters != nil {
+		sw.clusters.add(opts.obj)
+		return sw.count < sw.limit, nil
+	}
 	if opts.clip != nil {
 		// create a newly clipped object
 		opts.obj = object.New(
@@ -366,7 +385,8 @@ func (sw *scanWriter) pushObject(opts ScanWriterParams) (keepGoing bool,
 		)
 	}
 	if sw.mvt {
-		sw.mvtObjs = append(sw.mvtObjs, mvtObj{opts.obj.ID(), opts.obj.Geo()})
+		sw.mvtObjs = append(sw.mvtObjs,
+			mvtObj{opts.obj.ID(), opts.obj.Geo(), opts.obj.Fields(), nil})
 	}
 	if !sw.fullFields {
 		opts.obj.Fields().Scan(func(f field.Field) bool {
@@ -383,6 +403,14 @@ func (sw *scanWriter) pushObject(opts ScanWriterParams) (keepGoing bool,
 	return keepGoing, nil
 }
 
+// mvtItems returns the objects of a tile, or the clusters of the objects.
+func (sw *scanWriter) mvtItems() []mvtObj {
+	if sw.clusters != nil {
+		return sw.clusters.mvtObjs()
+	}
+	return sw.mvtObjs
+}
+
 func (sw *scanWriter) writeObject(opts ScanWriterParams) {
 	n := len(sw.filled)
 	sw.pushObject(opts)
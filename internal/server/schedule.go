@@ -0,0 +1,394 @@
+package server
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata" // the time zones of TZ, for systems without zoneinfo
+
+	"github.com/aiqia-dev/meridian/internal/log"
+	"github.com/aiqia-dev/meridian/internal/object"
+)
+
+// Hook schedules. A hook with a schedule only sends events while its window
+// is active:
+//
+//	SCHEDULE cron DURATION seconds [TZ zone] [EXIT]
+//	ACTIVE from to [TZ zone] [EXIT]
+//
+// SCHEDULE opens a window at each time that matches the cron expression,
+// which lasts for the duration. ACTIVE opens a window every day, from one
+// time of day (HH:MM or HH:MM:SS) to the other, and the window wraps around
+// midnight when "to" is earlier than "from". The zone defaults to UTC. With
+// EXIT, the objects inside the fence get an "exit" event when a window
+// closes.
+
+// cronSpec is a cron expression with five fields: minute, hour, day of the
+// month, month, and day of the week.
+type cronSpec struct {
+	minute, hour, dom, month, dow uint64 // bitsets
+	domStar, dowStar              bool
+}
+
+var cronMonths = []string{"jan", "feb", "mar", "apr", "may", "jun", "jul",
+	"aug", "sep", "oct", "nov", "dec"}
+
+var cronDays = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// parseCronValue parses a number or a name, where names[0] is min.
+func parseCronValue(s string, min, max int, names []string) (int, bool) {
+	for i, name := range names {
+		if strings.EqualFold(s, name) {
+			return min + i, true
+		}
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < min || n > max {
+		return 0, false
+	}
+	return n, true
+}
+
+// parseCronField parses a comma separated list of "*", "a", "a-b", with an
+// optional "/step", into a bitset.
+func parseCronField(s string, min, max int, names []string,
+) (bits uint64, star bool, ok bool) {
+	for _, part := range strings.Split(s, ",") {
+		rng, step := part, 1
+		if i := strings.IndexByte(part, '/'); i != -1 {
+			rng = part[:i]
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return 0, false, false
+			}
+			step = n
+		}
+		lo, hi := min, max
+		switch {
+		case rng == "*":
+			star = star || step == 1
+		case strings.IndexByte(rng, '-') != -1:
+			i := strings.IndexByte(rng, '-')
+			if lo, ok = parseCronValue(rng[:i], min, max, names); !ok {
+				return 0, false, false
+			}
+			if hi, ok = parseCronValue(rng[i+1:], min, max, names); !ok ||
+				hi < lo {
+				return 0, false, false
+			}
+		default:
+			if lo, ok = parseCronValue(rng, min, max, names); !ok {
+				return 0, false, false
+			}
+			if step == 1 {
+				hi = lo
+			}
+		}
+		for i := lo; i <= hi; i += step {
+			bits |= 1 << uint(i)
+		}
+	}
+	return bits, star, true
+}
+
+func parseCronSpec(expr string) (*cronSpec, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, errInvalidArgument(expr)
+	}
+	var c cronSpec
+	var ok bool
+	if c.minute, _, ok = parseCronField(fields[0], 0, 59, nil); !ok {
+		return nil, errInvalidArgument(expr)
+	}
+	if c.hour, _, ok = parseCronField(fields[1], 0, 23, nil); !ok {
+		return nil, errInvalidArgument(expr)
+	}
+	if c.dom, c.domStar, ok = parseCronField(fields[2], 1, 31, nil); !ok {
+		return nil, errInvalidArgument(expr)
+	}
+	if c.month, _, ok = parseCronField(fields[3], 1, 12, cronMonths); !ok {
+		return nil, errInvalidArgument(expr)
+	}
+	if c.dow, c.dowStar, ok = parseCronField(fields[4], 0, 7, cronDays); !ok {
+		return nil, errInvalidArgument(expr)
+	}
+	if c.dow&(1<<7) != 0 {
+		// 7 is also sunday
+		c.dow |= 1
+	}
+	return &c, nil
+}
+
+// dayMatch returns true when the day matches. As in cron, when both the day
+// of the month and the day of the week are restricted, either one matches.
+func (c *cronSpec) dayMatch(t time.Time) bool {
+	dom := c.dom&(1<<uint(t.Day())) != 0
+	dow := c.dow&(1<<uint(t.Weekday())) != 0
+	if c.domStar || c.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// next returns the first time that matches at or after t, or the zero time
+// when nothing matches within five years.
+func (c *cronSpec) next(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	if t.Second() > 0 || t.Nanosecond() > 0 {
+		t = t.Truncate(time.Minute).Add(time.Minute)
+	}
+	limit := t.Year() + 5
+wrap:
+	if t.Year() > limit {
+		return time.Time{}
+	}
+	for c.month&(1<<uint(t.Month())) == 0 {
+		t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		if t.Month() == time.January {
+			goto wrap
+		}
+	}
+	for !c.dayMatch(t) {
+		t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		if t.Day() == 1 {
+			goto wrap
+		}
+	}
+	for c.hour&(1<<uint(t.Hour())) == 0 {
+		t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, loc).
+			Add(time.Hour)
+		if t.Hour() == 0 {
+			goto wrap
+		}
+	}
+	for c.minute&(1<<uint(t.Minute())) == 0 {
+		t = t.Add(time.Minute)
+		if t.Minute() == 0 {
+			goto wrap
+		}
+	}
+	return t
+}
+
+// hookSchedule is the window of a hook, with SCHEDULE or ACTIVE.
+type hookSchedule struct {
+	cron     *cronSpec // SCHEDULE, nil with ACTIVE
+	expr     string
+	duration time.Duration
+	from, to time.Duration // ACTIVE, times of day
+	loc      *time.Location
+	exit     bool // send exit events when a window closes
+}
+
+// parseTimeOfDay parses HH:MM or HH:MM:SS.
+func parseTimeOfDay(s string) (time.Duration, error) {
+	for _, layout := range []string{"15:04", "15:04:05"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return time.Duration(t.Hour())*time.Hour +
+				time.Duration(t.Minute())*time.Minute +
+				time.Duration(t.Second())*time.Second, nil
+		}
+	}
+	return 0, errInvalidArgument(s)
+}
+
+// formatTimeOfDay formats a time of day as HH:MM or HH:MM:SS.
+func formatTimeOfDay(d time.Duration) string {
+	t := time.Date(0, 1, 1, 0, 0, 0, 0, time.UTC).Add(d)
+	if t.Second() > 0 {
+		return t.Format("15:04:05")
+	}
+	return t.Format("15:04")
+}
+
+// parseHookSchedule parses the arguments that follow SCHEDULE or ACTIVE, and
+// returns the remaining arguments.
+func parseHookSchedule(kind string, vs []string) (
+	sched *hookSchedule, rest []string, err error,
+) {
+	var ok bool
+	sched = &hookSchedule{loc: time.UTC}
+	if kind == "schedule" {
+		var expr, sdur string
+		if vs, expr, ok = tokenval(vs); !ok || expr == "" {
+			return nil, nil, errInvalidNumberOfArguments
+		}
+		if sched.cron, err = parseCronSpec(expr); err != nil {
+			return nil, nil, err
+		}
+		sched.expr = expr
+		if len(vs) < 2 || strings.ToLower(vs[0]) != "duration" {
+			return nil, nil, errors.New("missing DURATION argument")
+		}
+		if vs, sdur, ok = tokenval(vs[1:]); !ok || sdur == "" {
+			return nil, nil, errInvalidNumberOfArguments
+		}
+		if sched.duration, err = parseSeconds(sdur); err != nil {
+			return nil, nil, err
+		}
+	} else {
+		var sfrom, sto string
+		if vs, sfrom, ok = tokenval(vs); !ok || sfrom == "" {
+			return nil, nil, errInvalidNumberOfArguments
+		}
+		if vs, sto, ok = tokenval(vs); !ok || sto == "" {
+			return nil, nil, errInvalidNumberOfArguments
+		}
+		if sched.from, err = parseTimeOfDay(sfrom); err != nil {
+			return nil, nil, err
+		}
+		if sched.to, err = parseTimeOfDay(sto); err != nil {
+			return nil, nil, err
+		}
+		if sched.from == sched.to {
+			return nil, nil, errInvalidArgument(sto)
+		}
+	}
+	if len(vs) > 0 && strings.ToLower(vs[0]) == "tz" {
+		var zone string
+		if vs, zone, ok = tokenval(vs[1:]); !ok || zone == "" {
+			return nil, nil, errInvalidNumberOfArguments
+		}
+		if sched.loc, err = time.LoadLocation(zone); err != nil {
+			return nil, nil, errInvalidArgument(zone)
+		}
+	}
+	if len(vs) > 0 && strings.ToLower(vs[0]) == "exit" {
+		sched.exit = true
+		vs = vs[1:]
+	}
+	return sched, vs, nil
+}
+
+// args returns the SETHOOK arguments that reproduce the schedule.
+func (sched *hookSchedule) args() []string {
+	var args []string
+	if sched.cron != nil {
+		args = []string{"schedule", sched.expr, "duration",
+			formatSeconds(sched.duration)}
+	} else {
+		args = []string{"active", formatTimeOfDay(sched.from),
+			formatTimeOfDay(sched.to)}
+	}
+	if sched.loc != time.UTC {
+		args = append(args, "tz", sched.loc.String())
+	}
+	if sched.exit {
+		args = append(args, "exit")
+	}
+	return args
+}
+
+// json returns the schedule as a JSON object.
+func (sched *hookSchedule) json() string {
+	var s string
+	if sched.cron != nil {
+		s = `{"cron":` + jsonString(sched.expr) +
+			`,"duration":` + formatSeconds(sched.duration)
+	} else {
+		s = `{"from":` + jsonString(formatTimeOfDay(sched.from)) +
+			`,"to":` + jsonString(formatTimeOfDay(sched.to))
+	}
+	return s + `,"tz":` + jsonString(sched.loc.String()) +
+		`,"exit":` + strconv.FormatBool(sched.exit) + `}`
+}
+
+// window returns if a window is active at t, and until when that holds.
+func (sched *hookSchedule) window(t time.Time) (active bool, until time.Time) {
+	if sched.cron != nil {
+		// the last window that started within the duration
+		start := sched.cron.next(t.Add(-sched.duration).Add(1), sched.loc)
+		if start.IsZero() {
+			return false, t.Add(24 * time.Hour)
+		}
+		if !start.After(t) {
+			return true, start.Add(sched.duration)
+		}
+		return false, start
+	}
+	lt := t.In(sched.loc)
+	day := func(days int, tod time.Duration) time.Time {
+		return time.Date(lt.Year(), lt.Month(), lt.Day()+days, 0, 0, 0, 0,
+			sched.loc).Add(tod)
+	}
+	tod := lt.Sub(day(0, 0))
+	if sched.from < sched.to {
+		switch {
+		case tod < sched.from:
+			return false, day(0, sched.from)
+		case tod < sched.to:
+			return true, day(0, sched.to)
+		}
+		return false, day(1, sched.from)
+	}
+	// wraps around midnight
+	switch {
+	case tod < sched.to:
+		return true, day(0, sched.to)
+	case tod < sched.from:
+		return false, day(0, sched.from)
+	}
+	return true, day(1, sched.to)
+}
+
+// hookWindow is the state of a schedule window, from a time until another.
+type hookWindow struct {
+	active      bool
+	from, until time.Time
+}
+
+// scheduleActive returns true when the hook sends events at t, which is
+// always for hooks without a schedule.
+func (h *Hook) scheduleActive(t time.Time) bool {
+	if h.schedule == nil {
+		return true
+	}
+	w := &h.window
+	if t.Before(w.from) || !t.Before(w.until) {
+		w.active, w.until = h.schedule.window(t)
+		w.from = t
+	}
+	return w.active
+}
+
+// hookSchedulesEqual returns true when two schedules are equal.
+func hookSchedulesEqual(a, b *hookSchedule) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return strings.Join(a.args(), " ") == strings.Join(b.args(), " ")
+}
+
+// backgroundHookSchedules sends the exit events of the windows that closed.
+// Only the leader sends the events.
+func (s *Server) backgroundHookSchedules(now time.Time) {
+	if len(s.scheduled) == 0 {
+		return
+	}
+	leader := s.config.followHost() == ""
+	var q hookMsgs
+	for _, hook := range s.scheduled {
+		open := hook.scheduleActive(now)
+		closed := hook.schedOpen && !open
+		hook.schedOpen = open
+		if !closed || !hook.schedule.exit || !leader {
+			continue
+		}
+		col, _ := s.cols.Get(hook.Key)
+		if col == nil {
+			continue
+		}
+		col.Scan(false, nil, nil, func(obj *object.Object) bool {
+			if hookInside(hook, obj) {
+				q.add(hook, s.hookObjectMsgs(hook, obj, "schedule",
+					[]string{"exit"}, now))
+			}
+			return true
+		})
+	}
+	if err := s.queueHookMsgs(&q); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,113 @@
+package server
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCronNext(t *testing.T) {
+	at := func(s string) time.Time {
+		t, err := time.Parse("2006-01-02 15:04:05", s)
+		if err != nil {
+			panic(err)
+		}
+		return t
+	}
+	tests := []struct {
+		expr, from, next string
+	}{
+		{"* * * * *", "2024-01-15 10:30:00", "2024-01-15 10:30:00"},
+		{"* * * * *", "2024-01-15 10:30:01", "2024-01-15 10:31:00"},
+		{"0 7 * * mon-fri", "2024-01-12 07:00:01", "2024-01-15 07:00:00"},
+		{"*/15 * * * *", "2024-01-15 10:31:00", "2024-01-15 10:45:00"},
+		{"30 23 31 * *", "2024-02-01 00:00:00", "2024-03-31 23:30:00"},
+		{"0 0 29 feb *", "2025-01-01 00:00:00", "2028-02-29 00:00:00"},
+		{"0 12 1 * 0", "2024-01-02 00:00:00", "2024-01-07 12:00:00"},
+		{"0 12 * * 7", "2024-01-02 00:00:00", "2024-01-07 12:00:00"},
+		{"0 0 30 feb *", "2024-01-01 00:00:00", "0001-01-01 00:00:00"},
+	}
+	for _, tt := range tests {
+		c, err := parseCronSpec(tt.expr)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.expr, err)
+		}
+		next := c.next(at(tt.from), time.UTC)
+		if !next.Equal(at(tt.next)) {
+			t.Fatalf("%s from %s: expected %s, got %s", tt.expr, tt.from,
+				tt.next, next)
+		}
+	}
+	for _, expr := range []string{"* * * *", "60 * * * *", "* 24 * * *",
+		"* * 0 * *", "* * * 13 *", "* * * * 8", "5-1 * * * *", "*/0 * * * *",
+		"* * * foo *"} {
+		if _, err := parseCronSpec(expr); err == nil {
+			t.Fatalf("%s: expected an error", expr)
+		}
+	}
+}
+
+func TestHookScheduleWindow(t *testing.T) {
+	at := func(s string) time.Time {
+		t, err := time.Parse("2006-01-02 15:04", s)
+		if err != nil {
+			panic(err)
+		}
+		return t
+	}
+	sched, rest, err := parseHookSchedule("schedule",
+		[]string{"0 7 * * mon-fri", "DURATION", "3600", "EXIT", "NEARBY"})
+	if err != nil || len(rest) != 1 || !sched.exit {
+		t.Fatalf("unexpected %v %v", rest, err)
+	}
+	tests := []struct {
+		now    string
+		active bool
+		until  string
+	}{
+		{"2024-01-15 06:59", false, "2024-01-15 07:00"},
+		{"2024-01-15 07:00", true, "2024-01-15 08:00"},
+		{"2024-01-15 07:59", true, "2024-01-15 08:00"},
+		{"2024-01-15 08:00", false, "2024-01-16 07:00"},
+		{"2024-01-13 07:30", false, "2024-01-15 07:00"},
+	}
+	for _, tt := range tests {
+		active, until := sched.window(at(tt.now))
+		if active != tt.active || !until.Equal(at(tt.until)) {
+			t.Fatalf("%s: expected %t until %s, got %t until %s", tt.now,
+				tt.active, tt.until, active, until)
+		}
+	}
+
+	// wraps around midnight, in another zone
+	sched, _, err = parseHookSchedule("active",
+		[]string{"22:00", "06:00", "TZ", "America/Sao_Paulo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests = []struct {
+		now    string
+		active bool
+		until  string
+	}{
+		{"2024-01-15 00:59", false, "2024-01-15 01:00"},
+		{"2024-01-15 01:00", true, "2024-01-15 09:00"},
+		{"2024-01-15 09:00", false, "2024-01-16 01:00"},
+		{"2024-01-16 01:00", true, "2024-01-16 09:00"},
+	}
+	for _, tt := range tests {
+		active, until := sched.window(at(tt.now))
+		if active != tt.active || !until.Equal(at(tt.until)) {
+			t.Fatalf("%s: expected %t until %s, got %t until %s", tt.now,
+				tt.active, tt.until, active, until)
+		}
+	}
+	if args := sched.args(); len(args) != 5 || args[4] != "America/Sao_Paulo" {
+		t.Fatalf("unexpected args %v", args)
+	}
+	for _, args := range [][]string{{"07:00", "07:00"}, {"7", "08:00"},
+		{"07:00", "08:00", "TZ", "Mars/Olympus"}} {
+		if _, _, err := parseHookSchedule("active", args); err == nil {
+			t.Fatalf("%v: expected an error", args)
+		}
+	}
+}
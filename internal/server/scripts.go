@@ -44,6 +44,7 @@ type lStatePool struct {
 	s     *Server
 	saved []*lua.LState
 	total int
+	users map[*lua.LState]*aclUser // ACL users of the running scripts
 }
 
 // newPool returns a new pool of lua states
@@ -137,7 +138,8 @@ func (pl *lStatePool) New() *lua.LState {
 	call := func(ls *lua.LState) int {
 		evalCmd, args := getArgs(ls)
 		var numRet int
-		if res, err := pl.s.luaMeridianCall(evalCmd, args[0], args[1:]...); err != nil {
+		if res, err := pl.s.luaMeridianCall(evalCmd, pl.user(L), args[0],
+			args[1:]...); err != nil {
 			ls.RaiseError("ERR %s", err.Error())
 			numRet = 0
 		} else {
@@ -148,7 +150,8 @@ func (pl *lStatePool) New() *lua.LState {
 	}
 	pcall := func(ls *lua.LState) int {
 		evalCmd, args := getArgs(ls)
-		if res, err := pl.s.luaMeridianCall(evalCmd, args[0], args[1:]...); err != nil {
+		if res, err := pl.s.luaMeridianCall(evalCmd, pl.user(L), args[0],
+			args[1:]...); err != nil {
 			ls.Push(ConvertToLua(ls, resp.ErrorValue(err)))
 		} else {
 			ls.Push(ConvertToLua(ls, res))
@@ -207,6 +210,27 @@ func (pl *lStatePool) New() *lua.LState {
 	return L
 }
 
+// setUser sets the ACL user that runs a script on a lua state. A nil user
+// is the default user.
+func (pl *lStatePool) setUser(L *lua.LState, u *aclUser) {
+	pl.m.Lock()
+	if u == nil {
+		delete(pl.users, L)
+	} else {
+		if pl.users == nil {
+			pl.users = make(map[*lua.LState]*aclUser)
+		}
+		pl.users[L] = u
+	}
+	pl.m.Unlock()
+}
+
+func (pl *lStatePool) user(L *lua.LState) *aclUser {
+	pl.m.Lock()
+	defer pl.m.Unlock()
+	return pl.users[L]
+}
+
 func (pl *lStatePool) Put(L *lua.LState) {
 	pl.m.Lock()
 	pl.saved = append(pl.saved, L)
@@ -442,6 +466,8 @@ func (s *Server) cmdEvalUnified(scriptIsSha bool, msg *Message) (res resp.Value,
 		luaDeadline = lua.LNumber(float64(dlTime.UnixNano()) / 1e9)
 	}
 	defer s.luapool.Put(luaState)
+	s.luapool.setUser(luaState, msg.acl)
+	defer s.luapool.setUser(luaState, nil)
 
 	keysTbl := luaState.CreateTable(int(numkeys), 0)
 	for i = 0; i < numkeys; i++ {
@@ -690,10 +716,13 @@ func (s *Server) commandInScript(msg *Message) (
 	return
 }
 
-func (s *Server) luaMeridianCall(evalcmd string, cmd string, args ...string) (resp.Value, error) {
+func (s *Server) luaMeridianCall(evalcmd string, acl *aclUser, cmd string,
+	args ...string,
+) (resp.Value, error) {
 	msg := &Message{}
 	msg.OutputType = RESP
 	msg.Args = append([]string{cmd}, args...)
+	msg.acl = acl
 
 	if msg.Command() == "timeout" {
 		if err := rewriteTimeoutMsg(msg); err != nil {
@@ -703,13 +732,18 @@ func (s *Server) luaMeridianCall(evalcmd string, cmd string, args ...string) (re
 
 	switch msg.Command() {
 	case "ping", "echo", "auth", "massinsert", "shutdown", "gc",
-		"sethook", "pdelhook", "delhook",
+		"sethook", "pdelhook", "delhook", "hookpause", "hookresume",
 		"follow", "readonly", "config", "output", "client",
 		"aofshrink",
 		"script load", "script exists", "script flush",
 		"eval", "evalsha", "evalro", "evalrosha", "evalna", "evalnasha":
 		return resp.NullValue(), errCmdNotSupported
 	}
+	if acl != nil {
+		if err := acl.authorize(msg); err != nil {
+			return resp.NullValue(), err
+		}
+	}
 
 	switch evalcmd {
 	case "eval", "evalsha":
@@ -627,6 +627,9 @@ func (s *Server) cmdWITHINorINTERSECTS(cmd string, msg *Message) (res resp.Value
 	if err != nil {
 		return NOMessage, err
 	}
+	if sargs.output == outputCluster {
+		sw.clusters = newClusterer(sargs.cradius, sargs.czoom)
+	}
 	if msg.OutputType == JSON {
 		wr.WriteString(`{"ok":true`)
 	}
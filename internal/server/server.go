@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"crypto/rand"
 	"crypto/sha1"
+	"crypto/tls"
 	"encoding/base64"
 	"encoding/binary"
 	"encoding/hex"
@@ -33,6 +34,7 @@ import (
 	"github.com/tidwall/redcon"
 	"github.com/tidwall/resp"
 	"github.com/tidwall/rtree"
+	"google.golang.org/grpc"
 	"github.com/aiqia-dev/meridian/core"
 	"github.com/aiqia-dev/meridian/internal/admin"
 	"github.com/aiqia-dev/meridian/internal/backup"
@@ -66,10 +68,12 @@ type commandDetails struct {
 	old *object.Object // previous object, if any
 
 	updated   bool              // object was updated
+	expired   bool              // object was deleted because it expired
 	timestamp time.Time         // timestamp when the update occurred
 	parent    bool              // when true, only children are forwarded
 	pattern   string            // PDEL key pattern
 	children  []*commandDetails // for multi actions such as "PDEL"
+	aofArgs   []string          // args for the aof, when not the msg args
 }
 
 type rwlocker interface {
@@ -170,8 +174,12 @@ type Server struct {
 	epc     *endpoint.Manager
 	epool   *exprPool
 
-	lnmu sync.Mutex
-	ln   net.Listener // server listener
+	lnmu    sync.Mutex
+	ln      net.Listener // server listener
+	tls     *tlsCerts    // nil without TLS
+	acl     aclTable     // ACL users
+	limiter rateLimiter  // rate limits
+	slowlog slowLog      // slow commands
 
 	// env opts
 	geomParseOpts geojson.ParseOptions
@@ -206,8 +214,13 @@ type Server struct {
 	qdb  *buntdb.DB // hook queue log
 	qidx uint64     // hook queue log last idx
 
+	eseq     uint64       // event log last sequence
+	elogSize atomic.Int64 // event log total size in bytes
+
 	cols *btree.Map[string, *collection.Collection] // data collections
 
+	tiles tileCache // rendered multi-layer tiles
+
 	hooks        *btree.BTree // hook name -- [string]*Hook
 	hookCross    *rtree.RTree // hook spatial tree for "cross" geofences
 	hookTree     *rtree.RTree // hook spatial tree for all
@@ -215,6 +228,11 @@ type Server struct {
 	groupHooks   *btree.BTree // hooks that are connected to objects
 	groupObjects *btree.BTree // objects that are connected to hooks
 	hookExpires  *btree.BTree // queue of all hooks marked for expiration
+	hookDLQs     hookDLQTable // dead-letter queue policies, by hook name
+
+	colExpired map[string]int64 // expired objects, by collection, for STATS
+	stale      staleTimers      // stale object timers
+	scheduled  map[string]*Hook // hooks with a schedule, by name
 
 	// followers (external aof readers)
 	follows   map[*bytes.Buffer]bool
@@ -222,10 +240,12 @@ type Server struct {
 	lstack    []*commandDetails
 	lives     map[*liveBuffer]bool
 	lcond     *sync.Cond   // live geofence signal
+	sse       sseHub       // server-sent event streams
 	faofsz    int          // last reported aofsize
 	fcupflags atomic.Int32 // follow caught up (caughtUp and caughtUpOnce)
 	aofconnM  map[net.Conn]io.Closer
 	pubq      pubQueue
+	auditlog  auditLog // audit log file and forward queue
 
 	// lua scripts
 	luascripts *lScriptMap
@@ -249,6 +269,7 @@ type Options struct {
 	Dir            string
 	UseHTTP        bool
 	MetricsAddr    string
+	GRPCAddr       string // address for the grpc command api
 	UnixSocketPath string // path for unix socket
 	ClientOutput   string // "" or "resp" or "json"
 
@@ -299,6 +320,16 @@ type Options struct {
 	BackupInterval      time.Duration
 	BackupRetentionDays int
 	BackupCompress      bool
+
+	// TLS for the client port and the connections to the leader. The
+	// TLSClientAuth is "no", "optional" or "yes".
+	TLSCert       string
+	TLSKey        string
+	TLSCA         string
+	TLSClientAuth string
+
+	// TLSReload reloads the TLS certificates from their files.
+	TLSReload <-chan bool
 }
 
 // Serve starts a new meridian server
@@ -359,6 +390,7 @@ func Serve(opts Options) error {
 		http:      opts.UseHTTP,
 		pubsub:    newPubsub(),
 		pubq:      pubQueue{cond: sync.NewCond(&sync.Mutex{})},
+		auditlog:  auditLog{cond: sync.NewCond(&sync.Mutex{})},
 		monconns:  make(map[net.Conn]bool),
 		cols:      &btree.Map[string, *collection.Collection]{},
 
@@ -396,6 +428,21 @@ func Serve(opts Options) error {
 		}
 		log.Infof("RequirePass enabled")
 	}
+	s.tiles.resize(s.config.tileCacheSize())
+	s.epc.SetLocalDir(s.endpointDir())
+	if err := s.acl.load(s.config.aclUsers()); err != nil {
+		return err
+	}
+	if err := s.limiter.load(s.config.rateLimits()); err != nil {
+		return err
+	}
+	if s.tls, err = newTLSCerts(opts); err != nil {
+		return err
+	}
+	if s.tls != nil {
+		go s.reloadTLS(opts.TLSReload)
+		log.Infof("TLS enabled")
+	}
 
 	// Send "500 Internal Server" error instead of "200 OK" for json responses
 	// with `"ok":false`. T38HTTP500ERRORS=1
@@ -502,9 +549,30 @@ func Serve(opts Options) error {
 	if err != nil {
 		return err
 	}
+	err = qdb.CreateIndex("hookdlq", hookDLQPrefix+"*", buntdb.IndexJSONCaseSensitive("hook"))
+	if err != nil {
+		return err
+	}
+	err = qdb.CreateIndex("events", eventLogPrefix+"*",
+		buntdb.IndexJSONCaseSensitive("hook"), buntdb.IndexJSON("seq"))
+	if err != nil {
+		return err
+	}
+	// expired hook log entries are moved to the dead-letter queue
+	var qcfg buntdb.Config
+	if err := qdb.ReadConfig(&qcfg); err != nil {
+		return err
+	}
+	qcfg.OnExpiredSync = s.queueItemExpired
+	if err := qdb.SetConfig(qcfg); err != nil {
+		return err
+	}
 
 	s.qdb = qdb
 	s.qidx = qidx
+	if err := s.loadEventLog(); err != nil {
+		return err
+	}
 	if err := s.migrateAOF(); err != nil {
 		return err
 	}
@@ -580,6 +648,30 @@ func Serve(opts Options) error {
 		}()
 	}
 
+	var gs *grpc.Server
+	if opts.GRPCAddr != "" {
+		log.Infof("Listening for gRPC at: %s", opts.GRPCAddr)
+		gln, err := net.Listen("tcp", opts.GRPCAddr)
+		if err != nil {
+			return err
+		}
+		if s.tls != nil {
+			// grpc clients require http/2 in the handshake
+			gln = tls.NewListener(gln, s.tls.serverConfig("h2"))
+		}
+		gs = s.newGRPCServer()
+		bgwg.Add(1)
+		go func() {
+			defer bgwg.Done()
+			err := gs.Serve(gln)
+			if err != nil {
+				if !s.stopServer.Load() {
+					log.Fatalf("grpc server: %s", err)
+				}
+			}
+		}()
+	}
+
 	bgwg.Add(1)
 	go s.processLives(&bgwg)
 	bgwg.Add(1)
@@ -594,20 +686,29 @@ func Serve(opts Options) error {
 	go s.backgroundSyncAOF(&bgwg)
 	bgwg.Add(1)
 	go s.startPublishQueue(&bgwg)
+	bgwg.Add(1)
+	go s.forwardAuditLog(&bgwg)
 	defer func() {
 		log.Debug("Stopping background routines")
 		// Stop background routines
 		s.stopPublishQueue()
+		s.stopAuditLog()
 		s.followc.Add(1) // this will force any follow communication to die
 		s.stopServer.Store(true)
 		if mln != nil {
 			mln.Close() // Stop the metrics server
 		}
+		if gs != nil {
+			gs.Stop() // Stop the grpc server and its live streams
+		}
 		bgwg.Wait()
 	}()
 
 	// Server is now loaded and ready. Wait for network error messages.
+	s.mu.Lock()
+	s.rescheduleStaleTimers()
 	s.loadedAndReady.Store(true)
+	s.mu.Unlock()
 	return <-nerr
 }
 
@@ -638,6 +739,9 @@ func (s *Server) netServe() error {
 	if err != nil {
 		return err
 	}
+	if s.tls != nil {
+		ln = tls.NewListener(ln, s.tls.serverConfig())
+	}
 	s.lnmu.Lock()
 	s.ln = ln
 	s.lnmu.Unlock()
@@ -702,6 +806,10 @@ func (s *Server) netServe() error {
 
 			// set the client keep-alive, if needed
 			if s.config.keepAlive() > 0 {
+				conn := conn
+				if tlsconn, ok := conn.(*tls.Conn); ok {
+					conn = tlsconn.NetConn()
+				}
 				if conn, ok := conn.(*net.TCPConn); ok {
 					conn.SetKeepAlive(true)
 					conn.SetKeepAlivePeriod(
@@ -718,6 +826,9 @@ func (s *Server) netServe() error {
 				delete(s.conns, client.id)
 				s.connsmu.Unlock()
 				log.Debugf("Closed connection: %s", client.remoteAddr)
+				if client.push != nil {
+					client.push.close(s)
+				}
 				conn.Close()
 			}()
 
@@ -766,6 +877,7 @@ func (s *Server) netServe() error {
 							msg.OutputType = defaultOutputType
 						}
 						msg.StrictRESP = client.strictRESP
+						msg.RESP3 = client.resp3
 						if msg.Command() == "quit" {
 							if msg.OutputType == RESP {
 								io.WriteString(client, "+OK\r\n")
@@ -784,6 +896,11 @@ func (s *Server) netServe() error {
 
 						// handle the command
 						err := s.handleInputCommand(client, msg)
+						if err != nil && err.Error() == goingLive && msg.RESP3 {
+							// RESP3 clients receive the live messages as push
+							// frames, on the same connection
+							err = s.goLivePush(err, client, conn, msg)
+						}
 						if err != nil {
 							if err.Error() == goingLive {
 								client.goLiveErr = err
@@ -827,6 +944,7 @@ func (s *Server) netServe() error {
 
 						client.outputType = msg.OutputType
 						client.strictRESP = msg.StrictRESP
+						client.resp3 = msg.RESP3
 					} else {
 						client.Write([]byte("HTTP/1.1 500 Bad Request\r\nConnection: close\r\n\r\n"))
 						break
@@ -853,9 +971,16 @@ func (s *Server) netServe() error {
 						}()
 						s.aofdirty.Store(false)
 					}
-					conn.Write(client.out)
+					if client.push != nil {
+						client.push.write(client.out)
+					} else {
+						conn.Write(client.out)
+					}
 					client.out = nil
 				}
+				if client.push != nil {
+					client.push.release()
+				}
 				if close {
 					break
 				}
@@ -1038,31 +1163,28 @@ func (s *Server) handleInputCommand(client *Client, msg *Message) error {
 			query = msg.Args[0][i+1:]
 			msg.Args[0] = msg.Args[0][:i]
 		}
-		if strings.HasSuffix(msg.Args[0], ".mvt") ||
+		if isTilePath(msg.Args[0]) {
+			return s.handleTiles(client, msg, query)
+		} else if strings.HasSuffix(msg.Args[0], ".mvt") ||
 			strings.HasSuffix(msg.Args[0], ".pbf") {
 			mvt = mvtFilterHTTPArgs(msg, query)
 		} else if strings.HasPrefix(msg.Args[0], "viewer/") ||
 			msg.Args[0] == "viewer" {
 			return viewer.HandleHTTP(client, "/"+strings.Join(msg.Args, "/"),
 				s.opts.DevMode)
+		} else if isOGCPath(msg.Args[0]) {
+			return s.handleOGC(client, msg, query)
 		} else if strings.HasPrefix(msg.Args[0], "admin/") ||
 			msg.Args[0] == "admin" {
 			// Admin panel routes
-			adminConfig := admin.Config{
-				Username:  s.opts.AdminUser,
-				Password:  s.opts.AdminPassword,
-			}
-			if s.opts.AdminJWTSecret != "" {
-				adminConfig.JWTSecret, _ = admin.SecretFromString(s.opts.AdminJWTSecret)
-			} else {
-				adminConfig.JWTSecret, _ = admin.GenerateRandomSecret()
-			}
+			adminConfig := s.adminConfig()
 
 			// Build the full path for routing
 			adminPath := "/" + msg.Args[0]
 
 			// Handle API endpoints
 			if adminPath == "/admin/api/login" && msg.ConnType == HTTP {
+				s.auditAdminLogin(client, msg, adminConfig)
 				return admin.HandleAPILogin(client, msg.Body, adminConfig)
 			}
 			if adminPath == "/admin/api/verify" && msg.ConnType == HTTP {
@@ -1070,7 +1192,7 @@ func (s *Server) handleInputCommand(client *Client, msg *Message) error {
 			}
 			if adminPath == "/admin/api/command" && msg.ConnType == HTTP {
 				// Validate auth token
-				_, err := admin.ValidateCommandAuth(msg.Auth, adminConfig)
+				username, err := admin.ValidateCommandAuth(msg.Auth, adminConfig)
 				if err != nil {
 					return admin.WriteUnauthorizedResponse(client)
 				}
@@ -1090,6 +1212,7 @@ func (s *Server) handleInputCommand(client *Client, msg *Message) error {
 				// Execute the command with JSON output
 				cmdMsg.ConnType = HTTP
 				cmdMsg.OutputType = JSON
+				cmdMsg.admin = username
 				return s.handleInputCommand(client, cmdMsg)
 			}
 
@@ -1106,7 +1229,11 @@ func (s *Server) handleInputCommand(client *Client, msg *Message) error {
 			resStr = res.String()
 		case RESP:
 			var resBytes []byte
-			resBytes, err = res.MarshalRESP()
+			if msg.RESP3 {
+				resBytes = appendRESP3Reply(nil, msg, res)
+			} else {
+				resBytes, err = res.MarshalRESP()
+			}
 			resStr = string(resBytes)
 		}
 		return resStr, err
@@ -1173,13 +1300,17 @@ func (s *Server) handleInputCommand(client *Client, msg *Message) error {
 		case Native:
 			_, err := fmt.Fprintf(client, "$%d %s\r\n", len(res), res)
 			return err
+		case GRPC:
+			client.out = append(client.out, res...)
+			return nil
 		}
 	}
 
 	cmd := msg.Command()
 	defer func() {
-		took := time.Since(start).Seconds()
-		cmdDurations.With(prometheus.Labels{"cmd": cmd}).Observe(took)
+		took := time.Since(start)
+		cmdDurations.With(prometheus.Labels{"cmd": cmd}).Observe(took.Seconds())
+		s.slowLogCmd(client, msg, took)
 	}()
 
 	// Ping. Just send back the response. No need to put through the pipeline.
@@ -1238,21 +1369,20 @@ func (s *Server) handleInputCommand(client *Client, msg *Message) error {
 	}
 
 	if cmd == "hello" {
-		// Not Supporting RESP3+, returns an ERR instead.
 		ot := msg.OutputType
-		if len(msg.Args) > 1 && (msg.Args[1] >= "0" &&
-			msg.Args[1] <= "9") && s.opts.ClientOutput == "json" &&
-			ot == JSON && msg.ConnType == RESP {
-			// Here we are making sure that we ignoring the '-o json' flag, if
-			// used, otherwise the connection will fail for some redis clients
-			// like "github.com/redis/go-redis/v9" are overly strict and expect
-			// a map type or an error as the result of the HELLO command.
+		if msg.ConnType == RESP {
+			// Redis clients expect a RESP reply to HELLO, even when the
+			// '-o json' flag is used.
 			msg.OutputType = RESP
-			msg.StrictRESP = true
 		}
-		err := writeErr("unknown command '" + msg.Args[0] + "'")
-		msg.OutputType = ot
-		return err
+		res, err := s.cmdHELLO(msg, client)
+		if err != nil {
+			err = writeErr(err.Error())
+			msg.OutputType = ot
+			return err
+		}
+		resStr, _ := serializeOutput(res)
+		return writeOutput(resStr)
 	}
 
 	if cmd == "command" && len(msg.Args) > 1 && msg.Args[1] == "DOCS" &&
@@ -1271,7 +1401,15 @@ func (s *Server) handleInputCommand(client *Client, msg *Message) error {
 	var write bool
 
 	if (!client.authd || cmd == "auth") && cmd != "output" && cmd != "healthz" {
-		if s.config.requirePass() != "" {
+		if username, password, ok := aclCredentials(msg); ok {
+			if err := s.aclAuth(client, username, password); err != nil {
+				return writeErr(err.Error())
+			}
+			if cmd == "auth" {
+				resStr, _ := serializeOutput(OKMessage(msg, start))
+				return writeOutput(resStr)
+			}
+		} else if s.config.requirePass() != "" {
 			password := ""
 			// This better be an AUTH command or the Message should contain an Auth
 			if cmd != "auth" && msg.Auth == "" {
@@ -1289,7 +1427,7 @@ func (s *Server) handleInputCommand(client *Client, msg *Message) error {
 				return writeErr("invalid password")
 			}
 			client.authd = true
-			if msg.ConnType != HTTP {
+			if msg.ConnType != HTTP && msg.ConnType != GRPC {
 				resStr, _ := serializeOutput(OKMessage(msg, start))
 				return writeOutput(resStr)
 			}
@@ -1298,6 +1436,20 @@ func (s *Server) handleInputCommand(client *Client, msg *Message) error {
 		}
 	}
 
+	acl, err := s.aclAuthorize(client, msg)
+	if err != nil {
+		s.auditCmd(client, msg, err)
+		return writeErr(err.Error())
+	}
+	msg.acl = acl
+	if err := s.rateLimit(client, msg); err != nil {
+		return writeErr(err.Error())
+	}
+
+	if msg.RESP3 && (cmd == "unsubscribe" || cmd == "punsubscribe") {
+		return writeOutput(string(s.pushUnsubscribe(client, msg)))
+	}
+
 	// choose the locking strategy
 	switch msg.Command() {
 	default:
@@ -1305,7 +1457,7 @@ func (s *Server) handleInputCommand(client *Client, msg *Message) error {
 		defer s.mu.RUnlock()
 	case "set", "del", "drop", "fset", "flushdb",
 		"setchan", "pdelchan", "delchan",
-		"sethook", "pdelhook", "delhook",
+		"sethook", "pdelhook", "delhook", "hookpause", "hookresume",
 		"expire", "persist", "jset", "pdel", "rename", "renamenx":
 		// write operations
 		write = true
@@ -1328,7 +1480,7 @@ func (s *Server) handleInputCommand(client *Client, msg *Message) error {
 			return writeErr("read only")
 		}
 	case "get", "keys", "scan", "nearby", "within", "intersects", "hooks",
-		"chans", "search", "ttl", "bounds", "server", "info", "type", "jget",
+		"hookstats", "chans", "search", "ttl", "bounds", "server", "info", "type", "jget",
 		"evalro", "evalrosha", "role", "fget", "exists", "fexists":
 		// read operations
 		s.mu.RLock()
@@ -1341,7 +1493,7 @@ func (s *Server) handleInputCommand(client *Client, msg *Message) error {
 		if s.config.followHost() != "" && !s.caughtUpOnce() {
 			return writeErr("catching up to leader")
 		}
-	case "follow", "slaveof", "replconf", "readonly", "config":
+	case "follow", "slaveof", "replconf", "readonly", "config", "hookdlq", "replay":
 		// system operations
 		// does not write to aof, but requires a write lock.
 		s.mu.Lock()
@@ -1399,16 +1551,19 @@ func (s *Server) handleInputCommand(client *Client, msg *Message) error {
 		return res, d, err
 	}()
 	if res.Type() == resp.Error {
+		s.auditCmd(client, msg, errors.New(res.String()))
 		return writeErr(res.String())
 	}
 	if err != nil {
 		if err.Error() == goingLive {
 			return err
 		}
+		s.auditCmd(client, msg, err)
 		return writeErr(err.Error())
 	}
 	if write {
 		if err := s.writeAOF(msg.Args, &d); err != nil {
+			s.auditCmd(client, msg, err)
 			if _, ok := err.(errAOFHook); ok {
 				return writeErr(err.Error())
 			}
@@ -1416,6 +1571,7 @@ func (s *Server) handleInputCommand(client *Client, msg *Message) error {
 			return err
 		}
 	}
+	s.auditCmd(client, msg, nil)
 	var resStr string
 	resStr, err = serializeOutput(res)
 	if err != nil {
@@ -1442,6 +1598,7 @@ func randomKey(n int) string {
 func (s *Server) reset() {
 	s.aofsz = 0
 	s.cols.Clear()
+	s.colExpired = nil
 }
 
 func (s *Server) command(msg *Message, client *Client) (
@@ -1466,12 +1623,16 @@ func (s *Server) command(msg *Message, client *Client) (
 		res, d, err = s.cmdRENAME(msg)
 	case "renamenx":
 		res, d, err = s.cmdRENAME(msg)
+	case "setgroup", "delgroup":
+		res, d, err = s.cmdGroupOp(msg, client)
 	case "sethook":
 		res, d, err = s.cmdSetHook(msg)
 	case "delhook":
 		res, d, err = s.cmdDelHook(msg)
 	case "pdelhook":
 		res, d, err = s.cmdPDelHook(msg)
+	case "hookpause", "hookresume":
+		res, d, err = s.cmdHookPause(msg)
 	case "hooks":
 		res, err = s.cmdHooks(msg)
 	case "setchan":
@@ -1482,6 +1643,12 @@ func (s *Server) command(msg *Message, client *Client) (
 		res, d, err = s.cmdPDelHook(msg)
 	case "chans":
 		res, err = s.cmdHooks(msg)
+	case "hookstats":
+		res, err = s.cmdHookStats(msg)
+	case "replay":
+		res, err = s.cmdReplay(msg)
+	case "hookdlq":
+		res, err = s.cmdHookDLQ(msg)
 	case "expire":
 		res, d, err = s.cmdEXPIRE(msg)
 	case "persist":
@@ -1582,6 +1749,12 @@ func (s *Server) command(msg *Message, client *Client) (
 		}
 	case "client":
 		res, err = s.cmdCLIENT(msg, client)
+	case "acl":
+		res, err = s.cmdACL(msg, client)
+	case "ratelimit":
+		res, err = s.cmdRATELIMIT(msg)
+	case "slowlog":
+		res, err = s.cmdSLOWLOG(msg)
 	case "eval", "evalro", "evalna":
 		res, err = s.cmdEvalUnified(false, msg)
 	case "evalsha", "evalrosha", "evalnasha":
@@ -1679,6 +1852,7 @@ const (
 	HTTP
 	WebSocket
 	JSON
+	GRPC
 )
 
 // Message is a resp message
@@ -1686,12 +1860,22 @@ type Message struct {
 	_command       string
 	Args           []string
 	StrictRESP     bool
+	RESP3          bool // client negotiated RESP3 with HELLO
 	ConnType       Type
 	OutputType     Type
 	Auth           string
 	AcceptEncoding string
+	Host           string // HTTP host header
+	EventStream    bool   // HTTP client accepts text/event-stream
+	LastEventID    string // HTTP Last-Event-ID header
 	Deadline       *deadline.Deadline
 	Body           []byte // HTTP request body
+
+	output outputT  // search output, for the RESP3 reply
+	acl    *aclUser // ACL user, nil for the default user
+	admin  string   // admin panel user, for the audit log
+	iters  uint64   // objects iterated by a search, for the slow log
+	items  uint64   // objects returned by a search, for the slow log
 }
 
 // Command returns the first argument as a lowercase string
@@ -1803,6 +1987,7 @@ func readNextHTTPCommand(packet []byte, argsIn [][]byte, msg *Message, wr io.Wri
 		if len(path) == 0 || path[0] != '/' {
 			return false, errInvalidHTTP
 		}
+		rawPath := path[1:]
 		path, err = url.QueryUnescape(path[1:])
 		if err != nil {
 			return false, errInvalidHTTP
@@ -1821,6 +2006,12 @@ func readNextHTTPCommand(packet []byte, argsIn [][]byte, msg *Message, wr io.Wri
 				acceptEncoding = strings.TrimSpace(hdr[i:])
 			} else if i = headerValue(hdr, "Authorization"); i != -1 {
 				msg.Auth = strings.TrimSpace(hdr[i:])
+			} else if i = headerValue(hdr, "Host"); i != -1 {
+				msg.Host = strings.TrimSpace(hdr[i:])
+			} else if i = headerValue(hdr, "Accept"); i != -1 {
+				msg.EventStream = strings.Contains(hdr[i:], "text/event-stream")
+			} else if i = headerValue(hdr, "Last-Event-ID"); i != -1 {
+				msg.LastEventID = strings.TrimSpace(hdr[i:])
 			} else if i = headerValue(hdr, "Upgrade"); i != -1 {
 				val := strings.TrimSpace(hdr[i:])
 				if strings.ToLower(val) == "websocket" {
@@ -1873,6 +2064,12 @@ func readNextHTTPCommand(packet []byte, argsIn [][]byte, msg *Message, wr io.Wri
 		if path == "" {
 			return true, nil
 		}
+		if isOGCPath(rawPath) || isTilePath(rawPath) {
+			// OGC API and tile paths are unescaped by the handler, which
+			// keeps spaces in the query, such as in a filter.
+			msg.Args = []string{rawPath}
+			return true, nil
+		}
 		nmsg, err := readNativeMessageLine([]byte(path))
 		if err != nil {
 			return false, err
@@ -0,0 +1,216 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tidwall/resp"
+)
+
+// The slow log keeps the commands that took longer than
+// slowlog-log-slower-than microseconds, up to slowlog-max-len entries.
+//
+//	SLOWLOG GET [count]
+//	SLOWLOG LEN
+//	SLOWLOG RESET
+//
+// The replies are the same as Redis, plus the number of objects iterated and
+// returned by the search commands.
+
+const (
+	slowLogMaxArgs   = 32  // max number of arguments of an entry
+	slowLogMaxArgLen = 128 // max length of an argument
+)
+
+type slowLogEntry struct {
+	id       uint64
+	time     time.Time
+	duration time.Duration
+	args     []string
+	addr     string
+	name     string
+	iters    uint64 // objects iterated
+	items    uint64 // objects returned
+}
+
+type slowLog struct {
+	mu      sync.Mutex
+	entries []slowLogEntry // oldest first
+	nextID  uint64
+}
+
+// slowLogArgs returns the arguments of an entry, truncated, with the secrets
+// redacted.
+func slowLogArgs(msg *Message) []string {
+	args := msg.Args
+	switch cmd, cargs := auditName(msg); cmd {
+	case "sethook", "setchan", "config set", "acl setuser":
+		n := len(args) - len(cargs)
+		_, _, cargs = auditArgs(msg, cmd, cargs)
+		args = append(args[:n:n], cargs...)
+	}
+	if len(args) > 0 && strings.Contains(args[0], " ") {
+		// CONFIG is rewritten into "config foo"
+		args = append(strings.Fields(args[0]), args[1:]...)
+	}
+	n := len(args)
+	if n > slowLogMaxArgs {
+		n = slowLogMaxArgs - 1
+	}
+	out := make([]string, 0, n+1)
+	for _, arg := range args[:n] {
+		if len(arg) > slowLogMaxArgLen {
+			arg = fmt.Sprintf("%s... (%d more bytes)", arg[:slowLogMaxArgLen],
+				len(arg)-slowLogMaxArgLen)
+		}
+		out = append(out, arg)
+	}
+	if n < len(args) {
+		out = append(out, fmt.Sprintf("... (%d more arguments)", len(args)-n))
+	}
+	return out
+}
+
+// slowLogCmd records a command in the slow log, when it's slower than the
+// threshold. The AUTH and HELLO commands aren't recorded.
+func (s *Server) slowLogCmd(client *Client, msg *Message, took time.Duration) {
+	threshold := s.config.slowLogSlowerThan()
+	if threshold < 0 || took < threshold {
+		return
+	}
+	switch msg.Command() {
+	case "auth", "hello":
+		return
+	}
+	maxLen := s.config.slowLogMaxLen()
+	if maxLen == 0 {
+		return
+	}
+	client.mu.Lock()
+	name := client.name
+	client.mu.Unlock()
+	entry := slowLogEntry{
+		time:     time.Now(),
+		duration: took,
+		args:     slowLogArgs(msg),
+		addr:     client.remoteAddr,
+		name:     name,
+		iters:    msg.iters,
+		items:    msg.items,
+	}
+	sl := &s.slowlog
+	sl.mu.Lock()
+	entry.id = sl.nextID
+	sl.nextID++
+	sl.entries = append(sl.entries, entry)
+	if len(sl.entries) > maxLen {
+		sl.entries = append([]slowLogEntry(nil),
+			sl.entries[len(sl.entries)-maxLen:]...)
+	}
+	sl.mu.Unlock()
+}
+
+// SLOWLOG GET [count] | LEN | RESET
+func (s *Server) cmdSLOWLOG(msg *Message) (resp.Value, error) {
+	start := time.Now()
+	args := msg.Args
+	if len(args) < 2 {
+		return retrerr(errInvalidNumberOfArguments)
+	}
+	sl := &s.slowlog
+	switch strings.ToLower(args[1]) {
+	case "get":
+		if len(args) > 3 {
+			return retrerr(errInvalidNumberOfArguments)
+		}
+		count := 10
+		if len(args) == 3 {
+			n, err := strconv.Atoi(args[2])
+			if err != nil || n < -1 {
+				return retrerr(errInvalidArgument(args[2]))
+			}
+			count = n
+		}
+		// newest first
+		sl.mu.Lock()
+		if count == -1 || count > len(sl.entries) {
+			count = len(sl.entries)
+		}
+		entries := make([]slowLogEntry, count)
+		for i := range entries {
+			entries[i] = sl.entries[len(sl.entries)-1-i]
+		}
+		sl.mu.Unlock()
+		if msg.OutputType == JSON {
+			type jsonEntry struct {
+				ID       uint64   `json:"id"`
+				Time     int64    `json:"time"`
+				Duration int64    `json:"duration"`
+				Args     []string `json:"args"`
+				Addr     string   `json:"addr"`
+				Name     string   `json:"name"`
+				Iters    uint64   `json:"iters"`
+				Items    uint64   `json:"items"`
+			}
+			jentries := make([]jsonEntry, len(entries))
+			for i, e := range entries {
+				jentries[i] = jsonEntry{
+					ID:       e.id,
+					Time:     e.time.Unix(),
+					Duration: e.duration.Microseconds(),
+					Args:     e.args,
+					Addr:     e.addr,
+					Name:     e.name,
+					Iters:    e.iters,
+					Items:    e.items,
+				}
+			}
+			data, _ := json.Marshal(jentries)
+			return resp.StringValue(`{"ok":true,"entries":` + string(data) +
+				`,"elapsed":"` + time.Since(start).String() + `"}`), nil
+		}
+		vals := make([]resp.Value, len(entries))
+		for i, e := range entries {
+			cargs := make([]resp.Value, len(e.args))
+			for j, arg := range e.args {
+				cargs[j] = resp.StringValue(arg)
+			}
+			vals[i] = resp.ArrayValue([]resp.Value{
+				resp.IntegerValue(int(e.id)),
+				resp.IntegerValue(int(e.time.Unix())),
+				resp.IntegerValue(int(e.duration.Microseconds())),
+				resp.ArrayValue(cargs),
+				resp.StringValue(e.addr),
+				resp.StringValue(e.name),
+				resp.IntegerValue(int(e.iters)),
+				resp.IntegerValue(int(e.items)),
+			})
+		}
+		return resp.ArrayValue(vals), nil
+	case "len":
+		if len(args) != 2 {
+			return retrerr(errInvalidNumberOfArguments)
+		}
+		sl.mu.Lock()
+		n := len(sl.entries)
+		sl.mu.Unlock()
+		if msg.OutputType == JSON {
+			return resp.StringValue(fmt.Sprintf(`{"ok":true,"len":%d,`+
+				`"elapsed":"%s"}`, n, time.Since(start))), nil
+		}
+		return resp.IntegerValue(n), nil
+	case "reset":
+		if len(args) != 2 {
+			return retrerr(errInvalidNumberOfArguments)
+		}
+		sl.mu.Lock()
+		sl.entries = nil
+		sl.mu.Unlock()
+		return OKMessage(msg, start), nil
+	}
+	return retrerr(errInvalidArgument(args[1]))
+}
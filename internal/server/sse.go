@@ -0,0 +1,339 @@
+package server
+
+import (
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tidwall/gjson"
+)
+
+// Server-Sent Events transport for live fences and subscriptions.
+//
+// An HTTP request for a live command, with "Accept: text/event-stream",
+// streams the messages as SSE events. Each live command runs as a stream
+// that is shared by all clients of the same command. A stream keeps the
+// latest events in a replay buffer, and is kept for a while after its last
+// client leaves, so a client that reconnects with Last-Event-ID receives the
+// events that it missed.
+
+const (
+	sseHeartbeat  = 15 * time.Second // comment sent to idle clients
+	sseRetention  = 30 * time.Second // stream lifetime without clients
+	sseReplaySize = 1000             // events kept for resuming clients
+)
+
+type sseEvent struct {
+	id   uint64
+	data string
+}
+
+type sseStream struct {
+	key      string
+	mu       sync.Mutex
+	events   []sseEvent
+	clients  map[chan struct{}]bool
+	idle     time.Time // when the last client left
+	err      error
+	ready    chan struct{} // closed once the stream is live
+	done     chan struct{} // closed to stop the stream
+	ended    chan struct{} // closed when the stream stopped
+	doneOnce sync.Once
+	hub      *sseHub
+}
+
+type sseHub struct {
+	mu      sync.Mutex
+	seq     uint64
+	streams map[string]*sseStream
+}
+
+// push adds an event to the stream and notifies the clients.
+func (st *sseStream) push(data string) error {
+	st.hub.mu.Lock()
+	st.hub.seq++
+	id := st.hub.seq
+	st.hub.mu.Unlock()
+	st.mu.Lock()
+	st.events = append(st.events, sseEvent{id, data})
+	if len(st.events) > sseReplaySize {
+		st.events = append(st.events[:0], st.events[1:]...)
+	}
+	for notify := range st.clients {
+		select {
+		case notify <- struct{}{}:
+		default:
+		}
+	}
+	st.mu.Unlock()
+	return nil
+}
+
+// since returns the events after an id.
+func (st *sseStream) since(id uint64) []sseEvent {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	i := len(st.events)
+	for i > 0 && st.events[i-1].id > id {
+		i--
+	}
+	return append([]sseEvent(nil), st.events[i:]...)
+}
+
+// lastID returns the id of the latest event.
+func (st *sseStream) lastID() uint64 {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if len(st.events) == 0 {
+		return 0
+	}
+	return st.events[len(st.events)-1].id
+}
+
+func (st *sseStream) stop() {
+	st.doneOnce.Do(func() { close(st.done) })
+}
+
+// attach adds a client to the stream of a live command, starting the
+// stream when needed.
+func (hub *sseHub) attach(key string, run func(st *sseStream) error) (
+	st *sseStream, notify chan struct{}, created bool,
+) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+	if hub.streams == nil {
+		hub.streams = make(map[string]*sseStream)
+	}
+	st = hub.streams[key]
+	if st == nil {
+		st = &sseStream{
+			key:     key,
+			clients: make(map[chan struct{}]bool),
+			ready:   make(chan struct{}),
+			done:    make(chan struct{}),
+			ended:   make(chan struct{}),
+			hub:     hub,
+		}
+		hub.streams[key] = st
+		created = true
+		go func() {
+			err := run(st)
+			hub.end(st, err)
+		}()
+	}
+	notify = make(chan struct{}, 1)
+	st.mu.Lock()
+	st.clients[notify] = true
+	st.mu.Unlock()
+	return st, notify, created
+}
+
+// detach removes a client from a stream. The stream stops when it has no
+// clients for the retention period.
+func (hub *sseHub) detach(st *sseStream, notify chan struct{}) {
+	st.mu.Lock()
+	delete(st.clients, notify)
+	if len(st.clients) == 0 {
+		st.idle = time.Now()
+	}
+	st.mu.Unlock()
+	time.AfterFunc(sseRetention, func() {
+		hub.mu.Lock()
+		defer hub.mu.Unlock()
+		st.mu.Lock()
+		defer st.mu.Unlock()
+		if len(st.clients) == 0 && time.Since(st.idle) >= sseRetention {
+			if hub.streams[st.key] == st {
+				delete(hub.streams, st.key)
+			}
+			st.stop()
+		}
+	})
+}
+
+// end is called when the stream stopped.
+func (hub *sseHub) end(st *sseStream, err error) {
+	hub.mu.Lock()
+	if hub.streams[st.key] == st {
+		delete(hub.streams, st.key)
+	}
+	hub.mu.Unlock()
+	st.mu.Lock()
+	st.err = err
+	st.mu.Unlock()
+	st.stop()
+	close(st.ended)
+}
+
+func sseStreamKey(msg *Message) string {
+	return msg.Command() + "\x00" + strings.Join(msg.Args[1:], "\x00")
+}
+
+// appendSSEEvent appends an event in the wire format. Each line of the data
+// is a data field.
+func appendSSEEvent(dst []byte, ev sseEvent) []byte {
+	dst = append(dst, "id: "...)
+	dst = strconv.AppendUint(dst, ev.id, 10)
+	dst = append(dst, '\n')
+	for _, line := range strings.Split(ev.data, "\n") {
+		dst = append(dst, "data: "...)
+		dst = append(dst, line...)
+		dst = append(dst, '\n')
+	}
+	return append(dst, '\n')
+}
+
+// goLiveSSE streams a live fence or subscription to an HTTP client.
+func (s *Server) goLiveSSE(inerr error, conn net.Conn, msg *Message) error {
+	defer conn.Close()
+	var run func(st *sseStream) error
+	switch lfs := inerr.(type) {
+	case liveFenceSwitches:
+		run = func(st *sseStream) error {
+			ready := func() error {
+				close(st.ready)
+				return nil
+			}
+			return s.watchLiveFence(lfs, msg, st.done, ready, st.push)
+		}
+	case liveSubscriptionSwitches:
+		run = func(st *sseStream) error {
+			return s.sseSubscription(st, msg)
+		}
+	default:
+		return writeSSEError(conn, "400 Bad Request",
+			"event stream is not supported for "+msg.Command())
+	}
+	st, notify, created := s.sse.attach(sseStreamKey(msg), run)
+	defer s.sse.detach(st, notify)
+	select {
+	case <-st.ready:
+	case <-st.ended:
+		// the stream failed before it went live
+		if st.err == nil {
+			return writeSSEError(conn, "500 Internal Server Error",
+				"event stream ended")
+		}
+		status := "500 Internal Server Error"
+		if st.err == errInvalidNumberOfArguments {
+			status = "400 Bad Request"
+		}
+		if err := writeSSEError(conn, status, st.err.Error()); err != nil {
+			return err
+		}
+		return st.err
+	}
+	var last uint64
+	if id, err := strconv.ParseUint(msg.LastEventID, 10, 64); err == nil {
+		last = id
+	} else if !created {
+		last = st.lastID()
+	}
+	_, err := io.WriteString(conn, "HTTP/1.1 200 OK\r\n"+
+		"Connection: keep-alive\r\n"+
+		"Content-Type: text/event-stream\r\n"+
+		"Cache-Control: no-cache\r\n"+
+		"X-Accel-Buffering: no\r\n"+
+		"Access-Control-Allow-Origin: *\r\n"+
+		"\r\n")
+	if err != nil {
+		return nil
+	}
+	gone := make(chan struct{})
+	go func() {
+		io.Copy(io.Discard, conn)
+		close(gone)
+	}()
+	heartbeat := time.NewTicker(sseHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		if events := st.since(last); len(events) > 0 {
+			var data []byte
+			for _, ev := range events {
+				data = appendSSEEvent(data, ev)
+			}
+			if _, err := conn.Write(data); err != nil {
+				return nil
+			}
+			last = events[len(events)-1].id
+		}
+		select {
+		case <-notify:
+		case <-heartbeat.C:
+			if _, err := io.WriteString(conn, ": ping\n\n"); err != nil {
+				return nil
+			}
+		case <-gone:
+			return nil
+		case <-st.ended:
+			return st.err
+		}
+	}
+}
+
+// writeSSEError writes an HTTP error response, with a json body, to a client
+// that requested an event stream.
+func writeSSEError(conn net.Conn, status, errMsg string) error {
+	body := `{"ok":false,"err":` + jsonString(errMsg) + `}`
+	_, err := io.WriteString(conn, "HTTP/1.1 "+status+"\r\n"+
+		"Connection: close\r\n"+
+		"Content-Type: application/json\r\n"+
+		"Content-Length: "+strconv.Itoa(len(body))+"\r\n"+
+		"Access-Control-Allow-Origin: *\r\n"+
+		"\r\n"+body)
+	return err
+}
+
+// sseSubscription pushes the messages of a SUBSCRIBE or PSUBSCRIBE command
+// to a stream. The messages have the same format as on a WebSocket.
+func (s *Server) sseSubscription(st *sseStream, msg *Message) error {
+	if len(msg.Args) < 2 {
+		return errInvalidNumberOfArguments
+	}
+	kind := pubsubChannel
+	if msg.Command() == "psubscribe" {
+		kind = pubsubPattern
+	}
+	target := newSubtarget()
+	for _, channel := range msg.Args[1:] {
+		s.pubsub.register(kind, channel, target)
+	}
+	defer func() {
+		for _, channel := range msg.Args[1:] {
+			s.pubsub.unregister(kind, channel, target)
+		}
+	}()
+	close(st.ready)
+	go func() {
+		<-st.done
+		target.cond.L.Lock()
+		target.closed = true
+		target.cond.Broadcast()
+		target.cond.L.Unlock()
+	}()
+	target.cond.L.Lock()
+	defer target.cond.L.Unlock()
+	for {
+		for len(target.msgs) > 0 {
+			msgs := target.msgs
+			target.msgs = nil
+			target.cond.L.Unlock()
+			for _, m := range msgs {
+				data := m.message
+				if !gjson.Valid(data) {
+					data = string(appendJSONString(nil, data))
+				}
+				st.push(data)
+			}
+			s.statsTotalMsgsSent.Add(int64(len(msgs)))
+			target.cond.L.Lock()
+		}
+		if target.closed {
+			return nil
+		}
+		target.cond.Wait()
+	}
+}
@@ -0,0 +1,44 @@
+package server
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/tidwall/gjson"
+)
+
+func TestSSEErrorBeforeReady(t *testing.T) {
+	var s Server
+	client, server := net.Pipe()
+	defer client.Close()
+	errc := make(chan error, 1)
+	go func() {
+		// a subscription without channels fails before it goes live
+		errc <- s.goLiveSSE(liveSubscriptionSwitches{}, server,
+			&Message{Args: []string{"subscribe"}})
+	}()
+	resp, err := http.ReadResponse(bufio.NewReader(client), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != 400 || resp.Header.Get("Content-Type") !=
+		"application/json" {
+		t.Fatalf("expected a 400 json response, got %d %s", resp.StatusCode,
+			resp.Header.Get("Content-Type"))
+	}
+	if gjson.GetBytes(body, "ok").Bool() ||
+		gjson.GetBytes(body, "err").String() !=
+			errInvalidNumberOfArguments.Error() {
+		t.Fatalf("unexpected body '%s'", body)
+	}
+	if err := <-errc; err != errInvalidNumberOfArguments {
+		t.Fatalf("expected '%v', got '%v'", errInvalidNumberOfArguments, err)
+	}
+}
@@ -0,0 +1,256 @@
+package server
+
+import (
+	"time"
+
+	"github.com/aiqia-dev/meridian/internal/log"
+	"github.com/aiqia-dev/meridian/internal/object"
+	"github.com/tidwall/btree"
+)
+
+// Stale objects. A hook with the STALE option sends a "stale" event when an
+// object inside its fence isn't updated within the duration, followed by an
+// "exit" event with STALE duration EXIT.
+//
+// Each update of an object inside the fence schedules a timer, which is
+// canceled when the object leaves the fence or is deleted. The followers keep
+// the timers too, so they're current after a promotion, and the timers are
+// rescheduled from the objects once the AOF is loaded.
+
+type staleTimerKey struct {
+	hook, key, id string
+}
+
+type staleTimer struct {
+	staleTimerKey
+	deadline time.Time
+}
+
+func byStaleDeadline(a, b interface{}) bool {
+	ta, tb := a.(*staleTimer), b.(*staleTimer)
+	if !ta.deadline.Equal(tb.deadline) {
+		return ta.deadline.Before(tb.deadline)
+	}
+	if ta.hook != tb.hook {
+		return ta.hook < tb.hook
+	}
+	if ta.key != tb.key {
+		return ta.key < tb.key
+	}
+	return ta.id < tb.id
+}
+
+type staleTimers struct {
+	hooks  map[string]*Hook // hooks with STALE, by name
+	timers map[staleTimerKey]*staleTimer
+	queue  *btree.BTree // timers by deadline
+}
+
+func (st *staleTimers) set(k staleTimerKey, deadline time.Time) {
+	st.del(k)
+	if st.timers == nil {
+		st.timers = make(map[staleTimerKey]*staleTimer)
+		st.queue = btree.NewNonConcurrent(byStaleDeadline)
+	}
+	t := &staleTimer{k, deadline}
+	st.timers[k] = t
+	st.queue.Set(t)
+}
+
+func (st *staleTimers) del(k staleTimerKey) {
+	if t := st.timers[k]; t != nil {
+		delete(st.timers, k)
+		st.queue.Delete(t)
+	}
+}
+
+// delWhere deletes the timers that match.
+func (st *staleTimers) delWhere(match func(k staleTimerKey) bool) {
+	for k := range st.timers {
+		if match(k) {
+			st.del(k)
+		}
+	}
+}
+
+// setHook adds or removes a hook, and deletes the timers of its previous
+// version.
+func (st *staleTimers) setHook(name string, hook *Hook) {
+	if _, ok := st.hooks[name]; ok {
+		delete(st.hooks, name)
+		st.delWhere(func(k staleTimerKey) bool { return k.hook == name })
+	}
+	if hook != nil && hook.stale > 0 {
+		if st.hooks == nil {
+			st.hooks = make(map[string]*Hook)
+		}
+		st.hooks[name] = hook
+	}
+}
+
+// hookInside returns true when an object is inside the fence of a hook.
+func hookInside(hook *Hook, obj *object.Object) bool {
+	if obj == nil || !objIsSpatial(obj.Geo()) ||
+		!multiGlobMatch(hook.Fence.globs, obj.ID()) ||
+		!fenceMatchObject(hook.Fence, obj) {
+		return false
+	}
+	match, _, _ := hook.ScanWriter.testObject(obj)
+	return match
+}
+
+// updateStaleTimers schedules or cancels the timers of the objects that a
+// command changed.
+func (s *Server) updateStaleTimers(d *commandDetails) {
+	st := &s.stale
+	if d.parent {
+		for _, d := range d.children {
+			s.updateStaleTimers(d)
+		}
+		return
+	}
+	if len(st.timers) == 0 && len(st.hooks) == 0 {
+		return
+	}
+	switch d.command {
+	case "flushdb":
+		st.delWhere(func(staleTimerKey) bool { return true })
+		return
+	case "drop", "rename":
+		st.delWhere(func(k staleTimerKey) bool {
+			return k.key == d.key || (d.newKey != "" && k.key == d.newKey)
+		})
+		return
+	case "expire", "persist":
+		// not an update of the object
+		return
+	}
+	if d.obj == nil {
+		return
+	}
+	for name, hook := range st.hooks {
+		if hook.Key != d.key {
+			continue
+		}
+		k := staleTimerKey{name, d.key, d.obj.ID()}
+		if d.command != "del" && hookInside(hook, d.obj) {
+			st.set(k, d.timestamp.Add(hook.stale))
+		} else {
+			st.del(k)
+		}
+	}
+}
+
+// scheduleStaleHook schedules the timers of the objects that are inside the
+// fence of a hook.
+func (s *Server) scheduleStaleHook(hook *Hook, now time.Time) {
+	col, _ := s.cols.Get(hook.Key)
+	if col == nil {
+		return
+	}
+	col.Scan(false, nil, nil, func(obj *object.Object) bool {
+		if hookInside(hook, obj) {
+			s.stale.set(staleTimerKey{hook.Name, hook.Key, obj.ID()},
+				now.Add(hook.stale))
+		}
+		return true
+	})
+}
+
+// rescheduleStaleTimers schedules the timers of all the hooks, after the AOF
+// is loaded. The AOF doesn't keep the time of the last update of an object,
+// so every timer restarts with a full STALE period.
+func (s *Server) rescheduleStaleTimers() {
+	now := time.Now()
+	for _, hook := range s.stale.hooks {
+		s.scheduleStaleHook(hook, now)
+	}
+}
+
+// backgroundStaleObjects sends the events of the timers that are due. Only
+// the leader sends the events, a follower keeps the due timers until it's
+// promoted.
+func (s *Server) backgroundStaleObjects(now time.Time) {
+	st := &s.stale
+	if len(st.timers) == 0 || s.config.followHost() != "" {
+		return
+	}
+	var due []*staleTimer
+	st.queue.Ascend(nil, func(v interface{}) bool {
+		t := v.(*staleTimer)
+		if t.deadline.After(now) {
+			return false
+		}
+		due = append(due, t)
+		return true
+	})
+	if len(due) == 0 {
+		return
+	}
+	var q hookMsgs
+	for _, t := range due {
+		st.del(t.staleTimerKey)
+		hook := st.hooks[t.hook]
+		col, _ := s.cols.Get(t.key)
+		if hook == nil || col == nil {
+			continue
+		}
+		if obj := col.Get(t.id); obj != nil && hook.scheduleActive(now) {
+			q.add(hook, s.staleMsgs(hook, obj, now))
+		}
+	}
+	if err := s.queueHookMsgs(&q); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// staleMsgs returns the stale event of an object, and the exit event with
+// STALE duration EXIT.
+func (s *Server) staleMsgs(hook *Hook, obj *object.Object,
+	now time.Time,
+) []string {
+	detects := []string{"stale"}
+	if hook.staleExit {
+		detects = append(detects, "exit")
+	}
+	return s.hookObjectMsgs(hook, obj, "stale", detects, now)
+}
+
+// hookObjectMsgs returns the events of an object that aren't caused by a
+// command, one for each detect. The object leaves its group with "exit".
+func (s *Server) hookObjectMsgs(hook *Hook, obj *object.Object,
+	command string, detects []string, now time.Time,
+) []string {
+	fence := hook.Fence
+	if len(fence.accept) > 0 && !fence.accept[command] {
+		return nil
+	}
+	sw := hook.ScanWriter
+	sw.fullFields = true
+	sw.msg.OutputType = JSON
+	sw.writeObject(ScanWriterParams{obj: obj, noTest: true})
+	res := sw.wr.String()
+	sw.wr.Reset()
+	if len(res) > 0 && res[0] == ',' {
+		res = res[1:]
+	}
+	if sw.output == outputIDs {
+		res = `{"id":` + res + `}`
+	}
+	if len(res) == 0 || res[0] != '{' {
+		return nil
+	}
+	group := s.groupGet(hook.Name, hook.Key, obj.ID())
+	if group == "" {
+		group = s.groupConnect(hook.Name, hook.Key, obj.ID())
+	}
+	var msgs []string
+	for _, detect := range detects {
+		msgs = append(msgs, makemsg(command, group, detect, hook.Name,
+			hook.Metas, hook.Key, now, res[1:]))
+		if detect == "exit" {
+			s.groupDisconnect(hook.Name, hook.Key, obj.ID())
+		}
+	}
+	return msgs
+}
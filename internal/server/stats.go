@@ -119,6 +119,7 @@ func (s *Server) cmdSTATS(msg *Message) (resp.Value, error) {
 			m["in_memory_size"] = col.TotalWeight()
 			m["num_objects"] = col.Count()
 			m["num_strings"] = col.StringCount()
+			m["num_expired"] = s.colExpired[key]
 			switch msg.OutputType {
 			case JSON:
 				ms = append(ms, m)
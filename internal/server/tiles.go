@@ -0,0 +1,377 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aiqia-dev/meridian/internal/bing"
+	"github.com/aiqia-dev/meridian/internal/object"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/tidwall/geojson/geometry"
+	"github.com/tidwall/tinylru"
+)
+
+// Vector tiles combining multiple collections, one layer per collection.
+//
+//	/tiles/{layers}/{z}/{x}/{y}.mvt?{layer}.where=&{layer}.fields=&{layer}.cluster=
+//
+// The layers are comma separated keys. Each layer may have a WHERE
+// expression, a comma separated list of fields that are added to the
+// features as tags, and a radius in pixels for clustering the points.
+
+const tileMaxObjects = "100000000"
+
+// tileMaxZoom is the max zoom of a tile.
+const tileMaxZoom = 23
+
+// tileCache is an LRU cache of rendered tiles. A tile is removed when a write
+// to one of its layers intersects the tile. The tiles are indexed by layer
+// and z/x/y, so a write only looks at the tiles that it may intersect.
+type tileCache struct {
+	mu    sync.Mutex
+	lru   tinylru.LRUG[string, *cachedTile]
+	index map[string]map[tileXYZ]map[string]bool // layer, tile, cache keys
+}
+
+type tileXYZ struct {
+	x, y, z int
+}
+
+type cachedTile struct {
+	layers []string
+	xyz    tileXYZ
+	rect   geometry.Rect
+	data   []byte
+}
+
+// resize sets the number of cached tiles. Zero disables the cache.
+func (tc *tileCache) resize(size int) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	if size == 0 {
+		tc.lru.Clear()
+		tc.index = nil
+		return
+	}
+	keys, tiles := tc.lru.Resize(size)
+	for i, key := range keys {
+		tc.unindex(key, tiles[i])
+	}
+}
+
+func (tc *tileCache) get(key string) ([]byte, bool) {
+	tile, ok := tc.lru.Get(key)
+	if !ok {
+		return nil, false
+	}
+	return tile.data, true
+}
+
+func (tc *tileCache) set(key string, tile *cachedTile) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	prev, replaced, ekey, etile, evicted := tc.lru.SetEvicted(key, tile)
+	if replaced {
+		tc.unindex(key, prev)
+	}
+	if evicted {
+		tc.unindex(ekey, etile)
+	}
+	if tc.index == nil {
+		tc.index = make(map[string]map[tileXYZ]map[string]bool)
+	}
+	for _, layer := range tile.layers {
+		tiles := tc.index[layer]
+		if tiles == nil {
+			tiles = make(map[tileXYZ]map[string]bool)
+			tc.index[layer] = tiles
+		}
+		keys := tiles[tile.xyz]
+		if keys == nil {
+			keys = make(map[string]bool)
+			tiles[tile.xyz] = keys
+		}
+		keys[key] = true
+	}
+}
+
+// unindex removes a tile that is no longer cached from the index.
+func (tc *tileCache) unindex(key string, tile *cachedTile) {
+	for _, layer := range tile.layers {
+		tiles := tc.index[layer]
+		if keys := tiles[tile.xyz]; keys != nil {
+			delete(keys, key)
+			if len(keys) == 0 {
+				delete(tiles, tile.xyz)
+			}
+		}
+		if len(tiles) == 0 {
+			delete(tc.index, layer)
+		}
+	}
+}
+
+// invalidate removes the tiles that have the layer and intersect any of the
+// rects. With no rects, all tiles of the layer are removed. With an empty
+// layer, all tiles are removed.
+func (tc *tileCache) invalidate(layer string, rects ...geometry.Rect) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	if layer == "" {
+		tc.lru.Clear()
+		tc.index = nil
+		return
+	}
+	tiles := tc.index[layer]
+	if len(tiles) == 0 {
+		return
+	}
+	hits := make(map[string]bool)
+	hit := func(keys map[string]bool, rect geometry.Rect) {
+		for key := range keys {
+			tile, ok := tc.lru.Peek(key)
+			if ok && tile.rect.IntersectsRect(rect) {
+				hits[key] = true
+			}
+		}
+	}
+	if len(rects) == 0 {
+		for _, keys := range tiles {
+			for key := range keys {
+				hits[key] = true
+			}
+		}
+	}
+	for _, rect := range rects {
+		var n int
+		for z := 0; z <= tileMaxZoom && n <= len(tiles); z++ {
+			x0, y0, x1, y1 := tileRange(rect, z)
+			n += (x1 - x0 + 1) * (y1 - y0 + 1)
+		}
+		if n > len(tiles) {
+			// the rect covers more tiles than the cached ones
+			for _, keys := range tiles {
+				hit(keys, rect)
+			}
+			continue
+		}
+		for z := 0; z <= tileMaxZoom; z++ {
+			x0, y0, x1, y1 := tileRange(rect, z)
+			for x := x0; x <= x1; x++ {
+				for y := y0; y <= y1; y++ {
+					hit(tiles[tileXYZ{x, y, z}], rect)
+				}
+			}
+		}
+	}
+	for key := range hits {
+		if tile, ok := tc.lru.Delete(key); ok {
+			tc.unindex(key, tile)
+		}
+	}
+}
+
+// tileRange returns the range of tiles at a zoom that intersect the rect. The
+// range has a margin of one tile for the buffer around a rendered tile.
+func tileRange(rect geometry.Rect, z int) (x0, y0, x1, y1 int) {
+	px0, py0 := bing.LatLongToPixelXY(rect.Max.Y, rect.Min.X, uint64(z))
+	px1, py1 := bing.LatLongToPixelXY(rect.Min.Y, rect.Max.X, uint64(z))
+	tx0, ty0 := bing.PixelXYToTileXY(px0, py0)
+	tx1, ty1 := bing.PixelXYToTileXY(px1, py1)
+	last := int64(1)<<z - 1
+	return int(max(tx0-1, 0)), int(max(ty0-1, 0)),
+		int(min(tx1+1, last)), int(min(ty1+1, last))
+}
+
+// invalidateTiles removes the cached tiles affected by a write.
+func (s *Server) invalidateTiles(d *commandDetails) {
+	if d.parent {
+		for _, d := range d.children {
+			s.invalidateTiles(d)
+		}
+		return
+	}
+	switch d.command {
+	case "flushdb":
+		s.tiles.invalidate("")
+	case "drop", "rename", "renamenx":
+		s.tiles.invalidate(d.key)
+		if d.newKey != "" {
+			s.tiles.invalidate(d.newKey)
+		}
+	default:
+		var rects []geometry.Rect
+		for _, o := range []*object.Object{d.obj, d.old} {
+			if o != nil && o.IsSpatial() {
+				rects = append(rects, o.Rect())
+			}
+		}
+		if len(rects) > 0 {
+			s.tiles.invalidate(d.key, rects...)
+		}
+	}
+}
+
+// isTilePath returns true when the http path is a multi-layer tile.
+func isTilePath(path string) bool {
+	return strings.HasPrefix(path, "tiles/")
+}
+
+// handleTiles serves a multi-layer vector tile.
+func (s *Server) handleTiles(client *Client, msg *Message, query string) error {
+	start := time.Now()
+	status, contentType, cache := "200 OK", "application/vnd.mapbox-vector-tile", "MISS"
+	data, err := func() ([]byte, error) {
+		acl, ok := s.httpAuthorized(msg)
+		if !ok {
+			status = "401 Unauthorized"
+			return nil, fmt.Errorf("authentication required")
+		}
+		status = "400 Bad Request"
+		q, err := url.ParseQuery(query)
+		if err != nil {
+			return nil, fmt.Errorf("invalid query")
+		}
+		parts := strings.Split(msg.Args[0], "/")
+		if len(parts) != 5 || !strings.HasSuffix(parts[4], ".mvt") {
+			status = "404 Not Found"
+			return nil, fmt.Errorf("not found")
+		}
+		parts[4] = parts[4][:len(parts[4])-4]
+		for i := range parts {
+			if parts[i], err = url.PathUnescape(parts[i]); err != nil {
+				return nil, fmt.Errorf("invalid path")
+			}
+		}
+		layers := strings.Split(parts[1], ",")
+		if acl != nil {
+			// the layers are read with SCAN
+			for _, layer := range layers {
+				err := acl.authorize(&Message{Args: []string{"scan", layer}})
+				if err != nil {
+					status = "403 Forbidden"
+					return nil, err
+				}
+			}
+		}
+		z, err := strconv.Atoi(parts[2])
+		if err != nil {
+			return nil, errInvalidArgument(parts[2])
+		}
+		sx, sy, sz := parts[3], parts[4], parts[2]
+		cacheKey := strings.Join(parts[1:], "/") + "?" + q.Encode()
+
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+		if s.config.followHost() != "" && !s.caughtUpOnce() {
+			status = "503 Service Unavailable"
+			return nil, fmt.Errorf("catching up to leader")
+		}
+		if data, ok := s.tiles.get(cacheKey); ok {
+			status, cache = "200 OK", "HIT"
+			return data, nil
+		}
+		var rect geometry.Rect
+		var mlayers []mvtLayer
+		for _, layer := range layers {
+			vs := []string{layer, "LIMIT", tileMaxObjects}
+			if where := q.Get(layer + ".where"); where != "" {
+				vs = append(vs, "WHERE", where)
+			}
+			if radius := q.Get(layer + ".cluster"); radius != "" {
+				vs = append(vs, "CLUSTER", radius, sz)
+			}
+			vs = append(vs, "MVT", sx, sy, sz)
+			objs, trect, err := s.tileLayerObjs(msg, vs)
+			if err != nil {
+				return nil, err
+			}
+			rect = trect
+			ml := mvtLayer{name: layer, objs: objs}
+			ml.opts.tolerance = mvtTolerance(z)
+			if fields := q.Get(layer + ".fields"); fields != "" {
+				ml.opts.fields = strings.Split(fields, ",")
+			}
+			mlayers = append(mlayers, ml)
+		}
+		x, _ := strconv.Atoi(sx)
+		y, _ := strconv.Atoi(sy)
+		data := mvtRenderLayers(x, y, z, mlayers)
+		if s.config.tileCacheSize() > 0 {
+			s.tiles.set(cacheKey, &cachedTile{layers: layers,
+				xyz: tileXYZ{x, y, z}, rect: rect, data: data})
+		}
+		status = "200 OK"
+		return data, nil
+	}()
+	if err != nil {
+		contentType = "application/json"
+		data, _ = json.Marshal(map[string]any{"ok": false, "err": err.Error()})
+	}
+	_, werr := fmt.Fprintf(client, ""+
+		"HTTP/1.1 %s\r\n"+
+		"Connection: close\r\n"+
+		"Content-Type: %s\r\n"+
+		"Content-Length: %d\r\n"+
+		"Access-Control-Allow-Origin: *\r\n"+
+		"X-Cache: %s\r\n"+
+		"\r\n", status, contentType, len(data), cache)
+	if werr != nil {
+		return werr
+	}
+	_, werr = client.Write(data)
+	cmdDurations.With(prometheus.Labels{"cmd": "tiles"}).Observe(
+		time.Since(start).Seconds())
+	return werr
+}
+
+// tileLayerObjs returns the objects of one layer by running the arguments
+// as an INTERSECTS search, along with the rect of the tile.
+func (s *Server) tileLayerObjs(msg *Message, vs []string) (
+	[]mvtObj, geometry.Rect, error,
+) {
+	sargs, err := s.cmdSearchArgs(false, "intersects", vs,
+		withinOrIntersectsTypes)
+	if sargs.usingLua() {
+		defer sargs.Close()
+	}
+	if err != nil {
+		return nil, geometry.Rect{}, err
+	}
+	smsg := &Message{Args: append([]string{"INTERSECTS"}, vs...),
+		ConnType: msg.ConnType, OutputType: JSON}
+	sw, err := s.newScanWriter(
+		&bytes.Buffer{}, smsg, sargs.key, sargs.output, sargs.precision,
+		sargs.globs, false, sargs.cursor, sargs.limit, sargs.wheres,
+		sargs.whereins, sargs.whereevals, sargs.nofields,
+		sargs.mvt, sargs.tileX, sargs.tileY, sargs.tileZ)
+	if err != nil {
+		return nil, geometry.Rect{}, err
+	}
+	if sargs.output == outputCluster {
+		sw.clusters = newClusterer(sargs.cradius, sargs.czoom)
+	}
+	rect := sargs.obj.Rect()
+	if sw.col == nil {
+		return nil, rect, nil
+	}
+	var ierr error
+	sw.col.Intersects(sargs.obj, 0, sw, nil, func(o *object.Object) bool {
+		keepGoing, err := sw.pushObject(ScanWriterParams{obj: o})
+		if err != nil {
+			ierr = err
+			return false
+		}
+		return keepGoing
+	})
+	if ierr != nil {
+		return nil, rect, ierr
+	}
+	return sw.mvtItems(), rect, nil
+}
@@ -0,0 +1,76 @@
+package server
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/aiqia-dev/meridian/internal/bing"
+	"github.com/tidwall/geojson/geometry"
+)
+
+func TestTileCacheInvalidate(t *testing.T) {
+	var tc tileCache
+	tc.resize(1000)
+	set := func(layers []string, x, y, z int) {
+		minLat, minLon, maxLat, maxLon :=
+			bing.TileXYToBounds(int64(x), int64(y), uint64(z))
+		tc.set(fmt.Sprintf("%v/%d/%d/%d", layers, z, x, y), &cachedTile{
+			layers: layers,
+			xyz:    tileXYZ{x, y, z},
+			rect: geometry.Rect{
+				Min: geometry.Point{X: minLon, Y: minLat},
+				Max: geometry.Point{X: maxLon, Y: maxLat},
+			},
+		})
+	}
+	cached := func(layers []string, x, y, z int) bool {
+		_, ok := tc.get(fmt.Sprintf("%v/%d/%d/%d", layers, z, x, y))
+		return ok
+	}
+	fleet := []string{"fleet"}
+	both := []string{"fleet", "zones"}
+	// the tiles of a point at zooms 10 and 2, and their neighbours
+	px, py := bing.PixelXYToTileXY(bing.LatLongToPixelXY(33, -115, 10))
+	x, y := int(px), int(py)
+	set(fleet, x, y, 10)
+	set(fleet, x+1, y, 10)
+	set(both, x, y, 10)
+	set(fleet, x+5, y, 10)
+	set(fleet, x>>8, y>>8, 2)
+	set(both, x+5, y, 10)
+	// enough tiles for a point to look only at its own tiles
+	for i := 0; i < 500; i++ {
+		set(fleet, i, 0, 12)
+	}
+
+	point := geometry.Rect{
+		Min: geometry.Point{X: -115, Y: 33},
+		Max: geometry.Point{X: -115, Y: 33},
+	}
+	tc.invalidate("zones", point)
+	if cached(both, x, y, 10) || !cached(fleet, x, y, 10) ||
+		!cached(both, x+5, y, 10) {
+		t.Fatal("expected only the zones tile of the point to be removed")
+	}
+	tc.invalidate("fleet", point)
+	if cached(fleet, x, y, 10) || cached(fleet, x>>8, y>>8, 2) {
+		t.Fatal("expected the fleet tiles of the point to be removed")
+	}
+	if !cached(fleet, x+1, y, 10) || !cached(fleet, x+5, y, 10) {
+		t.Fatal("expected the other tiles to be kept")
+	}
+
+	// a rect that covers more tiles than the cached ones
+	tc.invalidate("fleet", geometry.Rect{
+		Min: geometry.Point{X: -180, Y: -90},
+		Max: geometry.Point{X: 180, Y: 90},
+	})
+	if cached(fleet, x+1, y, 10) || cached(fleet, x+5, y, 10) ||
+		cached(both, x+5, y, 10) {
+		t.Fatal("expected all the fleet tiles to be removed")
+	}
+	if tc.lru.Len() != 0 || len(tc.index) != 0 {
+		t.Fatalf("expected an empty cache, got %d tiles and %d layers",
+			tc.lru.Len(), len(tc.index))
+	}
+}
@@ -0,0 +1,173 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aiqia-dev/meridian/internal/log"
+	"github.com/tidwall/resp"
+)
+
+// Native TLS for the client port, which serves RESP, HTTP and WebSockets,
+// and for the connections of a follower to its leader.
+//
+// The certificate, key and CA are read from files, and are read again when
+// the server is asked to reload them, on SIGHUP. The connections that are
+// already open keep their certificates.
+
+// tlsCerts holds the certificates of the server.
+type tlsCerts struct {
+	certFile   string
+	keyFile    string
+	caFile     string
+	clientAuth tls.ClientAuthType
+
+	mu   sync.RWMutex
+	cert *tls.Certificate
+	pool *x509.CertPool // nil without a CA
+}
+
+// newTLSCerts returns the certificates of the options, or nil when TLS is not
+// enabled.
+func newTLSCerts(opts Options) (*tlsCerts, error) {
+	if opts.TLSCert == "" && opts.TLSKey == "" {
+		if opts.TLSCA != "" {
+			return nil, errors.New("tls-ca requires tls-cert and tls-key")
+		}
+		return nil, nil
+	}
+	if opts.TLSCert == "" || opts.TLSKey == "" {
+		return nil, errors.New("tls-cert and tls-key must be set together")
+	}
+	tc := &tlsCerts{
+		certFile: opts.TLSCert,
+		keyFile:  opts.TLSKey,
+		caFile:   opts.TLSCA,
+	}
+	switch strings.ToLower(opts.TLSClientAuth) {
+	case "", "no":
+		tc.clientAuth = tls.NoClientCert
+	case "optional":
+		tc.clientAuth = tls.VerifyClientCertIfGiven
+	case "yes":
+		tc.clientAuth = tls.RequireAndVerifyClientCert
+	default:
+		return nil, fmt.Errorf("invalid tls-client-auth value '%s'",
+			opts.TLSClientAuth)
+	}
+	if tc.clientAuth != tls.NoClientCert && tc.caFile == "" {
+		return nil, errors.New("tls-client-auth requires tls-ca")
+	}
+	if err := tc.load(); err != nil {
+		return nil, err
+	}
+	return tc, nil
+}
+
+// load reads the certificate files. On failure the current certificates are
+// kept.
+func (tc *tlsCerts) load() error {
+	cert, err := tls.LoadX509KeyPair(tc.certFile, tc.keyFile)
+	if err != nil {
+		return fmt.Errorf("tls: %w", err)
+	}
+	var pool *x509.CertPool
+	if tc.caFile != "" {
+		data, err := os.ReadFile(tc.caFile)
+		if err != nil {
+			return fmt.Errorf("tls: %w", err)
+		}
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return fmt.Errorf("tls: no certificates in '%s'", tc.caFile)
+		}
+	}
+	tc.mu.Lock()
+	tc.cert = &cert
+	tc.pool = pool
+	tc.mu.Unlock()
+	return nil
+}
+
+// serverConfig returns the config of a listener, with the application
+// protocols negotiated by ALPN. Each handshake uses the latest certificates.
+func (tc *tlsCerts) serverConfig(protos ...string) *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		NextProtos: protos,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			tc.mu.RLock()
+			defer tc.mu.RUnlock()
+			return &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*tc.cert},
+				ClientCAs:    tc.pool,
+				ClientAuth:   tc.clientAuth,
+				NextProtos:   protos,
+			}, nil
+		},
+	}
+}
+
+// clientConfig returns the config of a connection to a leader. The server
+// certificate is the client certificate, and the CA verifies the leader.
+func (tc *tlsCerts) clientConfig(host string) *tls.Config {
+	tc.mu.RLock()
+	defer tc.mu.RUnlock()
+	return &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{*tc.cert},
+		RootCAs:      tc.pool,
+		ServerName:   host,
+	}
+}
+
+// reloadTLS reloads the certificates each time the server is asked to.
+func (s *Server) reloadTLS(reload <-chan bool) {
+	for range reload {
+		if s.tls == nil {
+			continue
+		}
+		if err := s.tls.load(); err != nil {
+			log.Errorf("TLS reload failed: %v", err)
+			continue
+		}
+		log.Infof("TLS certificates reloaded")
+	}
+}
+
+// dialLeader opens a connection to the leader, over TLS when it's enabled.
+func (s *Server) dialLeader(addr string) (*RESPConn, error) {
+	if s.tls == nil {
+		return DialTimeout(addr, time.Second*2)
+	}
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	return DialTLSTimeout(addr, time.Second*2, s.tls.clientConfig(host))
+}
+
+// DialTLSTimeout dials a resp over TLS
+func DialTLSTimeout(address string, timeout time.Duration,
+	config *tls.Config,
+) (*RESPConn, error) {
+	dialer := &net.Dialer{Timeout: timeout}
+	tlsconn, err := tls.DialWithDialer(dialer, "tcp", address, config)
+	if err != nil {
+		return nil, err
+	}
+	conn := &RESPConn{
+		conn: tlsconn,
+		rd:   resp.NewReader(tlsconn),
+		wr:   resp.NewWriter(tlsconn),
+	}
+	return conn, nil
+}
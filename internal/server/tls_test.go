@@ -0,0 +1,174 @@
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// testCert writes a certificate and key signed by the parent, or a self
+// signed CA without a parent.
+func testCert(t *testing.T, dir, name string, serial int64,
+	parent *x509.Certificate, parentKey *ecdsa.PrivateKey,
+) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage: []x509.ExtKeyUsage{
+			x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth,
+		},
+	}
+	if parent == nil {
+		tmpl.IsCA = true
+		tmpl.BasicConstraintsValid = true
+		tmpl.KeyUsage = x509.KeyUsageCertSign
+		parent, parentKey = tmpl, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent,
+		&key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(dir, name+".crt"),
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)
+	os.WriteFile(filepath.Join(dir, name+".key"),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}),
+		0600)
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert, key
+}
+
+// testHandshake runs a handshake, and returns the serial number of the
+// server certificate.
+func testHandshake(ln net.Listener, config *tls.Config) (int64, error) {
+	go func() {
+		conn, err := ln.Accept()
+		if err == nil {
+			conn.(*tls.Conn).Handshake()
+			conn.Close()
+		}
+	}()
+	conn, err := tls.Dial("tcp", ln.Addr().String(), config)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+	// the server verifies the client certificate after the client is done
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	if _, err := conn.Read(make([]byte, 1)); err != nil && err != io.EOF {
+		return 0, err
+	}
+	return conn.ConnectionState().PeerCertificates[0].SerialNumber.Int64(), nil
+}
+
+func TestTLSCerts(t *testing.T) {
+	dir := t.TempDir()
+	ca, caKey := testCert(t, dir, "ca", 1, nil, nil)
+	testCert(t, dir, "server", 2, ca, caKey)
+	path := func(name string) string { return filepath.Join(dir, name) }
+
+	if _, err := newTLSCerts(Options{TLSCert: path("server.crt")}); err == nil {
+		t.Fatal("expected error for missing key")
+	}
+	if _, err := newTLSCerts(Options{TLSCert: path("server.crt"),
+		TLSKey: path("server.key"), TLSClientAuth: "yes"}); err == nil {
+		t.Fatal("expected error for client auth without a CA")
+	}
+	tc, err := newTLSCerts(Options{TLSCert: path("server.crt"),
+		TLSKey: path("server.key"), TLSCA: path("ca.crt"),
+		TLSClientAuth: "yes"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", tc.serverConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	// mutual TLS, as a follower connects to its leader
+	serial, err := testHandshake(ln, tc.clientConfig("127.0.0.1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if serial != 2 {
+		t.Fatalf("expected serial 2, got %d", serial)
+	}
+
+	// a client without a certificate is rejected
+	noCert := tc.clientConfig("127.0.0.1")
+	noCert.Certificates = nil
+	if _, err := testHandshake(ln, noCert); err == nil {
+		t.Fatal("expected error for client without certificate")
+	}
+
+	// reload picks up a new certificate
+	testCert(t, dir, "server", 3, ca, caKey)
+	if err := tc.load(); err != nil {
+		t.Fatal(err)
+	}
+	serial, err = testHandshake(ln, tc.clientConfig("127.0.0.1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if serial != 3 {
+		t.Fatalf("expected serial 3, got %d", serial)
+	}
+
+	// the grpc listener negotiates http/2
+	h2ln, err := tls.Listen("tcp", "127.0.0.1:0", tc.serverConfig("h2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h2ln.Close()
+	go func() {
+		if conn, err := h2ln.Accept(); err == nil {
+			conn.(*tls.Conn).Handshake()
+			conn.Close()
+		}
+	}()
+	h2 := tc.clientConfig("127.0.0.1")
+	h2.NextProtos = []string{"h2"}
+	conn, err := tls.Dial("tcp", h2ln.Addr().String(), h2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if proto := conn.ConnectionState().NegotiatedProtocol; proto != "h2" {
+		t.Fatalf("expected h2, got '%s'", proto)
+	}
+	conn.Close()
+
+	// a failed reload keeps the current certificate
+	os.WriteFile(path("server.key"), []byte("invalid"), 0600)
+	if err := tc.load(); err == nil {
+		t.Fatal("expected error for invalid key")
+	}
+	if _, err := testHandshake(ln, tc.clientConfig("127.0.0.1")); err != nil {
+		t.Fatal(err)
+	}
+}
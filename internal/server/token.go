@@ -21,6 +21,7 @@ var errIDNotFound = errors.New("id not found")
 var errIDAlreadyExists = errors.New("id already exists")
 var errPathNotFound = errors.New("path not found")
 var errKeyHasHooksSet = errors.New("key has hooks set")
+var errHookNotFound = errors.New("hook not found")
 var errNotRectangle = errors.New("not a rectangle")
 
 func errInvalidArgument(arg string) error {
@@ -254,6 +255,8 @@ type searchScanBaseTokens struct {
 	tileX      int
 	tileY      int
 	tileZ      int
+	cradius    float64 // cluster radius, in pixels
+	czoom      int     // cluster zoom
 }
 
 func (s *Server) parseSearchScanBaseTokens(
@@ -654,6 +657,7 @@ func (s *Server) parseSearchScanBaseTokens(
 	t.output = defaultSearchOutput
 	var nvs []string
 	var sprecision string
+	var scradius, sczoom string
 	var which string
 	if nvs, which, ok = tokenval(vs); ok && which != "" {
 		updline := true
@@ -678,6 +682,25 @@ func (s *Server) parseSearchScanBaseTokens(
 			}
 		case "bounds":
 			t.output = outputBounds
+		case "cluster":
+			if cmd != "within" && cmd != "intersects" {
+				err = errors.New("CLUSTER is not allowed for " +
+					strings.ToUpper(cmd))
+				return
+			}
+			if t.fence {
+				err = errors.New("CLUSTER is not allowed when FENCE is specified")
+				return
+			}
+			t.output = outputCluster
+			if nvs, scradius, ok = tokenval(nvs); !ok || scradius == "" {
+				err = errInvalidNumberOfArguments
+				return
+			}
+			if nvs, sczoom, ok = tokenval(nvs); !ok || sczoom == "" {
+				err = errInvalidNumberOfArguments
+				return
+			}
 		case "ids":
 			t.output = outputIDs
 		}
@@ -698,6 +721,18 @@ func (s *Server) parseSearchScanBaseTokens(
 			return
 		}
 	}
+	if scradius != "" {
+		t.cradius, err = strconv.ParseFloat(scradius, 64)
+		if err != nil || !(t.cradius > 0) || math.IsInf(t.cradius, 0) {
+			err = errInvalidArgument(scradius)
+			return
+		}
+		t.czoom, err = strconv.Atoi(sczoom)
+		if err != nil || t.czoom < 0 || t.czoom > clusterMaxZoom {
+			err = errInvalidArgument(sczoom)
+			return
+		}
+	}
 	if slimit != "" {
 		t.ulimit = true
 		if t.limit, err = strconv.ParseUint(slimit, 10, 64); err != nil || t.limit == 0 {
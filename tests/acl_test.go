@@ -0,0 +1,291 @@
+package tests
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/tidwall/gjson"
+)
+
+func subTestACL(g *testGroup) {
+	g.regSubTest("tenant", acl_tenant_test)
+	g.regSubTest("rules", acl_rules_test)
+	g.regSubTest("scripts", acl_scripts_test)
+	g.regSubTest("hooks", acl_hooks_test)
+}
+
+// aclExpect runs a command on a connection, comparing the reply, or the
+// error.
+func aclExpect(conn redis.Conn, expect string, args ...any) error {
+	var res string
+	v, err := conn.Do(args[0].(string), args[1:]...)
+	switch v := v.(type) {
+	case []any:
+		var strs []string
+		for _, v := range v {
+			strs = append(strs, fmt.Sprintf("%s", v))
+		}
+		res = "[" + strings.Join(strs, " ") + "]"
+	case []byte:
+		res = string(v)
+	default:
+		res = fmt.Sprint(v)
+	}
+	if err != nil {
+		res = err.Error()
+	}
+	if res != expect {
+		return fmt.Errorf("%v: expected '%s', got '%s'", args, expect, res)
+	}
+	return nil
+}
+
+func acl_tenant_test(mc *mockServer) error {
+	if err := mc.DoBatch(
+		Do("ACL", "SETUSER", "tenant1", "on", ">secret", "~tenant1:*",
+			"+@read").OK(),
+		Do("SET", "tenant1:fleet", "truck1", "POINT", 33, -115).OK(),
+		Do("SET", "tenant2:fleet", "truck1", "POINT", 34, -116).OK(),
+		Do("ACL", "LIST").Str("[user default on nopass ~* +@all "+
+			"user tenant1 on #2bb80d537b1da3e38bd30361aa855686bde0eacd7162fef6a25fe97bf527a25b "+
+			"~tenant1:* +@read]"),
+		Do("ACL", "LIST").JSON().Func(func(s string) error {
+			if gjson.Get(s, "users.#").Int() != 2 {
+				return fmt.Errorf("expected 2 users, got '%s'", s)
+			}
+			return nil
+		}),
+	); err != nil {
+		return err
+	}
+	conn, err := redis.Dial("tcp", fmt.Sprintf(":%d", mc.port))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	for _, cmd := range [][]any{
+		{"WRONGPASS invalid username-password pair or user is disabled",
+			"AUTH", "tenant1", "wrong"},
+		{"OK", "AUTH", "tenant1", "secret"},
+		{"tenant1", "ACL", "WHOAMI"},
+		{`{"type":"Point","coordinates":[-115,33]}`,
+			"GET", "tenant1:fleet", "truck1"},
+		{"NOPERM this user has no permissions to access one of the keys " +
+			"used as arguments", "GET", "tenant2:fleet", "truck1"},
+		{"NOPERM this user has no permissions to run the 'set' command",
+			"SET", "tenant1:fleet", "truck2", "POINT", "33", "-115"},
+		{"NOPERM this user has no permissions to run the 'acl' command",
+			"ACL", "LIST"},
+		{"[tenant1:fleet]", "KEYS", "*"},
+		{"PONG", "PING"},
+	} {
+		if err := aclExpect(conn, cmd[0].(string), cmd[1:]...); err != nil {
+			return err
+		}
+	}
+
+	// the users are persisted in the config file
+	data, err := os.ReadFile(filepath.Join(mc.dir, "config"))
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(gjson.GetBytes(data, "acl_users.0").String(),
+		"user tenant1 on ") {
+		return fmt.Errorf("expected tenant1 in config, got '%s'", data)
+	}
+
+	// a deleted user can't run commands anymore
+	if err := mc.DoBatch(
+		Do("ACL", "DELUSER", "tenant1", "tenant3").Str("1"),
+		Do("ACL", "DELUSER", "tenant3").JSON().Func(func(s string) error {
+			if gjson.Get(s, "deleted").Int() != 0 {
+				return errors.New("expected no deleted users")
+			}
+			return nil
+		}),
+	); err != nil {
+		return err
+	}
+	return aclExpect(conn, "NOPERM the user is disabled or deleted",
+		"GET", "tenant1:fleet", "truck1")
+}
+
+func acl_rules_test(mc *mockServer) error {
+	if err := mc.DoBatch(
+		Do("ACL", "SETUSER", "default", "off").Err(
+			"the default user is configured with requirepass"),
+		Do("ACL", "SETUSER", "writer", "on", "nopass", "+@all",
+			"-@admin", "-flushdb", "+@scripting", "-@scripting").OK(),
+		Do("ACL", "SETUSER", "writer", "+@nothing").Err(
+			"unknown command category 'nothing'"),
+		Do("ACL", "SETUSER", "writer", "#abc").Err(
+			"invalid password hash '#abc'"),
+		Do("ACL", "SETUSER", "writer", "").Err(
+			"syntax error in ACL rule ''"),
+		Do("ACL", "SETUSER", "writer", "~fleet", "~zones:*").OK(),
+		Do("ACL", "LIST").Func(func(s string) error {
+			if !strings.Contains(s, "user writer on nopass ~fleet ~zones:* "+
+				"+@all -@admin -flushdb +@scripting -@scripting") {
+				return fmt.Errorf("unexpected list '%s'", s)
+			}
+			return nil
+		}),
+		Do("ACL", "WHOAMI").Str("default"),
+		Do("ACL", "FOO").Err("invalid argument 'FOO'"),
+	); err != nil {
+		return err
+	}
+	conn, err := redis.Dial("tcp", fmt.Sprintf(":%d", mc.port))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	for _, cmd := range [][]any{
+		{"OK", "AUTH", "writer", "anything"},
+		{"OK", "SET", "fleet", "truck1", "POINT", "33", "-115"},
+		{"1", "SETHOOK", "hook1", "http://localhost:4892", "NEARBY",
+			"fleet", "FENCE", "POINT", "33", "-115", "1000"},
+		{"NOPERM this user has no permissions to access one of the keys " +
+			"used as arguments", "SETHOOK", "hook2", "http://localhost:4892",
+			"NEARBY", "trucks", "FENCE", "POINT", "33", "-115", "1000"},
+		{"NOPERM this user has no permissions to access one of the keys " +
+			"used as arguments", "WITHIN", "fleet", "GET", "areas", "a1"},
+		{"NOPERM this user has no permissions to access one of the keys " +
+			"used as arguments", "NEARBY", "fleet", "FENCE", "ROAM", "trucks",
+			"*", "1000"},
+		{"NOPERM this user has no permissions to access one of the keys " +
+			"used as arguments", "SETHOOK", "hook3", "http://localhost:4892",
+			"NEARBY", "fleet", "FENCE", "ROAM", "trucks", "*", "1000"},
+		{"NOPERM this user has no permissions to access one of the keys " +
+			"used as arguments", "SETCHAN", "chan1", "NEARBY", "trucks",
+			"FENCE", "POINT", "33", "-115", "1000"},
+		{"NOPERM this user has no permissions to run the 'flushdb' command",
+			"FLUSHDB"},
+		{"NOPERM this user has no permissions to run the 'eval' command",
+			"EVAL", "return 1", "0"},
+		{"NOPERM this user has no permissions to run the 'config' command",
+			"CONFIG", "GET", "requirepass"},
+	} {
+		if err := aclExpect(conn, cmd[0].(string), cmd[1:]...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func acl_scripts_test(mc *mockServer) error {
+	if err := mc.DoBatch(
+		Do("ACL", "SETUSER", "scripter", "on", "nopass", "~tenant1:*",
+			"+@read", "+@scripting").OK(),
+		Do("SET", "tenant2:fleet", "truck1", "POINT", 34, -116).OK(),
+	); err != nil {
+		return err
+	}
+	conn, err := redis.Dial("tcp", fmt.Sprintf(":%d", mc.port))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if err := aclExpect(conn, "OK", "AUTH", "scripter", "anything"); err != nil {
+		return err
+	}
+	for _, cmd := range [][]any{
+		{"<nil>", "EVALRO", "return meridian.call('get', KEYS[1], 'truck1')",
+			"1", "tenant1:fleet"},
+		{"NOPERM this user has no permissions to access one of the keys " +
+			"used as arguments", "EVALRO",
+			"return meridian.pcall('get', KEYS[1], 'truck1')['err']",
+			"1", "tenant2:fleet"},
+		{"NOPERM this user has no permissions to run the 'drop' command",
+			"EVAL", "return meridian.pcall('drop', KEYS[1])['err']",
+			"1", "tenant1:fleet"},
+	} {
+		if err := aclExpect(conn, cmd[0].(string), cmd[1:]...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func acl_hooks_test(mc *mockServer) error {
+	if err := mc.DoBatch(
+		Do("SETCHAN", "tenant2:chan", "NEARBY", "tenant2:fleet", "FENCE",
+			"POINT", 33, -115, 1000).Str("1"),
+		Do("SETHOOK", "tenant2:hook", "http://localhost:4892", "NEARBY",
+			"tenant2:fleet", "FENCE", "POINT", 33, -115, 1000).Str("1"),
+		Do("ACL", "SETUSER", "tenant1", "on", "nopass", "~tenant1:*",
+			"&tenant1:*", "+@hooks").OK(),
+		Do("ACL", "LIST").Func(func(s string) error {
+			if !strings.Contains(s, "user tenant1 on nopass ~tenant1:* "+
+				"&tenant1:* +@hooks") {
+				return fmt.Errorf("unexpected list '%s'", s)
+			}
+			return nil
+		}),
+	); err != nil {
+		return err
+	}
+	conn, err := redis.Dial("tcp", fmt.Sprintf(":%d", mc.port))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	for _, cmd := range [][]any{
+		{"OK", "AUTH", "tenant1", "anything"},
+		{"1", "SETCHAN", "tenant1:chan", "NEARBY", "tenant1:fleet", "FENCE",
+			"POINT", "33", "-115", "1000"},
+		{"NOPERM the hook is owned by another user", "SETCHAN",
+			"tenant2:chan", "NEARBY", "tenant1:fleet", "FENCE", "POINT", "33",
+			"-115", "1000"},
+		{"0", "DELCHAN", "tenant2:chan"},
+		{"0", "HOOKPAUSE", "*"},
+		{"0", "HOOKRESUME", "tenant2:*"},
+		{"0", "PDELCHAN", "tenant2:*"},
+		{"0", "PUBLISH", "tenant1:chan", "hello"},
+		{"NOPERM this user has no permissions to access one of the " +
+			"channels used as arguments", "PUBLISH", "tenant2:chan", "hello"},
+		{"NOPERM this user has no permissions to access one of the " +
+			"channels used as arguments", "PSUBSCRIBE", "*"},
+		{"NOPERM this user has no permissions to access one of the " +
+			"channels used as arguments", "SUBSCRIBE", "tenant2:chan"},
+	} {
+		if err := aclExpect(conn, cmd[0].(string), cmd[1:]...); err != nil {
+			return err
+		}
+	}
+	vals, err := redis.Values(conn.Do("CHANS", "*"))
+	if err != nil {
+		return err
+	}
+	if len(vals) != 1 {
+		return fmt.Errorf("expected 1 channel, got %d", len(vals))
+	}
+
+	// a subscription can't add the channels of another tenant
+	psc := redis.PubSubConn{Conn: conn}
+	if err := psc.Subscribe("tenant1:chan"); err != nil {
+		return err
+	}
+	if v, ok := psc.Receive().(redis.Subscription); !ok || v.Count != 1 {
+		return fmt.Errorf("expected a subscription, got %v", v)
+	}
+	if err := psc.Subscribe("tenant2:chan"); err != nil {
+		return err
+	}
+	if v, ok := psc.Receive().(error); !ok || !strings.HasPrefix(v.Error(),
+		"NOPERM") {
+		return fmt.Errorf("expected a NOPERM error, got %v", v)
+	}
+	return mc.DoBatch(
+		Do("CHANS", "*").JSON().Func(func(s string) error {
+			if gjson.Get(s, "chans.#").Int() != 2 {
+				return fmt.Errorf("expected 2 channels, got '%s'", s)
+			}
+			return nil
+		}),
+	)
+}
@@ -2,6 +2,7 @@ package tests
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -35,6 +36,10 @@ func subTestFence(g *testGroup) {
 
 	// various
 	g.regSubTest("detect eecio", fence_eecio_test)
+	g.regSubTest("expired", fence_expired_test)
+	g.regSubTest("stale", fence_stale_test)
+	g.regSubTest("schedule", fence_schedule_test)
+	g.regSubTest("groups", fence_groups_test)
 }
 
 type fenceReader struct {
@@ -171,6 +176,349 @@ func fence_basic_test(mc *mockServer) error {
 	return nil
 }
 
+func fence_expired_test(mc *mockServer) error {
+	conn, err := net.Dial("tcp", fmt.Sprintf(":%d", mc.port))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	_, err = fmt.Fprintf(conn, "NEARBY mykey FENCE COMMANDS del,expired "+
+		"POINT 33 -115 5000\r\n")
+	if err != nil {
+		return err
+	}
+	buf := make([]byte, 4096)
+	n, err := conn.Read(buf)
+	if err != nil {
+		return err
+	}
+	if res := string(buf[:n]); res != "+OK\r\n" {
+		return fmt.Errorf("expected OK, got '%v'", res)
+	}
+	rd := &fenceReader{conn, bufio.NewReader(conn)}
+
+	if err := mc.DoBatch(
+		Do("SET", "mykey", "myid0", "POINT", 40, -115).OK(),
+		Do("SET", "mykey", "myid1", "FIELD", "speed", 10, "EX", 0.1,
+			"POINT", 33, -115).OK(),
+		Do("SET", "mykey", "myid2", "POINT", 33, -115).OK(),
+		Do("DEL", "mykey", "myid2").Str("1"),
+	); err != nil {
+		return err
+	}
+	// the set isn't accepted, the delete is a del
+	if err := rd.receiveExpect("command", "del", "id", "myid2"); err != nil {
+		return err
+	}
+	// the expiration has the last geometry and fields
+	if err := rd.receiveExpect("command", "expired",
+		"key", "mykey",
+		"id", "myid1",
+		"object.type", "Point",
+		"object.coordinates", "[-115,33]",
+		"fields.speed", "10"); err != nil {
+		return err
+	}
+	return mc.DoBatch(
+		Do("SET", "mykey", "myid3", "POINT", 33, -115).OK(),
+		Do("STATS", "mykey").JSON().Func(func(s string) error {
+			if gjson.Get(s, "stats.0.num_expired").Int() != 1 {
+				return fmt.Errorf("expected 1 expired, got '%s'", s)
+			}
+			return nil
+		}),
+		Do("RENAME", "mykey", "mykey2").OK(),
+		Do("STATS", "mykey2").JSON().Func(func(s string) error {
+			if gjson.Get(s, "stats.0.num_expired").Int() != 1 {
+				return fmt.Errorf("expected 1 expired, got '%s'", s)
+			}
+			return nil
+		}),
+		// the count is removed with the collection
+		Do("DEL", "mykey2", "myid0").Str("1"),
+		Do("DEL", "mykey2", "myid3").Str("1"),
+		Do("SET", "mykey2", "myid4", "POINT", 33, -115).OK(),
+		Do("STATS", "mykey2").JSON().Func(func(s string) error {
+			if gjson.Get(s, "stats.0.num_expired").Int() != 0 {
+				return fmt.Errorf("expected 0 expired, got '%s'", s)
+			}
+			return nil
+		}),
+	)
+}
+
+func fence_stale_test(mc *mockServer) error {
+	if err := mc.DoBatch(
+		Do("SETCHAN", "c1", "STALE", 0, "NEARBY", "fleet", "FENCE",
+			"POINT", 33, -115, 5000).Err("invalid argument '0'"),
+		Do("SETCHAN", "c1", "STALE", 0.3, "EXIT", "NEARBY", "fleet", "FENCE",
+			"COMMANDS", "stale", "POINT", 33, -115, 5000).Str("1"),
+		Do("CHANS", "*").JSON().Func(func(s string) error {
+			if gjson.Get(s, "chans.0.stale").String() != "0.3" {
+				return fmt.Errorf("expected stale, got '%s'", s)
+			}
+			return nil
+		}),
+	); err != nil {
+		return err
+	}
+	conn, err := redis.Dial("tcp", fmt.Sprintf(":%d", mc.port))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	psc := redis.PubSubConn{Conn: conn}
+	if err := psc.Subscribe("c1"); err != nil {
+		return err
+	}
+	if _, ok := psc.Receive().(redis.Subscription); !ok {
+		return errors.New("expected a subscription")
+	}
+	start := time.Now()
+	if err := mc.DoBatch(
+		Do("SET", "fleet", "truck1", "FIELD", "speed", 10, "POINT", 33, -115).OK(),
+		Do("SET", "fleet", "truck2", "POINT", 33, -115).OK(),
+		Do("SET", "fleet", "truck3", "POINT", 40, -115).OK(),
+		Do("SLEEP", 0.2).OK(),
+		// truck2 keeps reporting, and truck3 is outside
+		Do("SET", "fleet", "truck2", "POINT", 33.001, -115).OK(),
+	); err != nil {
+		return err
+	}
+	var msgs []string
+	for len(msgs) < 2 {
+		switch v := psc.ReceiveWithTimeout(time.Second * 5).(type) {
+		case redis.Message:
+			msgs = append(msgs, string(v.Data))
+		case error:
+			return v
+		}
+	}
+	if time.Since(start) < time.Millisecond*300 {
+		return errors.New("expected the events after the stale duration")
+	}
+	for i, detect := range []string{"stale", "exit"} {
+		for _, kv := range [][2]string{{"command", "stale"},
+			{"detect", detect}, {"id", "truck1"}, {"fields.speed", "10"},
+			{"object.coordinates", "[-115,33]"}} {
+			if v := gjson.Get(msgs[i], kv[0]).String(); v != kv[1] {
+				return fmt.Errorf("expected '%s'='%s', got '%s'", kv[0], kv[1],
+					msgs[i])
+			}
+		}
+	}
+	// the next event is truck2, which stopped reporting later
+	switch v := psc.ReceiveWithTimeout(time.Second * 5).(type) {
+	case redis.Message:
+		if gjson.Get(string(v.Data), "id").String() != "truck2" {
+			return fmt.Errorf("expected truck2, got '%s'", v.Data)
+		}
+	case error:
+		return v
+	}
+	return nil
+}
+
+func fence_schedule_test(mc *mockServer) error {
+	tod := func(d time.Duration) string {
+		return time.Now().UTC().Add(d).Format("15:04:05")
+	}
+	if err := mc.DoBatch(
+		Do("SETCHAN", "c1", "ACTIVE", "07:00", "07:00", "NEARBY", "fleet",
+			"FENCE", "POINT", 33, -115, 5000).Err("invalid argument '07:00'"),
+		Do("SETCHAN", "c1", "SCHEDULE", "0 7 * * mon-fri", "NEARBY", "fleet",
+			"FENCE", "POINT", 33, -115, 5000).Err("missing DURATION argument"),
+		// closed until later
+		Do("SETCHAN", "c1", "ACTIVE", tod(time.Hour), tod(time.Hour*2),
+			"NEARBY", "fleet", "FENCE", "POINT", 33, -115, 5000).Str("1"),
+		// closes in two seconds
+		Do("SETCHAN", "c2", "ACTIVE", tod(-time.Hour), tod(time.Second*2),
+			"TZ", "UTC", "EXIT", "NEARBY", "fleet", "FENCE", "DETECT", "enter,exit",
+			"POINT", 33, -115, 5000).Str("1"),
+		Do("SETCHAN", "c3", "SCHEDULE", "0 7 * * mon-fri", "DURATION", 3600,
+			"TZ", "America/Sao_Paulo", "NEARBY", "fleet", "FENCE",
+			"POINT", 33, -115, 5000).Str("1"),
+		Do("CHANS", "*").JSON().Func(func(s string) error {
+			for i, active := range []bool{false, true} {
+				if gjson.Get(s, fmt.Sprintf("chans.%d.active", i)).Bool() != active {
+					return fmt.Errorf("unexpected chans '%s'", s)
+				}
+			}
+			sched := gjson.Get(s, "chans.2.schedule").String()
+			expect := `{"cron":"0 7 * * mon-fri","duration":3600,"tz":"America/Sao_Paulo","exit":false}`
+			if sched != expect {
+				return fmt.Errorf("expected '%s', got '%s'", expect, sched)
+			}
+			return nil
+		}),
+		Do("DELCHAN", "c3").Str("1"),
+	); err != nil {
+		return err
+	}
+	conn, err := redis.Dial("tcp", fmt.Sprintf(":%d", mc.port))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	psc := redis.PubSubConn{Conn: conn}
+	if err := psc.Subscribe("c1", "c2"); err != nil {
+		return err
+	}
+	for i := 0; i < 2; i++ {
+		if _, ok := psc.Receive().(redis.Subscription); !ok {
+			return errors.New("expected a subscription")
+		}
+	}
+	if err := mc.DoBatch(
+		Do("SET", "fleet", "truck1", "POINT", 33, -115).OK(),
+		Do("SET", "fleet", "truck2", "POINT", 40, -115).OK(),
+	); err != nil {
+		return err
+	}
+	for _, expect := range []string{"set enter", "schedule exit"} {
+		var msg string
+		switch v := psc.ReceiveWithTimeout(time.Second * 5).(type) {
+		case redis.Message:
+			msg = string(v.Data)
+		case error:
+			return v
+		}
+		res := gjson.GetMany(msg, "hook", "command", "detect", "id")
+		if res[0].String() != "c2" || res[3].String() != "truck1" ||
+			res[1].String()+" "+res[2].String() != expect {
+			return fmt.Errorf("expected '%s', got '%s'", expect, msg)
+		}
+	}
+	return mc.DoBatch(
+		Do("CHANS", "c2").JSON().Func(func(s string) error {
+			if gjson.Get(s, "chans.0.active").Bool() {
+				return fmt.Errorf("expected inactive, got '%s'", s)
+			}
+			return nil
+		}),
+	)
+}
+
+func fence_groups_test(mc *mockServer) error {
+	// subscribe returns the next message of a channel, with a function
+	subscribe := func(mc *mockServer, channel string) (func() (string, error),
+		func(), error) {
+		conn, err := redis.Dial("tcp", fmt.Sprintf(":%d", mc.port))
+		if err != nil {
+			return nil, nil, err
+		}
+		psc := redis.PubSubConn{Conn: conn}
+		if err := psc.Subscribe(channel); err != nil {
+			conn.Close()
+			return nil, nil, err
+		}
+		next := func() (string, error) {
+			for {
+				switch v := psc.ReceiveWithTimeout(time.Second * 5).(type) {
+				case redis.Message:
+					return string(v.Data), nil
+				case error:
+					return "", v
+				}
+			}
+		}
+		return next, func() { conn.Close() }, nil
+	}
+	follower, err := mockOpenServer(MockServerOptions{Silent: true})
+	if err != nil {
+		return err
+	}
+	defer follower.Close()
+	if err := mc.DoBatch(
+		Do("SETCHAN", "c1", "NEARBY", "fleet", "FENCE", "DETECT", "enter,exit",
+			"POINT", 33, -115, 5000).Str("1"),
+		Do("SETGROUP", "c1", "fleet", "truck1", "1234").Err(
+			"unknown command 'SETGROUP'"),
+	); err != nil {
+		return err
+	}
+	if err := follower.DoBatch(
+		Do("FOLLOW", "localhost", mc.port).OK(),
+		Sleep(time.Second/2),
+	); err != nil {
+		return err
+	}
+	next, closeSub, err := subscribe(mc, "c1")
+	if err != nil {
+		return err
+	}
+	defer closeSub()
+	if err := mc.DoBatch(
+		Do("SET", "fleet", "truck1", "POINT", 33, -115).OK(),
+	); err != nil {
+		return err
+	}
+	msg, err := next()
+	if err != nil {
+		return err
+	}
+	group := gjson.Get(msg, "group").String()
+	if gjson.Get(msg, "detect").String() != "enter" || group == "" {
+		return fmt.Errorf("expected enter, got '%s'", msg)
+	}
+
+	// exit returns an error when the exit of truck1 isn't in the group
+	exit := func(mc *mockServer) error {
+		next, closeSub, err := subscribe(mc, "c1")
+		if err != nil {
+			return err
+		}
+		defer closeSub()
+		if err := mc.DoBatch(
+			Do("SET", "fleet", "truck1", "POINT", 40, -115).OK(),
+		); err != nil {
+			return err
+		}
+		msg, err := next()
+		if err != nil {
+			return err
+		}
+		if gjson.Get(msg, "detect").String() != "exit" ||
+			gjson.Get(msg, "group").String() != group {
+			return fmt.Errorf("expected exit in group '%s', got '%s'", group,
+				msg)
+		}
+		return nil
+	}
+
+	// the promoted follower
+	if err := follower.DoBatch(
+		Sleep(time.Second/2),
+		Do("FOLLOW", "no", "one").OK(),
+	); err != nil {
+		return err
+	}
+	if err := exit(follower); err != nil {
+		return err
+	}
+
+	// a restart, after a shrink
+	if err := mc.DoBatch(
+		Do("AOFSHRINK").OK(),
+		Sleep(time.Second/2),
+	); err != nil {
+		return err
+	}
+	aof, err := mc.readAOF()
+	if err != nil {
+		return err
+	}
+	if !bytes.Contains(aof, []byte(group)) {
+		return errors.New("expected the group in the aof")
+	}
+	mc2, err := loadAOF(aof)
+	if err != nil {
+		return err
+	}
+	defer mc2.Close()
+	return exit(mc2)
+}
+
 func fence_channel_message_order_test(mc *mockServer) error {
 	// Create a channel to store the goroutines error
 	finalErr := make(chan error)
@@ -1,9 +1,17 @@
 package tests
 
-import "time"
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/tidwall/gjson"
+)
 
 func subTestFollower(g *testGroup) {
 	g.regSubTest("follow", follower_follow_test)
+	g.regSubTest("stale", follower_stale_test)
 }
 
 func follower_follow_test(mc *mockServer) error {
@@ -66,3 +74,53 @@ func follower_follow_test(mc *mockServer) error {
 
 	return nil
 }
+
+func follower_stale_test(mc *mockServer) error {
+	mc2, err := mockOpenServer(MockServerOptions{
+		Silent: true, Metrics: false,
+	})
+	if err != nil {
+		return err
+	}
+	defer mc2.Close()
+	if err := mc.DoBatch(
+		Do("SETCHAN", "c1", "STALE", 0.3, "NEARBY", "fleet", "FENCE",
+			"POINT", 33, -115, 5000).Str("1"),
+		Do("SET", "fleet", "truck1", "POINT", 33, -115).OK(),
+	); err != nil {
+		return err
+	}
+	if err := mc2.DoBatch(
+		Do("FOLLOW", "localhost", mc.port).OK(),
+		Sleep(time.Second),
+	); err != nil {
+		return err
+	}
+	conn, err := redis.Dial("tcp", fmt.Sprintf(":%d", mc2.port))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	psc := redis.PubSubConn{Conn: conn}
+	if err := psc.Subscribe("c1"); err != nil {
+		return err
+	}
+	if _, ok := psc.Receive().(redis.Subscription); !ok {
+		return errors.New("expected a subscription")
+	}
+	// the follower kept the due timer, and sends it once promoted
+	if err := mc2.DoBatch(
+		Do("FOLLOW", "no", "one").OK(),
+	); err != nil {
+		return err
+	}
+	switch v := psc.ReceiveWithTimeout(time.Second * 5).(type) {
+	case redis.Message:
+		if gjson.Get(string(v.Data), "id").String() != "truck1" {
+			return fmt.Errorf("expected truck1, got '%s'", v.Data)
+		}
+	case error:
+		return v
+	}
+	return nil
+}
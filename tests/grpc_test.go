@@ -0,0 +1,320 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	cservicev1 "github.com/aiqia-dev/meridian/internal/cservice/v1"
+	hservicev1 "github.com/aiqia-dev/meridian/internal/hservice/v1"
+	"github.com/tidwall/gjson"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func subTestGRPC(g *testGroup) {
+	g.regSubTest("crud", grpc_crud_test)
+	g.regSubTest("search", grpc_search_test)
+	g.regSubTest("execute", grpc_execute_test)
+	g.regSubTest("fence", grpc_fence_test)
+}
+
+func grpcDial(mc *mockServer) (cservicev1.CommandServiceClient, func(), error) {
+	conn, err := grpc.NewClient(fmt.Sprintf("127.0.0.1:%d", mc.grpcPort()),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, nil, err
+	}
+	return cservicev1.NewCommandServiceClient(conn), func() { conn.Close() }, nil
+}
+
+func grpcPoint(lat, lon float64) *cservicev1.SetRequest_Point {
+	return &cservicev1.SetRequest_Point{
+		Point: &cservicev1.Point{Lat: lat, Lon: lon},
+	}
+}
+
+func grpcNearby(lat, lon, meters float64) *cservicev1.NearbyRequest_Point {
+	return &cservicev1.NearbyRequest_Point{
+		Point: &cservicev1.NearbyPoint{Lat: lat, Lon: lon, Meters: meters},
+	}
+}
+
+func grpc_crud_test(mc *mockServer) error {
+	client, done, err := grpcDial(mc)
+	if err != nil {
+		return err
+	}
+	defer done()
+	ctx := context.Background()
+	_, err = client.Set(ctx, &cservicev1.SetRequest{
+		Key: "fleet", Id: "truck1", Value: grpcPoint(33, -115),
+		Field: []*cservicev1.Field{{Name: "speed", Value: 90}},
+	})
+	if err != nil {
+		return err
+	}
+	res, err := client.Get(ctx, &cservicev1.GetRequest{
+		Key: "fleet", Id: "truck1", WithFields: true,
+	})
+	if err != nil {
+		return err
+	}
+	p := res.Object.GetGeometry().GetPoint()
+	if p.GetLat() != 33 || p.GetLon() != -115 {
+		return fmt.Errorf("expected point 33,-115, got %v", p)
+	}
+	if res.Object.Fields["speed"] != "90" {
+		return fmt.Errorf("expected speed 90, got %v", res.Object.Fields)
+	}
+	// the object is also visible to the other transports
+	if err := mc.DoBatch(
+		Do("GET", "fleet", "truck1", "POINT").Str("[33 -115]"),
+	); err != nil {
+		return err
+	}
+	_, err = client.Set(ctx, &cservicev1.SetRequest{
+		Key: "fleet", Id: "truck1", Value: grpcPoint(34, -115),
+		Type: cservicev1.SetRequest_TYPE_NX,
+	})
+	if status.Code(err) != codes.AlreadyExists {
+		return fmt.Errorf("expected already exists, got %v", err)
+	}
+	if err := mc.DoBatch(
+		Do("GET", "fleet", "truck1", "POINT").Str("[33 -115]"),
+	); err != nil {
+		return err
+	}
+	_, err = client.Del(ctx, &cservicev1.DelRequest{Key: "fleet", Id: "truck1"})
+	if err != nil {
+		return err
+	}
+	_, err = client.Get(ctx, &cservicev1.GetRequest{Key: "fleet", Id: "truck1"})
+	if status.Code(err) != codes.NotFound {
+		return fmt.Errorf("expected not found, got %v", err)
+	}
+	_, err = client.SetHook(ctx, &cservicev1.SetHookRequest{
+		Name: "warehouse", Endpoint: "http://localhost:3030/hook",
+		Meta:  []*cservicev1.Meta{{Name: "team", Value: "ops"}},
+		Retry: proto.Int64(3),
+		Fence: &cservicev1.FenceRequest{
+			Search: &cservicev1.FenceRequest_Nearby{
+				Nearby: &cservicev1.NearbyRequest{
+					Key: "fleet", Detect: proto.String("enter"),
+					Area: grpcNearby(33, -115, 100),
+				},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return mc.DoBatch(
+		Do("HOOKS", "*").JSON().Func(func(s string) error {
+			hook := gjson.Get(s, "hooks.0")
+			if hook.Get("name").String() != "warehouse" ||
+				hook.Get("meta.team").String() != "ops" ||
+				hook.Get("command").String() !=
+					`["NEARBY","fleet","FENCE","DETECT","enter","POINT","33","-115","100"]` {
+				return fmt.Errorf("unexpected hook %s", hook.Raw)
+			}
+			return nil
+		}),
+	)
+}
+
+func grpc_search_test(mc *mockServer) error {
+	client, done, err := grpcDial(mc)
+	if err != nil {
+		return err
+	}
+	defer done()
+	ctx := context.Background()
+	for i, lat := range []float64{33, 33.001, 34} {
+		_, err := client.Set(ctx, &cservicev1.SetRequest{
+			Key: "fleet", Id: fmt.Sprintf("truck%d", i+1),
+			Value: grpcPoint(lat, -115),
+			Field: []*cservicev1.Field{{Name: "speed", Value: float64(i * 10)}},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	res, err := client.Nearby(ctx, &cservicev1.NearbyRequest{
+		Key: "fleet", Distance: true, Area: grpcNearby(33, -115, 1000),
+	})
+	if err != nil {
+		return err
+	}
+	if res.Count != 2 || len(res.Objects) != 2 {
+		return fmt.Errorf("expected 2 objects, got %d", len(res.Objects))
+	}
+	if res.Objects[0].Id != "truck1" || res.Objects[1].Id != "truck2" {
+		return fmt.Errorf("unexpected order: %s, %s",
+			res.Objects[0].Id, res.Objects[1].Id)
+	}
+	if res.Objects[1].Fields["speed"] != "10" {
+		return fmt.Errorf("expected speed 10, got %v", res.Objects[1].Fields)
+	}
+	if res.Objects[1].Distance < 100 || res.Objects[1].Distance > 120 {
+		return fmt.Errorf("unexpected distance %f", res.Objects[1].Distance)
+	}
+	res, err = client.Within(ctx, &cservicev1.WithinRequest{
+		Key: "fleet",
+		Area: &cservicev1.WithinRequest_Bounds{
+			Bounds: &cservicev1.Bounds{
+				MinLat: 33.5, MinLon: -116, MaxLat: 34.5, MaxLon: -114,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if len(res.Objects) != 1 || res.Objects[0].Id != "truck3" {
+		return fmt.Errorf("expected truck3, got %v", res.Objects)
+	}
+	res, err = client.Scan(ctx, &cservicev1.ScanRequest{
+		Key: "fleet", Limit: proto.Int64(2),
+	})
+	if err != nil {
+		return err
+	}
+	if len(res.Objects) != 2 || res.Cursor != 2 {
+		return fmt.Errorf("expected 2 objects and cursor 2, got %d and %d",
+			len(res.Objects), res.Cursor)
+	}
+	return nil
+}
+
+func grpc_execute_test(mc *mockServer) error {
+	client, done, err := grpcDial(mc)
+	if err != nil {
+		return err
+	}
+	defer done()
+	ctx := context.Background()
+	_, err = client.Execute(ctx, &cservicev1.ExecuteRequest{
+		Args: []string{"SET", "fleet", "truck1", "POINT", "33", "-115"},
+	})
+	if err != nil {
+		return err
+	}
+	res, err := client.Execute(ctx, &cservicev1.ExecuteRequest{
+		Args: []string{"STATS", "fleet"},
+	})
+	if err != nil {
+		return err
+	}
+	if n := gjson.Get(res.Json, "stats.0.num_objects").Int(); n != 1 {
+		return fmt.Errorf("expected 1 object, got %s", res.Json)
+	}
+	_, err = client.Execute(ctx, &cservicev1.ExecuteRequest{
+		Args: []string{"GET", "fleet"},
+	})
+	if status.Code(err) != codes.InvalidArgument {
+		return fmt.Errorf("expected invalid argument, got %v", err)
+	}
+	_, err = client.Execute(ctx, &cservicev1.ExecuteRequest{
+		Args: []string{"NEARBY", "fleet", "FENCE", "POINT", "33", "-115", "100"},
+	})
+	if status.Code(err) != codes.InvalidArgument {
+		return fmt.Errorf("expected invalid argument, got %v", err)
+	}
+	return nil
+}
+
+func grpc_fence_test(mc *mockServer) error {
+	client, done, err := grpcDial(mc)
+	if err != nil {
+		return err
+	}
+	defer done()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := client.Fence(ctx, &cservicev1.FenceRequest{
+		Search: &cservicev1.FenceRequest_Nearby{
+			Nearby: &cservicev1.NearbyRequest{
+				Key: "fleet", Detect: proto.String("enter,exit"),
+				Area: grpcNearby(33, -115, 100),
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	events := make(chan *hservicev1.GeofenceEvent, 2)
+	errc := make(chan error, 1)
+	go func() {
+		for {
+			ev, err := stream.Recv()
+			if err != nil {
+				errc <- err
+				return
+			}
+			events <- ev
+		}
+	}()
+	recv := func() (*hservicev1.GeofenceEvent, error) {
+		select {
+		case ev := <-events:
+			return ev, nil
+		case err := <-errc:
+			return nil, err
+		case <-time.After(time.Second * 5):
+			return nil, errors.New("timeout waiting for fence event")
+		}
+	}
+	// the fence is registered once the stream is open, which is not known to
+	// the client, so keep moving the object until the first event arrives.
+	for i := 0; ; i++ {
+		if err := mc.DoBatch(
+			Do("SET", "fleet", "truck1", "POINT", 34, -115).OK(),
+			Do("SET", "fleet", "truck1", "POINT", 33, -115).OK(),
+		); err != nil {
+			return err
+		}
+		select {
+		case ev := <-events:
+			if ev.Detect != "enter" {
+				// exit from a previous round, the enter follows
+				if ev, err = recv(); err != nil {
+					return err
+				}
+			}
+			if ev.Detect != "enter" || ev.Id != "truck1" {
+				return fmt.Errorf("expected enter for truck1, got %s for %s",
+					ev.Detect, ev.Id)
+			}
+		case err := <-errc:
+			return err
+		case <-time.After(time.Second / 10):
+			if i == 50 {
+				return errors.New("timeout waiting for fence event")
+			}
+			continue
+		}
+		break
+	}
+	if err := mc.DoBatch(
+		Do("SET", "fleet", "truck1", "POINT", 34, -115).OK(),
+	); err != nil {
+		return err
+	}
+	ev, err := recv()
+	if err != nil {
+		return err
+	}
+	if ev.Detect != "exit" || ev.Key != "fleet" {
+		return fmt.Errorf("expected exit on fleet, got %s on %s",
+			ev.Detect, ev.Key)
+	}
+	p := ev.GetGeometry().GetPoint()
+	if p.GetLat() != 34 || p.GetLon() != -115 {
+		return fmt.Errorf("expected point 34,-115, got %v", p)
+	}
+	return nil
+}
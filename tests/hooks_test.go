@@ -0,0 +1,773 @@
+package tests
+
+import (
+	"bufio"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	hservicev1 "github.com/aiqia-dev/meridian/internal/hservice/v1"
+	"github.com/gomodule/redigo/redis"
+	"github.com/gorilla/websocket"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/redcon"
+	"google.golang.org/grpc"
+)
+
+func subTestHooks(g *testGroup) {
+	g.regSubTest("SETHOOK RETRY", hooks_SETHOOK_RETRY_test)
+	g.regSubTest("HOOKSTATS", hooks_HOOKSTATS_test)
+	g.regSubTest("HOOKDLQ", hooks_HOOKDLQ_test)
+	g.regSubTest("HOOKPAUSE", hooks_HOOKPAUSE_test)
+	g.regSubTest("HOOKS RESP", hooks_HOOKS_RESP_test)
+	g.regSubTest("BATCH", hooks_BATCH_test)
+	g.regSubTest("SECRET", hooks_SECRET_test)
+	g.regSubTest("REPLAY", hooks_REPLAY_test)
+	g.regSubTest("WebSocket", hooks_WebSocket_test)
+	g.regSubTest("Redis XADD", hooks_RedisXADD_test)
+	g.regSubTest("Redis XADD BATCH", hooks_RedisXADD_BATCH_test)
+	g.regSubTest("file", hooks_file_test)
+	g.regSubTest("unix", hooks_unix_test)
+	g.regSubTest("gRPC", hooks_gRPC_test)
+}
+
+func hooks_SETHOOK_RETRY_test(mc *mockServer) error {
+	return mc.DoBatch(
+		Do("SETHOOK", "hook1", "http://127.0.0.1:1/hook", "RETRY", "-1", "NEARBY", "mykey", "FENCE", "POINT", 33, -115, 100).Err("invalid argument '-1'"),
+		Do("SETHOOK", "hook1", "http://127.0.0.1:1/hook", "BACKOFF", "2", "1", "NEARBY", "mykey", "FENCE", "POINT", 33, -115, 100).Err("invalid argument '1'"),
+		Do("SETHOOK", "hook1", "http://127.0.0.1:1/hook", "MSGTTL", "0", "NEARBY", "mykey", "FENCE", "POINT", 33, -115, 100).Err("invalid argument '0'"),
+		Do("SETHOOK", "hook1", "http://127.0.0.1:1/hook", "DLQ", "0", "60", "NEARBY", "mykey", "FENCE", "POINT", 33, -115, 100).Err("invalid argument '0'"),
+		Do("SETHOOK", "hook1", "http://127.0.0.1:1/hook", "DLQ", "10", "0", "NEARBY", "mykey", "FENCE", "POINT", 33, -115, 100).Err("invalid argument '0'"),
+		Do("SETHOOK", "hook1", "http://127.0.0.1:1/hook", "RETRY", "3", "BACKOFF", "0.1", "1", "MSGTTL", "60", "NEARBY", "mykey", "FENCE", "POINT", 33, -115, 100).Str("1"),
+		Do("SETHOOK", "hook1", "http://127.0.0.1:1/hook", "RETRY", "3", "BACKOFF", "0.1", "1", "MSGTTL", "60", "NEARBY", "mykey", "FENCE", "POINT", 33, -115, 100).Str("0"),
+		Do("SETHOOK", "hook1", "http://127.0.0.1:1/hook", "RETRY", "4", "BACKOFF", "0.1", "1", "MSGTTL", "60", "NEARBY", "mykey", "FENCE", "POINT", 33, -115, 100).Str("1"),
+		Do("HOOKDLQ", "hook2").Err("hook not found"),
+		Do("HOOKDLQ", "hook1", "FOO").Err("invalid argument 'FOO'"),
+		Do("HOOKDLQ", "hook1").Str("[]"),
+	)
+}
+
+func hooks_HOOKSTATS_test(mc *mockServer) error {
+	var received atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			io.Copy(io.Discard, r.Body)
+			received.Add(1)
+		},
+	))
+	defer ts.Close()
+	return mc.DoBatch(
+		Do("SETHOOK", "hook1", ts.URL, "NEARBY", "mykey", "FENCE", "DETECT", "enter", "POINT", 33, -115, 100).Str("1"),
+		Do("HOOKSTATS").JSON().Func(func(s string) error {
+			ep := gjson.Get(s, "hooks.0.endpoints.0")
+			if ep.Get("endpoint").String() != ts.URL || ep.Get("sent").Int() != 0 {
+				return fmt.Errorf("unexpected stats: %s", s)
+			}
+			return nil
+		}),
+		Do("SET", "mykey", "myid", "POINT", 33, -115).OK(),
+		Sleep(time.Second/2),
+		Do("HOOKSTATS", "hook*").JSON().Func(func(s string) error {
+			h := gjson.Get(s, "hooks.0")
+			if h.Get("pending").Int() != 0 || h.Get("dead").Int() != 0 ||
+				h.Get("endpoints.0.sent").Int() != 1 ||
+				h.Get("endpoints.0.failed").Int() != 0 || received.Load() != 1 {
+				return fmt.Errorf("unexpected stats: %s", s)
+			}
+			return nil
+		}),
+		Do("HOOKSTATS", "nohook*").JSON().Func(func(s string) error {
+			if len(gjson.Get(s, "hooks").Array()) != 0 {
+				return fmt.Errorf("unexpected stats: %s", s)
+			}
+			return nil
+		}),
+		Do("HOOKSTATS", "a", "b").Err("wrong number of arguments for 'hookstats' command"),
+	)
+}
+
+func hooks_HOOKDLQ_test(mc *mockServer) error {
+	return mc.DoBatch(
+		// without a dead-letter queue the messages are dropped
+		Do("SETHOOK", "hook1", "http://127.0.0.1:1/hook", "RETRY", "2", "BACKOFF", "0.1", "0.1", "NEARBY", "mykey", "FENCE", "DETECT", "enter", "POINT", 33, -115, 100).Str("1"),
+		Do("SET", "mykey", "myid0", "POINT", 33, -115).OK(),
+		Sleep(time.Second),
+		Do("HOOKSTATS", "hook1").JSON().Func(func(s string) error {
+			h := gjson.Get(s, "hooks.0")
+			if h.Get("pending").Int() != 0 || h.Get("dead").Int() != 0 ||
+				h.Get("endpoints.0.failed").Int() != 2 {
+				return fmt.Errorf("unexpected stats: %s", s)
+			}
+			return nil
+		}),
+		// the dead-letter queue keeps the last message
+		Do("SETHOOK", "hook1", "http://127.0.0.1:1/hook", "RETRY", "2", "BACKOFF", "0.1", "0.1", "DLQ", "1", "3600", "NEARBY", "mykey", "FENCE", "DETECT", "enter", "POINT", 33, -115, 100).Str("1"),
+		Do("SET", "mykey", "myid1", "POINT", 33, -115).OK(),
+		Sleep(time.Second),
+		Do("SET", "mykey", "myid2", "POINT", 33, -115).OK(),
+		Sleep(time.Second),
+		Do("HOOKSTATS", "hook1").JSON().Func(func(s string) error {
+			h := gjson.Get(s, "hooks.0")
+			if h.Get("pending").Int() != 0 || h.Get("dead").Int() != 1 ||
+				h.Get("endpoints.0.failed").Int() != 4 ||
+				h.Get("endpoints.0.last_error").String() == "" {
+				return fmt.Errorf("unexpected stats: %s", s)
+			}
+			return nil
+		}),
+		Do("HOOKDLQ", "hook1").JSON().Func(func(s string) error {
+			if gjson.Get(s, "messages.#").Int() != 1 ||
+				gjson.Get(s, "messages.0.detect").String() != "enter" ||
+				gjson.Get(s, "messages.0.id").String() != "myid2" {
+				return fmt.Errorf("unexpected messages: %s", s)
+			}
+			return nil
+		}),
+		Do("HOOKDLQ", "hook1", "REPLAY").Str("1"),
+		Sleep(time.Second),
+		Do("HOOKDLQ", "hook1", "PURGE").Str("1"),
+		Do("HOOKDLQ", "hook1", "PURGE").Str("0"),
+		Do("HOOKSTATS", "hook1").JSON().Func(func(s string) error {
+			h := gjson.Get(s, "hooks.0")
+			if h.Get("dead").Int() != 0 || h.Get("endpoints.0.failed").Int() != 6 {
+				return fmt.Errorf("unexpected stats: %s", s)
+			}
+			return nil
+		}),
+	)
+}
+
+func hooks_HOOKPAUSE_test(mc *mockServer) error {
+	var received1, received2 atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			io.Copy(io.Discard, r.Body)
+			if r.URL.Path == "/1" {
+				received1.Add(1)
+			} else {
+				received2.Add(1)
+			}
+		},
+	))
+	defer ts.Close()
+	// paused returns a check of the pause state of a hook
+	paused := func(paused bool, pending, dropped int) func(s string) error {
+		return func(s string) error {
+			h := gjson.Get(s, "hooks.0")
+			if h.Get("paused").Bool() != paused ||
+				h.Get("pending").Int() != int64(pending) ||
+				h.Get("paused_dropped").Int() != int64(dropped) {
+				return fmt.Errorf("unexpected stats: %s", s)
+			}
+			return nil
+		}
+	}
+	if err := mc.DoBatch(
+		Do("SETHOOK", "hook1", ts.URL+"/1", "NEARBY", "mykey", "FENCE", "DETECT", "enter", "POINT", 33, -115, 100).Str("1"),
+		Do("SETHOOK", "hook2", ts.URL+"/2", "NEARBY", "mykey", "FENCE", "DETECT", "enter", "POINT", 33, -115, 100).Str("1"),
+		Do("HOOKPAUSE").Err("wrong number of arguments for 'hookpause' command"),
+		Do("HOOKPAUSE", "hook1", "FOO").Err("invalid argument 'FOO'"),
+		Do("HOOKPAUSE", "hook1", "QUEUE").Err("wrong number of arguments for 'hookpause' command"),
+		Do("HOOKPAUSE", "hook1", "QUEUE", 1).Str("1"),
+		Do("HOOKPAUSE", "hook2", "DROP").Str("1"),
+		Do("HOOKPAUSE", "nohook").Str("0"),
+		Do("SET", "mykey", "truck1", "POINT", 33, -115).OK(),
+		Do("SET", "mykey", "truck2", "POINT", 33, -115).OK(),
+		Sleep(time.Second/2),
+		Do("HOOKSTATS", "hook1").JSON().Func(paused(true, 1, 1)),
+		Do("HOOKSTATS", "hook2").JSON().Func(paused(true, 0, 2)),
+		Do("HOOKS", "hook1").JSON().Func(func(s string) error {
+			if !gjson.Get(s, "hooks.0.paused").Bool() {
+				return fmt.Errorf("expected paused, got '%s'", s)
+			}
+			return nil
+		}),
+		// a new definition stays paused
+		Do("SETHOOK", "hook2", ts.URL+"/2", "RETRY", 3, "NEARBY", "mykey", "FENCE", "DETECT", "enter", "POINT", 33, -115, 100).Str("1"),
+		Do("HOOKSTATS", "hook2").JSON().Func(paused(true, 0, 2)),
+		// the queued messages don't expire while paused, and count for the
+		// max of the queue
+		Do("SETHOOK", "hook1", ts.URL+"/1", "MSGTTL", 0.2, "NEARBY", "mykey", "FENCE", "DETECT", "enter", "POINT", 33, -115, 100).Str("1"),
+		Do("HOOKPAUSE", "hook1", "QUEUE", 2).Str("1"),
+		Do("SET", "mykey", "truck3", "POINT", 33, -115).OK(),
+		Do("SET", "mykey", "truck4", "POINT", 33, -115).OK(),
+		Sleep(time.Second/2),
+		Do("HOOKSTATS", "hook1").JSON().Func(paused(true, 2, 2)),
+		Do("HOOKSTATS", "hook1").JSON().Func(func(s string) error {
+			if gjson.Get(s, "hooks.0.paused_queued").Int() != 2 {
+				return fmt.Errorf("expected 2 queued, got '%s'", s)
+			}
+			return nil
+		}),
+	); err != nil {
+		return err
+	}
+	if n1, n2 := received1.Load(), received2.Load(); n1 != 0 || n2 != 0 {
+		return fmt.Errorf("expected no deliveries, got %d and %d", n1, n2)
+	}
+
+	// the pause is kept in the aof
+	time.Sleep(time.Millisecond * 100)
+	aof, err := mc.readAOF()
+	if err != nil {
+		return err
+	}
+	mc2, err := loadAOF(aof)
+	if err != nil {
+		return err
+	}
+	defer mc2.Close()
+	if err := mc2.DoBatch(
+		Do("HOOKSTATS", "hook1").JSON().Func(paused(true, 0, 0)),
+		Do("HOOKRESUME", "hook*").Str("2"),
+	); err != nil {
+		return err
+	}
+
+	if err := mc.DoBatch(
+		Do("HOOKRESUME", "hook*").Str("2"),
+		Sleep(time.Second/2),
+		Do("HOOKSTATS", "hook1").JSON().Func(paused(false, 0, 0)),
+	); err != nil {
+		return err
+	}
+	if n1, n2 := received1.Load(), received2.Load(); n1 != 2 || n2 != 0 {
+		return fmt.Errorf("expected 2 and 0 deliveries, got %d and %d", n1, n2)
+	}
+	return nil
+}
+
+func hooks_HOOKS_RESP_test(mc *mockServer) error {
+	return mc.DoBatch(
+		Do("SETHOOK", "hook1", "http://127.0.0.1:1/hook", "META", "a", "1", "NEARBY", "mykey", "FENCE", "POINT", 33, -115, 100).Str("1"),
+		Do("HOOKS", "hook1").Str("[[hook1 mykey [http://127.0.0.1:1/hook] [NEARBY mykey FENCE POINT 33 -115 100] [a 1] []]]"),
+		// the options are listed after the metas
+		Do("SETHOOK", "hook1", "http://127.0.0.1:1/hook", "STALE", 30, "SCHEDULE", "0 0 1 1 *", "DURATION", 1, "TZ", "America/Sao_Paulo", "NEARBY", "mykey", "FENCE", "POINT", 33, -115, 100).Str("1"),
+		Do("HOOKPAUSE", "hook1").Str("1"),
+		Do("HOOKS", "hook1").Str("[[hook1 mykey [http://127.0.0.1:1/hook] [NEARBY mykey FENCE POINT 33 -115 100] [] " +
+			"[stale 30 schedule [schedule 0 0 1 1 * duration 1 tz America/Sao_Paulo] active false paused true]]]"),
+	)
+}
+
+func hooks_BATCH_test(mc *mockServer) error {
+	bodies := make(chan string, 10)
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			bodies <- string(body)
+		},
+	))
+	defer ts.Close()
+	err := mc.DoBatch(
+		Do("SETHOOK", "hook1", ts.URL, "BATCH", "0", "1", "NEARBY", "mykey", "FENCE", "POINT", 33, -115, 100).Err("invalid argument '0'"),
+		Do("SETHOOK", "hook1", ts.URL, "BATCH", "10", "0.5", "NEARBY", "mykey", "FENCE", "DETECT", "enter", "POINT", 33, -115, 100).Str("1"),
+		Do("SET", "mykey", "myid1", "POINT", 33, -115).OK(),
+		Do("SET", "mykey", "myid2", "POINT", 33, -115).OK(),
+		Do("SET", "mykey", "myid3", "POINT", 33, -115).OK(),
+	)
+	if err != nil {
+		return err
+	}
+	select {
+	case body := <-bodies:
+		ids := gjson.Get(body, "#.id").String()
+		if ids != `["myid1","myid2","myid3"]` {
+			return fmt.Errorf("expected '%s', got '%s'", `["myid1","myid2","myid3"]`, ids)
+		}
+	case <-time.After(time.Second * 5):
+		return fmt.Errorf("timeout waiting for batch")
+	}
+	return nil
+}
+
+func hooks_SECRET_test(mc *mockServer) error {
+	headers := make(chan http.Header, 10)
+	bodies := make(chan string, 10)
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			headers <- r.Header
+			bodies <- string(body)
+		},
+	))
+	defer ts.Close()
+	err := mc.DoBatch(
+		Do("SETCHAN", "chan1", "SECRET", "s1", "NEARBY", "mykey", "FENCE", "POINT", 33, -115, 100).Err("invalid argument 'SECRET'"),
+		Do("SETHOOK", "hook1", ts.URL, "SECRET", "s1", "SECRET", "s2", "SECRET", "s3", "NEARBY", "mykey", "FENCE", "POINT", 33, -115, 100).Err("invalid argument 'SECRET'"),
+		Do("SETHOOK", "hook1", ts.URL, "SECRET", "new", "SECRET", "old", "NEARBY", "mykey", "FENCE", "DETECT", "enter", "POINT", 33, -115, 100).Str("1"),
+		Do("HOOKS", "*").JSON().Func(func(s string) error {
+			if strings.Contains(s, "new") || strings.Contains(s, "old") {
+				return fmt.Errorf("secrets not redacted: %s", s)
+			}
+			if gjson.Get(s, "hooks.0.secrets.#").Int() != 2 {
+				return fmt.Errorf("expected two secrets, got '%s'", s)
+			}
+			return nil
+		}),
+		Do("SET", "mykey", "myid1", "POINT", 33, -115).OK(),
+	)
+	if err != nil {
+		return err
+	}
+	select {
+	case header := <-headers:
+		body := <-bodies
+		ts := header.Get("X-Meridian-Timestamp")
+		var sigs []string
+		for _, secret := range []string{"new", "old"} {
+			mac := hmac.New(sha256.New, []byte(secret))
+			mac.Write([]byte(ts + "." + body))
+			sigs = append(sigs, "v1="+hex.EncodeToString(mac.Sum(nil)))
+		}
+		exp := strings.Join(sigs, ",")
+		if sig := header.Get("X-Meridian-Signature"); sig != exp {
+			return fmt.Errorf("expected '%s', got '%s'", exp, sig)
+		}
+	case <-time.After(time.Second * 5):
+		return fmt.Errorf("timeout waiting for delivery")
+	}
+	return nil
+}
+
+func hooks_REPLAY_test(mc *mockServer) error {
+	bodies := make(chan string, 10)
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			bodies <- string(body)
+		},
+	))
+	defer ts.Close()
+	recv := func(n int) ([]string, error) {
+		var msgs []string
+		for len(msgs) < n {
+			select {
+			case body := <-bodies:
+				msgs = append(msgs, body)
+			case <-time.After(time.Second * 5):
+				return nil, fmt.Errorf("timeout waiting for events")
+			}
+		}
+		return msgs, nil
+	}
+	err := mc.DoBatch(
+		Do("CONFIG", "SET", "eventlog-maxsize", "1mb").OK(),
+		Do("SETHOOK", "hook1", ts.URL, "NEARBY", "mykey", "FENCE", "DETECT", "enter", "POINT", 33, -115, 100).Str("1"),
+		Do("SET", "mykey", "myid1", "POINT", 33, -115).OK(),
+		Do("SET", "mykey", "myid2", "POINT", 33, -115).OK(),
+		Do("SET", "mykey", "myid3", "POINT", 33, -115).OK(),
+	)
+	if err != nil {
+		return err
+	}
+	msgs, err := recv(3)
+	if err != nil {
+		return err
+	}
+	seq := gjson.Get(msgs[1], "seq").Int()
+	if seq == 0 || gjson.Get(msgs[2], "seq").Int() != seq+1 {
+		return fmt.Errorf("unexpected sequence numbers: %v", msgs)
+	}
+	err = mc.DoBatch(
+		Do("REPLAY", "hook2", "FROM", seq).Err("hook not found"),
+		Do("REPLAY", "hook1", "TO", seq).Err("invalid argument 'TO'"),
+		Do("REPLAY", "hook1", "FROM", "abc").Err("invalid argument 'abc'"),
+		Do("REPLAY", "hook1", "FROM", seq).Str("2"),
+		Do("CONFIG", "SET", "eventlog-maxsize", "").OK(),
+		Do("REPLAY", "hook1", "FROM", seq).Err("event log disabled"),
+	)
+	if err != nil {
+		return err
+	}
+	if msgs, err = recv(2); err != nil {
+		return err
+	}
+	for i, id := range []string{"myid2", "myid3"} {
+		if gjson.Get(msgs[i], "id").String() != id {
+			return fmt.Errorf("expected '%s', got '%s'", id, msgs[i])
+		}
+	}
+
+	// the events of a channel are returned to the caller, without being
+	// published again to the subscribers
+	conn, err := redis.Dial("tcp", fmt.Sprintf(":%d", mc.port))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	psc := redis.PubSubConn{Conn: conn}
+	err = mc.DoBatch(
+		Do("CONFIG", "SET", "eventlog-maxsize", "1mb").OK(),
+		Do("SETCHAN", "chan1", "NEARBY", "mykey", "FENCE", "DETECT", "enter", "POINT", 33, -115, 100).Str("1"),
+	)
+	if err != nil {
+		return err
+	}
+	if err := psc.Subscribe("chan1"); err != nil {
+		return err
+	}
+	if _, ok := psc.Receive().(redis.Subscription); !ok {
+		return fmt.Errorf("expected a subscription")
+	}
+	err = mc.DoBatch(
+		Do("SET", "mykey", "myid4", "POINT", 33, -115).OK(),
+		Do("SET", "mykey", "myid5", "POINT", 33, -115).OK(),
+	)
+	if err != nil {
+		return err
+	}
+	msgs = nil
+	for len(msgs) < 2 {
+		switch v := psc.ReceiveWithTimeout(time.Second * 5).(type) {
+		case redis.Message:
+			msgs = append(msgs, string(v.Data))
+		case error:
+			return v
+		}
+	}
+	seq = gjson.Get(msgs[0], "seq").Int()
+	err = mc.DoBatch(
+		Do("REPLAY", "chan1", "FROM", seq).JSON().Func(func(s string) error {
+			if ids := gjson.Get(s, "events.#.id").String(); ids != `["myid4","myid5"]` {
+				return fmt.Errorf("expected '%s', got '%s'", `["myid4","myid5"]`, s)
+			}
+			return nil
+		}),
+		Do("REPLAY", "chan1", "FROM", seq+1).Func(func(s string) error {
+			if !strings.Contains(s, "myid5") || strings.Contains(s, "myid4") {
+				return fmt.Errorf("expected the myid5 event, got '%s'", s)
+			}
+			return nil
+		}),
+	)
+	if err != nil {
+		return err
+	}
+	switch v := psc.ReceiveWithTimeout(time.Second / 5).(type) {
+	case redis.Message:
+		return fmt.Errorf("unexpected message '%s'", v.Data)
+	}
+	return nil
+}
+
+func hooks_WebSocket_test(mc *mockServer) error {
+	msgs := make(chan string, 10)
+	var upgrader websocket.Upgrader
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			conn, err := upgrader.Upgrade(w, r, nil)
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+			for {
+				_, msg, err := conn.ReadMessage()
+				if err != nil {
+					return
+				}
+				msgs <- string(msg)
+				conn.WriteMessage(websocket.TextMessage, []byte(`{"ok":true}`))
+			}
+		},
+	))
+	defer ts.Close()
+	url := "ws" + strings.TrimPrefix(ts.URL, "http") + "/events?ack=1"
+	err := mc.DoBatch(
+		Do("SETHOOK", "hook1", url, "NEARBY", "mykey", "FENCE", "DETECT", "enter", "POINT", 33, -115, 100).Str("1"),
+		Do("SET", "mykey", "myid1", "POINT", 33, -115).OK(),
+		Do("SET", "mykey", "myid2", "POINT", 33, -115).OK(),
+	)
+	if err != nil {
+		return err
+	}
+	for _, id := range []string{"myid1", "myid2"} {
+		select {
+		case msg := <-msgs:
+			if gjson.Get(msg, "id").String() != id {
+				return fmt.Errorf("expected '%s', got '%s'", id, msg)
+			}
+		case <-time.After(time.Second * 5):
+			return fmt.Errorf("timeout waiting for websocket message")
+		}
+	}
+	return mc.DoBatch(
+		Sleep(time.Second/4),
+		Do("HOOKSTATS", "hook1").JSON().Func(func(s string) error {
+			h := gjson.Get(s, "hooks.0")
+			if h.Get("pending").Int() != 0 || h.Get("endpoints.0.sent").Int() != 2 {
+				return fmt.Errorf("unexpected stats: %s", s)
+			}
+			return nil
+		}),
+	)
+}
+
+func hooks_RedisXADD_test(mc *mockServer) error {
+	cmds := make(chan []string, 10)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return err
+	}
+	defer ln.Close()
+	go redcon.Serve(ln,
+		func(conn redcon.Conn, cmd redcon.Command) {
+			var args []string
+			for _, arg := range cmd.Args {
+				args = append(args, string(arg))
+			}
+			cmds <- args
+			conn.WriteBulkString("1-0")
+		}, nil, nil,
+	)
+	url := "redis://" + ln.Addr().String() + "/mystream?mode=xadd&maxlen=1000"
+	err = mc.DoBatch(
+		Do("SETHOOK", "hook1", "redis://127.0.0.1:6379/mystream?mode=foo", "NEARBY", "mykey", "FENCE", "POINT", 33, -115, 100).Err("invalid argument 'redis://127.0.0.1:6379/mystream?mode=foo'"),
+		Do("SETHOOK", "hook1", "redis://127.0.0.1:6379/mystream?maxlen=10", "NEARBY", "mykey", "FENCE", "POINT", 33, -115, 100).Err("invalid argument 'redis://127.0.0.1:6379/mystream?maxlen=10'"),
+		Do("SETHOOK", "hook1", url, "NEARBY", "mykey", "FENCE", "DETECT", "enter", "POINT", 33, -115, 100).Str("1"),
+		Do("SET", "mykey", "myid1", "POINT", 33, -115).OK(),
+	)
+	if err != nil {
+		return err
+	}
+	select {
+	case args := <-cmds:
+		exp := "XADD mystream MAXLEN 1000 * hook hook1 key mykey id myid1 detect enter command set message"
+		if len(args) != 17 || strings.Join(args[:16], " ") != exp {
+			return fmt.Errorf("expected '%s', got '%s'", exp, args)
+		}
+		if gjson.Get(args[16], "id").String() != "myid1" {
+			return fmt.Errorf("unexpected message '%s'", args[16])
+		}
+	case <-time.After(time.Second * 5):
+		return fmt.Errorf("timeout waiting for XADD")
+	}
+	return nil
+}
+
+func hooks_RedisXADD_BATCH_test(mc *mockServer) error {
+	cmds := make(chan []string, 10)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return err
+	}
+	defer ln.Close()
+	go redcon.Serve(ln,
+		func(conn redcon.Conn, cmd redcon.Command) {
+			var args []string
+			for _, arg := range cmd.Args {
+				args = append(args, string(arg))
+			}
+			cmds <- args
+			conn.WriteBulkString("1-0")
+		}, nil, nil,
+	)
+	url := "redis://" + ln.Addr().String() + "/mystream?mode=xadd"
+	err = mc.DoBatch(
+		Do("SETHOOK", "hook1", url, "BATCH", "10", "0.5", "NEARBY", "mykey", "FENCE", "DETECT", "enter", "POINT", 33, -115, 100).Str("1"),
+		Do("SET", "mykey", "myid1", "POINT", 33, -115).OK(),
+		Do("SET", "mykey", "myid2", "POINT", 33, -115).OK(),
+		Do("SET", "mykey", "myid3", "POINT", 33, -115).OK(),
+	)
+	if err != nil {
+		return err
+	}
+	// each event of the batch is added as its own stream entry
+	for _, id := range []string{"myid1", "myid2", "myid3"} {
+		select {
+		case args := <-cmds:
+			exp := "XADD mystream * hook hook1 key mykey id " + id +
+				" detect enter command set message"
+			if len(args) != 15 || strings.Join(args[:14], " ") != exp {
+				return fmt.Errorf("expected '%s', got '%s'", exp, args)
+			}
+			if gjson.Get(args[14], "id").String() != id {
+				return fmt.Errorf("unexpected message '%s'", args[14])
+			}
+		case <-time.After(time.Second * 5):
+			return fmt.Errorf("timeout waiting for XADD")
+		}
+	}
+	return mc.DoBatch(
+		Sleep(time.Second/10),
+		Do("HOOKSTATS", "hook1").JSON().Func(func(s string) error {
+			h := gjson.Get(s, "hooks.0")
+			if h.Get("pending").Int() != 0 || h.Get("endpoints.0.failed").Int() != 0 {
+				return fmt.Errorf("unexpected stats: %s", s)
+			}
+			return nil
+		}),
+	)
+}
+
+func hooks_file_test(mc *mockServer) error {
+	dir, err := os.MkdirTemp("", "meridian-hooks")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "events.ndjson")
+	link := filepath.Join(dir, "link")
+	if err := os.Symlink(os.TempDir(), link); err != nil {
+		return err
+	}
+	err = mc.DoBatch(
+		Do("SETHOOK", "hook1", "file://"+path, "NEARBY", "mykey", "FENCE", "POINT", 33, -115, 100).Err("invalid argument 'file://"+path+"'"),
+		Do("CONFIG", "SET", "endpoint-dir", dir).OK(),
+		Do("SETHOOK", "hook1", "file://"+dir+"/../events.ndjson", "NEARBY", "mykey", "FENCE", "POINT", 33, -115, 100).Err("invalid argument 'file://"+dir+"/../events.ndjson'"),
+		Do("SETHOOK", "hook1", "file://relative/events.ndjson", "NEARBY", "mykey", "FENCE", "POINT", 33, -115, 100).Err("invalid argument 'file://relative/events.ndjson'"),
+		Do("SETHOOK", "hook1", "file://"+link+"/events.ndjson", "NEARBY", "mykey", "FENCE", "POINT", 33, -115, 100).Err("invalid argument 'file://"+link+"/events.ndjson'"),
+		Do("SETHOOK", "hook1", "file://"+path+"?fsync=sometimes", "NEARBY", "mykey", "FENCE", "POINT", 33, -115, 100).Err("invalid argument 'file://"+path+"?fsync=sometimes'"),
+		Do("SETHOOK", "hook1", "file://"+path+"?maxsize=300b&fsync=always", "NEARBY", "mykey", "FENCE", "DETECT", "enter", "POINT", 33, -115, 100).Str("1"),
+		Do("SET", "mykey", "myid1", "POINT", 33, -115).OK(),
+		Do("SET", "mykey", "myid2", "POINT", 33, -115).OK(),
+		Do("SET", "mykey", "myid3", "POINT", 33, -115).OK(),
+		Sleep(time.Second/2),
+	)
+	if err != nil {
+		return err
+	}
+	// each event is larger than half of maxsize, so every event is written
+	// to its own file.
+	files, err := filepath.Glob(path + "*")
+	if err != nil {
+		return err
+	}
+	if len(files) != 3 {
+		return fmt.Errorf("expected 3 files, got %d", len(files))
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if id := gjson.Get(string(data), "id").String(); id != "myid3" {
+		return fmt.Errorf("expected '%s', got '%s'", "myid3", id)
+	}
+	return nil
+}
+
+func hooks_unix_test(mc *mockServer) error {
+	dir, err := os.MkdirTemp("", "meridian-hooks")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "consumer.sock")
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return err
+	}
+	defer ln.Close()
+	lines := make(chan string, 10)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		rd := bufio.NewScanner(conn)
+		for rd.Scan() {
+			lines <- rd.Text()
+		}
+	}()
+	err = mc.DoBatch(
+		Do("SETHOOK", "hook1", "unix://"+path, "NEARBY", "mykey", "FENCE", "DETECT", "enter", "POINT", 33, -115, 100).Err("invalid argument 'unix://"+path+"'"),
+		Do("CONFIG", "SET", "endpoint-dir", dir).OK(),
+		Do("SETHOOK", "hook1", "unix:///var/run/docker.sock", "NEARBY", "mykey", "FENCE", "DETECT", "enter", "POINT", 33, -115, 100).Err("invalid argument 'unix:///var/run/docker.sock'"),
+		Do("SETHOOK", "hook1", "unix://"+path, "NEARBY", "mykey", "FENCE", "DETECT", "enter", "POINT", 33, -115, 100).Str("1"),
+		Do("SET", "mykey", "myid1", "POINT", 33, -115).OK(),
+		Do("SET", "mykey", "myid2", "POINT", 33, -115).OK(),
+	)
+	if err != nil {
+		return err
+	}
+	for _, id := range []string{"myid1", "myid2"} {
+		select {
+		case line := <-lines:
+			if gjson.Get(line, "id").String() != id {
+				return fmt.Errorf("expected '%s', got '%s'", id, line)
+			}
+		case <-time.After(time.Second * 5):
+			return fmt.Errorf("timeout waiting for unix socket message")
+		}
+	}
+	return nil
+}
+
+type hookStreamServer struct {
+	hservicev1.UnimplementedHookServiceServer
+	events chan *hservicev1.GeofenceEvent
+}
+
+func (s *hookStreamServer) Stream(stream hservicev1.HookService_StreamServer) error {
+	for {
+		ev, err := stream.Recv()
+		if err != nil {
+			return nil
+		}
+		s.events <- ev
+		err = stream.Send(&hservicev1.Ack{Seq: ev.Seq, Ok: ev.Id != "reject"})
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func hooks_gRPC_test(mc *mockServer) error {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return err
+	}
+	srv := &hookStreamServer{events: make(chan *hservicev1.GeofenceEvent, 10)}
+	gs := grpc.NewServer()
+	hservicev1.RegisterHookServiceServer(gs, srv)
+	go gs.Serve(ln)
+	defer gs.Stop()
+	err = mc.DoBatch(
+		Do("SETHOOK", "hook1", "grpc://"+ln.Addr().String(), "NEARBY", "mykey", "FENCE", "DETECT", "enter", "POINT", 33, -115, 100).Str("1"),
+		Do("SET", "mykey", "myid1", "FIELD", "speed", 90, "POINT", 33, -115).OK(),
+		Do("SET", "mykey", "reject", "POINT", 33, -115).OK(),
+	)
+	if err != nil {
+		return err
+	}
+	recv := func() (*hservicev1.GeofenceEvent, error) {
+		select {
+		case ev := <-srv.events:
+			return ev, nil
+		case <-time.After(time.Second * 5):
+			return nil, fmt.Errorf("timeout waiting for grpc event")
+		}
+	}
+	ev, err := recv()
+	if err != nil {
+		return err
+	}
+	if ev.Seq != 1 || ev.Hook != "hook1" || ev.Key != "mykey" ||
+		ev.Id != "myid1" || ev.Detect != "enter" || ev.Command != "set" ||
+		ev.Geometry.GetPoint().GetLat() != 33 ||
+		ev.Geometry.GetPoint().GetLon() != -115 ||
+		ev.Fields.GetFields()["speed"].GetNumberValue() != 90 ||
+		ev.Time == nil {
+		return fmt.Errorf("unexpected event: %v", ev)
+	}
+	// the rejected event is retried on the same stream
+	for i := 0; i < 2; i++ {
+		if ev, err = recv(); err != nil {
+			return err
+		}
+		if ev.Id != "reject" || ev.Seq != uint64(i+2) {
+			return fmt.Errorf("unexpected event: %v", ev)
+		}
+	}
+	return nil
+}
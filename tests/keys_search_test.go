@@ -33,6 +33,7 @@ func subTestSearch(g *testGroup) {
 	g.regSubTest("MATCH", keys_MATCH_test)
 	g.regSubTest("FIELDS", keys_FIELDS_search_test)
 	g.regSubTest("BUFFER", keys_BUFFER_search_test)
+	g.regSubTest("CLUSTER", keys_CLUSTER_search_test)
 }
 
 func keys_KNN_basic_test(mc *mockServer) error {
@@ -789,6 +790,71 @@ func keys_BUFFER_search_test(mc *mockServer) error {
 	})
 }
 
+func keys_CLUSTER_search_test(mc *mockServer) error {
+	return mc.DoBatch(
+		Do("SET", "fleet", "a1", "POINT", 33, -115).OK(),
+		Do("SET", "fleet", "a2", "POINT", 33.05, -115.05).OK(),
+		Do("SET", "fleet", "a3", "POINT", 33.1, -115).OK(),
+		Do("SET", "fleet", "b1", "POINT", 40, -100).OK(),
+		Do("SET", "fleet", "b2", "POINT", 40, -100).OK(),
+		Do("SET", "fleet", "lone", "POINT", 0, 0).OK(),
+		Do("WITHIN", "fleet", "CLUSTER", 40, 5, "BOUNDS", -10, -180, 60, 10).JSON().
+			Func(func(s string) error {
+				if n := gjson.Get(s, "count").Int(); n != 6 {
+					return fmt.Errorf("expected count 6, got %d", n)
+				}
+				var counts []int
+				for _, c := range gjson.Get(s, "clusters").Array() {
+					n := int(c.Get("count").Int())
+					counts = append(counts, n)
+					switch n {
+					case 1:
+						if c.Get("id").String() != "lone" ||
+							c.Get("expansion_zoom").Exists() {
+							return fmt.Errorf("unexpected single %s", c.Raw)
+						}
+					case 2:
+						if c.Get("point").Raw != `{"lat":40,"lon":-100}` ||
+							c.Get("expansion_zoom").Int() != 24 {
+							return fmt.Errorf("unexpected cluster %s", c.Raw)
+						}
+					case 3:
+						if c.Get("id").Exists() ||
+							c.Get("expansion_zoom").Int() <= 5 {
+							return fmt.Errorf("unexpected cluster %s", c.Raw)
+						}
+					}
+				}
+				sort.Ints(counts)
+				if fmt.Sprint(counts) != "[1 2 3]" {
+					return fmt.Errorf("unexpected clusters %s", s)
+				}
+				return nil
+			}),
+		Do("INTERSECTS", "fleet", "CLUSTER", 40, 5, "BOUNDS", 39, -101, 41, -99).
+			Str("[0 [[ [40 -100] 2 24]]]"),
+		Do("WITHIN", "fleet", "CLUSTER", 40, 18, "BOUNDS", -10, -180, 60, 10).JSON().
+			Func(func(s string) error {
+				if n := len(gjson.Get(s, "clusters").Array()); n != 5 {
+					return fmt.Errorf("expected 5 clusters, got %d", n)
+				}
+				return nil
+			}),
+		Do("INTERSECTS", "fleet", "CLUSTER", 40, 5, "MVT", 5, 11, 5).JSON().
+			Func(func(s string) error {
+				if gjson.Get(s, "mvt").String() == "" {
+					return errors.New("missing mvt")
+				}
+				return nil
+			}),
+		Do("WITHIN", "fleet", "CLUSTER", 0, 5, "BOUNDS", 0, 0, 1, 1).Err("invalid argument '0'"),
+		Do("WITHIN", "fleet", "CLUSTER", 40, 25, "BOUNDS", 0, 0, 1, 1).Err("invalid argument '25'"),
+		Do("WITHIN", "fleet", "CLUSTER", 40).Err("wrong number of arguments for 'within' command"),
+		Do("NEARBY", "fleet", "CLUSTER", 40, 5, "POINT", 0, 0).Err("CLUSTER is not allowed for NEARBY"),
+		Do("WITHIN", "fleet", "FENCE", "CLUSTER", 40, 5, "BOUNDS", 0, 0, 1, 1).Err("CLUSTER is not allowed when FENCE is specified"),
+	)
+}
+
 // match sorts the response and compares to the expected input
 func match(expectIn string) func(org, v interface{}) (resp, expect interface{}) {
 	return func(v, org interface{}) (resp, expect interface{}) {
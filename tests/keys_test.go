@@ -415,17 +415,17 @@ func keys_STATS_test(mc *mockServer) error {
 	return mc.DoBatch(
 		Do("STATS", "mykey").Str("[nil]"),
 		Do("SET", "mykey", "myid", "STRING", "value").OK(),
-		Do("STATS", "mykey").Str("[[in_memory_size 9 num_objects 1 num_points 0 num_strings 1]]"),
-		Do("STATS", "mykey", "hello").JSON().Str(`{"ok":true,"stats":[{"in_memory_size":9,"num_objects":1,"num_points":0,"num_strings":1},null]}`),
+		Do("STATS", "mykey").Str("[[in_memory_size 9 num_expired 0 num_objects 1 num_points 0 num_strings 1]]"),
+		Do("STATS", "mykey", "hello").JSON().Str(`{"ok":true,"stats":[{"in_memory_size":9,"num_expired":0,"num_objects":1,"num_points":0,"num_strings":1},null]}`),
 		Do("SET", "mykey", "myid2", "STRING", "value").OK(),
-		Do("STATS", "mykey").Str("[[in_memory_size 19 num_objects 2 num_points 0 num_strings 2]]"),
+		Do("STATS", "mykey").Str("[[in_memory_size 19 num_expired 0 num_objects 2 num_points 0 num_strings 2]]"),
 		Do("SET", "mykey", "myid3", "OBJECT", `{"type":"Point","coordinates":[-115,33]}`).OK(),
-		Do("STATS", "mykey").Str("[[in_memory_size 40 num_objects 3 num_points 1 num_strings 2]]"),
+		Do("STATS", "mykey").Str("[[in_memory_size 40 num_expired 0 num_objects 3 num_points 1 num_strings 2]]"),
 		Do("DEL", "mykey", "myid").Str("1"),
-		Do("STATS", "mykey").Str("[[in_memory_size 31 num_objects 2 num_points 1 num_strings 1]]"),
+		Do("STATS", "mykey").Str("[[in_memory_size 31 num_expired 0 num_objects 2 num_points 1 num_strings 1]]"),
 		Do("DEL", "mykey", "myid3").Str("1"),
-		Do("STATS", "mykey").Str("[[in_memory_size 10 num_objects 1 num_points 0 num_strings 1]]"),
-		Do("STATS", "mykey", "mykey2").Str("[[in_memory_size 10 num_objects 1 num_points 0 num_strings 1] nil]"),
+		Do("STATS", "mykey").Str("[[in_memory_size 10 num_expired 0 num_objects 1 num_points 0 num_strings 1]]"),
+		Do("STATS", "mykey", "mykey2").Str("[[in_memory_size 10 num_expired 0 num_objects 1 num_points 0 num_strings 1] nil]"),
 		Do("DEL", "mykey", "myid2").Str("1"),
 		Do("STATS", "mykey").Str("[nil]"),
 		Do("STATS", "mykey", "mykey2").Str("[nil nil]"),
@@ -0,0 +1,145 @@
+package tests
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/tidwall/gjson"
+)
+
+func subTestKeyspace(g *testGroup) {
+	g.regSubTest("events", keyspace_events_test)
+	g.regSubTest("flags", keyspace_flags_test)
+}
+
+// keyspaceEvents subscribes to the keyspace channels, and returns a function
+// that receives the next events, as "channel event id" strings.
+func keyspaceEvents(mc *mockServer, pattern string) (func(n int) ([]string,
+	error), func(), error) {
+	conn, err := redis.Dial("tcp", fmt.Sprintf(":%d", mc.port))
+	if err != nil {
+		return nil, nil, err
+	}
+	psc := redis.PubSubConn{Conn: conn}
+	if err := psc.PSubscribe(pattern); err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+	if _, ok := psc.Receive().(redis.Subscription); !ok {
+		conn.Close()
+		return nil, nil, errors.New("expected a subscription")
+	}
+	next := func(n int) ([]string, error) {
+		var events []string
+		for len(events) < n {
+			switch v := psc.ReceiveWithTimeout(time.Second * 5).(type) {
+			case redis.Message:
+				msg := string(v.Data)
+				if gjson.Get(msg, "time").String() == "" {
+					return nil, fmt.Errorf("missing time in '%s'", msg)
+				}
+				events = append(events, strings.TrimSpace(v.Channel+" "+
+					gjson.Get(msg, "event").String()+" "+
+					gjson.Get(msg, "id").String()))
+			case error:
+				return events, v
+			}
+		}
+		return events, nil
+	}
+	return next, func() { conn.Close() }, nil
+}
+
+func keyspace_events_test(mc *mockServer) error {
+	if err := mc.DoBatch(
+		Do("CONFIG", "SET", "notify-keyspace-events", "KA").OK(),
+	); err != nil {
+		return err
+	}
+	next, closer, err := keyspaceEvents(mc, "__keyspace__:*")
+	if err != nil {
+		return err
+	}
+	defer closer()
+	if err := mc.DoBatch(
+		Do("SET", "fleet", "truck1", "POINT", 33, -115).OK(),
+		Do("FSET", "fleet", "truck1", "speed", 10).Str("1"),
+		Do("JSET", "fleet", "truck2", "name", "Truck").OK(),
+		Do("EXPIRE", "fleet", "truck1", 0.1).Str("1"),
+		Do("DEL", "fleet", "truck3").Str("0"),
+		Do("DEL", "fleet", "truck2").Str("1"),
+		Do("SLEEP", "0.5").OK(),
+		Do("SET", "fleet", "truck4", "POINT", 33, -115).OK(),
+		Do("RENAME", "fleet", "trucks").OK(),
+		Do("DROP", "trucks").Str("1"),
+	); err != nil {
+		return err
+	}
+	events, err := next(10)
+	if err != nil {
+		return err
+	}
+	expect := []string{
+		"__keyspace__:fleet set truck1",
+		"__keyspace__:fleet fset truck1",
+		"__keyspace__:fleet jset truck2",
+		"__keyspace__:fleet expire truck1",
+		"__keyspace__:fleet del truck2",
+		"__keyspace__:fleet expired truck1",
+		"__keyspace__:fleet set truck4",
+		"__keyspace__:fleet rename_from",
+		"__keyspace__:trucks rename_to",
+		"__keyspace__:trucks drop",
+	}
+	if strings.Join(events, "\n") != strings.Join(expect, "\n") {
+		return fmt.Errorf("expected:\n%s\ngot:\n%s", strings.Join(expect, "\n"),
+			strings.Join(events, "\n"))
+	}
+	return nil
+}
+
+func keyspace_flags_test(mc *mockServer) error {
+	if err := mc.DoBatch(
+		Do("CONFIG", "GET", "notify-keyspace-events").Str(
+			"[notify-keyspace-events ]"),
+		Do("CONFIG", "SET", "notify-keyspace-events", "Kz").Err(
+			"Invalid argument 'Kz' for CONFIG SET 'notify-keyspace-events'"),
+		Do("CONFIG", "SET", "notify-keyspace-events", "Kg").OK(),
+	); err != nil {
+		return err
+	}
+	next, closer, err := keyspaceEvents(mc, "__keyspace__:fleet")
+	if err != nil {
+		return err
+	}
+	defer closer()
+	// only the generic events, and only the fleet channel
+	if err := mc.DoBatch(
+		Do("SET", "fleet", "truck1", "POINT", 33, -115).OK(),
+		Do("SET", "other", "truck1", "POINT", 33, -115).OK(),
+		Do("DEL", "other", "truck1").Str("1"),
+		Do("DEL", "fleet", "truck1").Str("1"),
+		Do("CONFIG", "SET", "notify-keyspace-events", "").OK(),
+		Do("SET", "fleet", "truck1", "POINT", 33, -115).OK(),
+		Do("DEL", "fleet", "truck1").Str("1"),
+		Do("CONFIG", "SET", "notify-keyspace-events", "Kg").OK(),
+		Do("DROP", "fleet").Str("0"),
+		Do("SET", "fleet", "truck1", "POINT", 33, -115).OK(),
+		Do("DROP", "fleet").Str("1"),
+	); err != nil {
+		return err
+	}
+	events, err := next(2)
+	if err != nil {
+		return err
+	}
+	expect := "__keyspace__:fleet del truck1\n__keyspace__:fleet drop"
+	if strings.Join(events, "\n") != expect {
+		return fmt.Errorf("expected:\n%s\ngot:\n%s", expect,
+			strings.Join(events, "\n"))
+	}
+	return nil
+}
@@ -39,6 +39,7 @@ type mockServer struct {
 	closed   bool
 	port     int
 	mport    int
+	gport    int
 	conn     redis.Conn
 	ioJSON   bool
 	dir      string
@@ -53,11 +54,16 @@ func (mc *mockServer) metricsPort() int {
 	return mc.mport
 }
 
+func (mc *mockServer) grpcPort() int {
+	return mc.gport
+}
+
 type MockServerOptions struct {
 	AOFFileName string
 	AOFData     []byte
 	Silent      bool
 	Metrics     bool
+	GRPC        bool
 }
 
 var nextPort int32 = 10000
@@ -108,6 +114,9 @@ func mockOpenServer(opts MockServerOptions) (*mockServer, error) {
 	if opts.Metrics {
 		s.mport = getNextPort()
 	}
+	if opts.GRPC {
+		s.gport = getNextPort()
+	}
 	var ferr atomic.Pointer[error] // ferr for when the server fails to start
 	go func() {
 		sopts := server.Options{
@@ -123,6 +132,9 @@ func mockOpenServer(opts MockServerOptions) (*mockServer, error) {
 		if opts.Metrics {
 			sopts.MetricsAddr = fmt.Sprintf(":%d", s.mport)
 		}
+		if opts.GRPC {
+			sopts.GRPCAddr = fmt.Sprintf(":%d", s.gport)
+		}
 		err := server.Serve(sopts)
 		if err != nil {
 			ferr.CompareAndSwap(nil, &err)
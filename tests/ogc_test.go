@@ -0,0 +1,253 @@
+package tests
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+func subTestOGC(g *testGroup) {
+	g.regSubTest("collections", ogc_collections_test)
+	g.regSubTest("items", ogc_items_test)
+	g.regSubTest("item", ogc_item_test)
+	g.regSubTest("auth", ogc_auth_test)
+	g.regSubTest("acl", ogc_acl_test)
+}
+
+func ogcGet(mc *mockServer, path string, expectStatus int) (gjson.Result, error) {
+	status, body, err := downloadURLWithStatusCode(
+		fmt.Sprintf("http://127.0.0.1:%d/%s", mc.port, path))
+	if err != nil {
+		return gjson.Result{}, err
+	}
+	if status != expectStatus {
+		return gjson.Result{}, fmt.Errorf("expected status %d, got %d: %s",
+			expectStatus, status, body)
+	}
+	return gjson.Parse(body), nil
+}
+
+func ogc_collections_test(mc *mockServer) error {
+	if err := mc.DoBatch(
+		Do("SET", "fleet", "truck1", "POINT", 33, -115).OK(),
+		Do("SET", "fleet", "truck2", "POINT", 34, -114).OK(),
+		Do("SET", "zones", "z1", "BOUNDS", 10, 10, 20, 20).OK(),
+	); err != nil {
+		return err
+	}
+	res, err := ogcGet(mc, "conformance", 200)
+	if err != nil {
+		return err
+	}
+	if !res.Get(`conformsTo.#(%"*/conf/core")`).Exists() {
+		return fmt.Errorf("missing core conformance: %s", res.Raw)
+	}
+	res, err = ogcGet(mc, "collections", 200)
+	if err != nil {
+		return err
+	}
+	if ids := res.Get("collections.#.id").Raw; ids != `["fleet","zones"]` {
+		return fmt.Errorf("expected fleet and zones, got %s", ids)
+	}
+	res, err = ogcGet(mc, "collections/fleet", 200)
+	if err != nil {
+		return err
+	}
+	if bbox := res.Get("extent.spatial.bbox.0").Raw; bbox != `[-115,33,-114,34]` {
+		return fmt.Errorf("unexpected extent %s", bbox)
+	}
+	if !res.Get(`links.#(rel=="items").href`).Exists() {
+		return fmt.Errorf("missing items link: %s", res.Raw)
+	}
+	res, err = ogcGet(mc, "collections/nope", 404)
+	if err != nil {
+		return err
+	}
+	if res.Get("code").String() != "NotFound" {
+		return fmt.Errorf("expected NotFound, got %s", res.Raw)
+	}
+	return nil
+}
+
+func ogc_items_test(mc *mockServer) error {
+	for i := 0; i < 5; i++ {
+		if err := mc.DoBatch(
+			Do("SET", "fleet", fmt.Sprintf("truck%d", i), "FIELD", "speed", i*10,
+				"POINT", 33+float64(i), -115).OK(),
+		); err != nil {
+			return err
+		}
+	}
+	res, err := ogcGet(mc, "collections/fleet/items?limit=2", 200)
+	if err != nil {
+		return err
+	}
+	if res.Get("type").String() != "FeatureCollection" ||
+		res.Get("numberReturned").Int() != 2 {
+		return fmt.Errorf("unexpected result %s", res.Raw)
+	}
+	if v := res.Get("features.1").Raw; v != `{"type":"Feature","id":"truck1",`+
+		`"geometry":{"type":"Point","coordinates":[-115,34]},`+
+		`"properties":{"speed":10}}` {
+		return fmt.Errorf("unexpected feature %s", v)
+	}
+	next := res.Get(`links.#(rel=="next").href`).String()
+	u, err := url.Parse(next)
+	if err != nil {
+		return err
+	}
+	if u.Query().Get("offset") != "2" || u.Query().Get("limit") != "2" {
+		return fmt.Errorf("unexpected next link %s", next)
+	}
+	res, err = ogcGet(mc, u.Path[1:]+"?"+u.RawQuery, 200)
+	if err != nil {
+		return err
+	}
+	if ids := res.Get("features.#.id").Raw; ids != `["truck2","truck3"]` {
+		return fmt.Errorf("unexpected page %s", ids)
+	}
+
+	// bbox is minLon,minLat,maxLon,maxLat
+	res, err = ogcGet(mc, "collections/fleet/items?bbox=-116,34.5,-114,36.5", 200)
+	if err != nil {
+		return err
+	}
+	// spatial results are not ordered by id
+	if ids := res.Get("features.#.id").Raw; ids != `["truck2","truck3"]` &&
+		ids != `["truck3","truck2"]` {
+		return fmt.Errorf("unexpected bbox result %s", ids)
+	}
+	if res.Get(`links.#(rel=="next")`).Exists() {
+		return fmt.Errorf("unexpected next link %s", res.Raw)
+	}
+	res, err = ogcGet(mc, "collections/fleet/items?filter="+
+		url.QueryEscape("speed >= 30"), 200)
+	if err != nil {
+		return err
+	}
+	if ids := res.Get("features.#.id").Raw; ids != `["truck3","truck4"]` {
+		return fmt.Errorf("unexpected filter result %s", ids)
+	}
+	_, err = ogcGet(mc, "collections/fleet/items?bbox=1,2,3", 400)
+	return err
+}
+
+func ogc_item_test(mc *mockServer) error {
+	if err := mc.DoBatch(
+		Do("SET", "fleet", "truck1", "FIELD", "speed", 90, "OBJECT",
+			`{"type":"Feature","geometry":{"type":"Point","coordinates":[-115,33]},"properties":{"name":"one"}}`).OK(),
+		Do("SET", "fleet", "note", "STRING", "hello").OK(),
+	); err != nil {
+		return err
+	}
+	res, err := ogcGet(mc, "collections/fleet/items/truck1", 200)
+	if err != nil {
+		return err
+	}
+	if res.Get("geometry.type").String() != "Point" ||
+		res.Get("properties.name").String() != "one" ||
+		res.Get("properties.speed").Int() != 90 {
+		return fmt.Errorf("unexpected feature %s", res.Raw)
+	}
+	res, err = ogcGet(mc, "collections/fleet/items/note", 200)
+	if err != nil {
+		return err
+	}
+	if res.Get("geometry").Raw != "null" ||
+		res.Get("properties.value").String() != "hello" {
+		return fmt.Errorf("unexpected feature %s", res.Raw)
+	}
+	_, err = ogcGet(mc, "collections/fleet/items/truck2", 404)
+	return err
+}
+
+func ogc_auth_test(mc *mockServer) error {
+	if err := mc.DoBatch(
+		Do("SET", "fleet", "truck1", "POINT", 33, -115).OK(),
+		Do("CONFIG", "SET", "requirepass", "secret").OK(),
+	); err != nil {
+		return err
+	}
+	u := fmt.Sprintf("http://127.0.0.1:%d/collections/fleet/items", mc.port)
+	for _, auth := range []string{"", "wrong", "secret", "Bearer secret"} {
+		req, err := http.NewRequest("GET", u, nil)
+		if err != nil {
+			return err
+		}
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		expect := 401
+		if auth == "secret" || auth == "Bearer secret" {
+			expect = 200
+		}
+		if resp.StatusCode != expect {
+			return fmt.Errorf("expected status %d for '%s', got %d",
+				expect, auth, resp.StatusCode)
+		}
+	}
+	return mc.DoBatch(Do("AUTH", "secret").OK())
+}
+
+func ogc_acl_test(mc *mockServer) error {
+	if err := mc.DoBatch(
+		Do("SET", "fleet", "truck1", "POINT", 33, -115).OK(),
+		Do("SET", "zones", "z1", "BOUNDS", 10, 10, 20, 20).OK(),
+		Do("ACL", "SETUSER", "reader", "on", ">pass", "~fleet", "+@read").OK(),
+	); err != nil {
+		return err
+	}
+	get := func(path, password string) (int, string, error) {
+		req, err := http.NewRequest("GET",
+			fmt.Sprintf("http://127.0.0.1:%d/%s", mc.port, path), nil)
+		if err != nil {
+			return 0, "", err
+		}
+		req.SetBasicAuth("reader", password)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return 0, "", err
+		}
+		defer resp.Body.Close()
+		var body strings.Builder
+		if _, err := io.Copy(&body, resp.Body); err != nil {
+			return 0, "", err
+		}
+		return resp.StatusCode, body.String(), nil
+	}
+	for _, tc := range []struct {
+		path, password string
+		status         int
+	}{
+		{"collections/fleet/items", "wrong", 401},
+		{"collections/fleet/items", "pass", 200},
+		{"collections/zones/items", "pass", 403},
+		{"collections/zones", "pass", 403},
+	} {
+		status, body, err := get(tc.path, tc.password)
+		if err != nil {
+			return err
+		}
+		if status != tc.status {
+			return fmt.Errorf("%s: expected status %d, got %d: %s", tc.path,
+				tc.status, status, body)
+		}
+	}
+	_, body, err := get("collections", "pass")
+	if err != nil {
+		return err
+	}
+	if ids := gjson.Get(body, "collections.#.id").String(); ids != `["fleet"]` {
+		return fmt.Errorf("expected only fleet, got %s", ids)
+	}
+	return nil
+}
@@ -7,6 +7,8 @@ import (
 
 func subTestProto(g *testGroup) {
 	g.regSubTest("HTTP CORS", proto_HTTP_CORS_test)
+	g.regSubTest("RESP3", proto_RESP3_test)
+	g.regSubTest("RESP3 push", proto_RESP3_push_test)
 }
 
 func proto_HTTP_CORS_test(mc *mockServer) error {
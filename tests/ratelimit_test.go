@@ -0,0 +1,181 @@
+package tests
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/tidwall/gjson"
+)
+
+func subTestRateLimit(g *testGroup) {
+	g.regSubTest("limits", ratelimit_limits_test)
+	g.regSubTest("user", ratelimit_user_test)
+	g.regSubTest("quotas", ratelimit_quotas_test)
+}
+
+// rateLimited expects a RATELIMITED error.
+func rateLimited(category string) func(s string) error {
+	return func(s string) error {
+		prefix := "RATELIMITED too many '" + category + "' commands, retry in "
+		if !strings.HasPrefix(s, prefix) &&
+			!strings.HasPrefix(gjson.Get(s, "err").String(), prefix) {
+			return fmt.Errorf("expected '%s...', got '%s'", prefix, s)
+		}
+		return nil
+	}
+}
+
+func ratelimit_limits_test(mc *mockServer) error {
+	return mc.DoBatch(
+		Do("RATELIMIT", "SET", "host", "*", "write", "1").Err(
+			"invalid argument 'host'"),
+		Do("RATELIMIT", "SET", "ip", "*", "writes", "1").Err(
+			"invalid argument 'writes'"),
+		Do("RATELIMIT", "SET", "ip", "*", "write", "0").Err(
+			"invalid argument '0'"),
+		Do("RATELIMIT", "SET", "ip", "*", "write").Err(
+			"wrong number of arguments for 'ratelimit' command"),
+		Do("RATELIMIT", "SET", "ip", "*", "write", "1", "2").OK(),
+		Do("RATELIMIT", "LIST").Str("[ip * write 1 2]"),
+		Do("RATELIMIT", "LIST").JSON().Func(func(s string) error {
+			if gjson.Get(s, "rules").String() != `["ip * write 1 2"]` {
+				return fmt.Errorf("unexpected list '%s'", s)
+			}
+			return nil
+		}),
+		Do("SET", "fleet", "truck1", "POINT", 33, -115).OK(),
+		Do("SET", "fleet", "truck2", "POINT", 33, -115).OK(),
+		Do("SET", "fleet", "truck3", "POINT", 33, -115).Func(
+			rateLimited("write")),
+		// the other categories aren't limited
+		Do("GET", "fleet", "truck1", "POINT").Str("[33 -115]"),
+		Do("SCAN", "fleet", "COUNT").Str("2"),
+		Do("SLEEP", "1").OK(),
+		Do("SET", "fleet", "truck3", "POINT", 33, -115).OK(),
+		Do("RATELIMIT", "DEL", "ip", "*", "write").Str("1"),
+		Do("RATELIMIT", "DEL", "ip", "*", "write").Str("0"),
+		Do("SET", "fleet", "truck4", "POINT", 33, -115).OK(),
+		Do("SET", "fleet", "truck5", "POINT", 33, -115).OK(),
+		Do("SET", "fleet", "truck6", "POINT", 33, -115).OK(),
+		Do("RATELIMIT", "LIST").Str("[]"),
+	)
+}
+
+func ratelimit_user_test(mc *mockServer) error {
+	if err := mc.DoBatch(
+		Do("ACL", "SETUSER", "ingest", "on", ">secret", "allkeys",
+			"+@all").OK(),
+		Do("RATELIMIT", "SET", "user", "*", "all", "1").OK(),
+		Do("RATELIMIT", "SET", "user", "default", "all", "1000").OK(),
+		Do("RATELIMIT", "SET", "user", "ingest", "write", "1", "1").OK(),
+	); err != nil {
+		return err
+	}
+	conn, err := redis.Dial("tcp", fmt.Sprintf(":%d", mc.port))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if err := aclExpect(conn, "OK", "AUTH", "ingest", "secret"); err != nil {
+		return err
+	}
+	if err := aclExpect(conn, "OK", "SET", "fleet", "truck1", "POINT", "33",
+		"-115"); err != nil {
+		return err
+	}
+	_, err = conn.Do("SET", "fleet", "truck2", "POINT", "33", "-115")
+	if err == nil || rateLimited("write")(err.Error()) != nil {
+		return fmt.Errorf("expected RATELIMITED, got %v", err)
+	}
+	// the 'all' rule of the user still applies
+	_, err = conn.Do("GET", "fleet", "truck1")
+	if err == nil || rateLimited("all")(err.Error()) != nil {
+		return fmt.Errorf("expected RATELIMITED, got %v", err)
+	}
+	// the default user has its own rule
+	for i := 0; i < 10; i++ {
+		if err := mc.DoBatch(
+			Do("SET", "fleet", fmt.Sprintf("truck%d", i), "POINT", 33, -115).OK(),
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func ratelimit_quotas_test(mc *mockServer) error {
+	if err := mc.DoBatch(
+		Do("CONFIG", "SET", "collection-maxobjects", "x").Err(
+			"Invalid argument 'x' for CONFIG SET 'collection-maxobjects'"),
+		Do("CONFIG", "SET", "collection-maxobjects", "2").OK(),
+		Do("CONFIG", "SET", "user-maxhooks", "1").OK(),
+		Do("SET", "fleet", "truck1", "POINT", 33, -115).OK(),
+		Do("SET", "fleet", "truck2", "POINT", 33, -115).OK(),
+		Do("SET", "fleet", "truck3", "POINT", 33, -115).Err(
+			"QUOTA collection 'fleet' reached the max of 2 objects"),
+		Do("JSET", "fleet", "truck3", "name", "Truck").Err(
+			"QUOTA collection 'fleet' reached the max of 2 objects"),
+		Do("SET", "fleet", "truck1", "POINT", 34, -116).OK(),
+		Do("SET", "other", "truck3", "POINT", 33, -115).OK(),
+		Do("ACL", "SETUSER", "tenant1", "on", "nopass", "allkeys",
+			"+@all").OK(),
+	); err != nil {
+		return err
+	}
+	conn, err := redis.Dial("tcp", fmt.Sprintf(":%d", mc.port))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	for _, cmd := range [][]any{
+		{"OK", "AUTH", "tenant1", "any"},
+		{"1", "SETHOOK", "hook1", "http://localhost:4892", "NEARBY", "fleet",
+			"FENCE", "POINT", "33", "-115", "100"},
+		{"QUOTA user 'tenant1' reached the max of 1 hooks", "SETCHAN",
+			"chan1", "NEARBY", "fleet", "FENCE", "POINT", "33", "-115", "100"},
+		{"1", "SETHOOK", "hook1", "http://localhost:4892", "NEARBY", "fleet",
+			"FENCE", "POINT", "33", "-115", "200"},
+		{"OWNER is only allowed for the default user", "SETHOOK", "hook2",
+			"http://localhost:4892", "OWNER", "tenant2", "NEARBY", "fleet",
+			"FENCE", "POINT", "33", "-115", "100"},
+	} {
+		if err := aclExpect(conn, cmd[0].(string), cmd[1:]...); err != nil {
+			return err
+		}
+	}
+	if err := mc.DoBatch(
+		Do("SETHOOK", "hook2", "http://localhost:4892", "OWNER", "tenant1",
+			"NEARBY", "fleet", "FENCE", "POINT", 33, -115, 100).Err(
+			"QUOTA user 'tenant1' reached the max of 1 hooks"),
+		Do("SETHOOK", "hook2", "http://localhost:4892", "NEARBY", "fleet",
+			"FENCE", "POINT", 33, -115, 100).Str("1"),
+		// the hooks without an owner count for the default user
+		Do("SETCHAN", "chan2", "NEARBY", "fleet", "FENCE", "POINT",
+			33, -115, 100).Err("QUOTA user 'default' reached the max of 1 hooks"),
+		Do("SETHOOK", "hook2", "http://localhost:4892", "NEARBY", "fleet",
+			"FENCE", "POINT", 33, -115, 200).Str("1"),
+		Do("HOOKS", "*").JSON().Func(func(s string) error {
+			owners := gjson.Get(s, "hooks.#.owner").String()
+			if owners != `["tenant1"]` {
+				return fmt.Errorf("expected one owner, got '%s'", s)
+			}
+			return nil
+		}),
+	); err != nil {
+		return err
+	}
+	// the owner is kept in the aof
+	time.Sleep(time.Millisecond * 100)
+	aof, err := mc.readAOF()
+	if err != nil {
+		return err
+	}
+	if !bytes.Contains(aof, []byte("$5\r\nowner\r\n$7\r\ntenant1\r\n")) {
+		return errors.New("expected the owner in the aof")
+	}
+	return nil
+}
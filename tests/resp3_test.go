@@ -0,0 +1,244 @@
+package tests
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/redcon"
+)
+
+// resp3Conn is a RESP3 connection. The values are read as strings in a
+// compact form, that keeps the types: 'OK', ':1', ',1.5', '_', '#t',
+// '[a b]', '{k:v}', '>[a b]', and bulk strings as is.
+type resp3Conn struct {
+	conn net.Conn
+	rd   *bufio.Reader
+}
+
+func resp3Dial(mc *mockServer) (*resp3Conn, error) {
+	conn, err := net.Dial("tcp", fmt.Sprintf("127.0.0.1:%d", mc.port))
+	if err != nil {
+		return nil, err
+	}
+	return &resp3Conn{conn: conn, rd: bufio.NewReader(conn)}, nil
+}
+
+func (c *resp3Conn) Close() {
+	c.conn.Close()
+}
+
+// Do sends a command and reads the next value.
+func (c *resp3Conn) Do(args ...string) (string, error) {
+	var cmd []byte
+	cmd = redcon.AppendArray(cmd, len(args))
+	for _, arg := range args {
+		cmd = redcon.AppendBulkString(cmd, arg)
+	}
+	if _, err := c.conn.Write(cmd); err != nil {
+		return "", err
+	}
+	return c.Read()
+}
+
+// Read reads the next value.
+func (c *resp3Conn) Read() (string, error) {
+	c.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	return c.read()
+}
+
+func (c *resp3Conn) read() (string, error) {
+	line, err := c.rd.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return "", errors.New("invalid value")
+	}
+	typ, rest := line[0], line[1:]
+	switch typ {
+	case '+':
+		return rest, nil
+	case '-':
+		return "", errors.New(rest)
+	case ':', ',', '#':
+		return line, nil
+	case '_':
+		return "_", nil
+	case '$':
+		n, err := strconv.Atoi(rest)
+		if err != nil {
+			return "", err
+		}
+		data := make([]byte, n+2)
+		if _, err := io.ReadFull(c.rd, data); err != nil {
+			return "", err
+		}
+		return string(data[:n]), nil
+	case '*', '>', '%':
+		n, err := strconv.Atoi(rest)
+		if err != nil {
+			return "", err
+		}
+		if typ == '%' {
+			n *= 2
+		}
+		vals := make([]string, n)
+		for i := range vals {
+			if vals[i], err = c.read(); err != nil {
+				return "", err
+			}
+		}
+		if typ == '%' {
+			var pairs []string
+			for i := 0; i < n; i += 2 {
+				pairs = append(pairs, vals[i]+":"+vals[i+1])
+			}
+			return "{" + strings.Join(pairs, " ") + "}", nil
+		}
+		s := "[" + strings.Join(vals, " ") + "]"
+		if typ == '>' {
+			s = ">" + s
+		}
+		return s, nil
+	}
+	return "", fmt.Errorf("unknown type '%c'", typ)
+}
+
+// resp3Expect runs commands on a RESP3 connection, comparing the replies.
+func resp3Expect(c *resp3Conn, cmds ...[]string) error {
+	for _, cmd := range cmds {
+		args, expect := cmd[:len(cmd)-1], cmd[len(cmd)-1]
+		res, err := c.Do(args...)
+		if err != nil {
+			res = "ERR " + err.Error()
+		}
+		if res != expect {
+			return fmt.Errorf("%s: expected '%s', got '%s'",
+				strings.Join(args, " "), expect, res)
+		}
+	}
+	return nil
+}
+
+func proto_RESP3_test(mc *mockServer) error {
+	c, err := resp3Dial(mc)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	res, err := c.Do("HELLO", "3", "SETNAME", "resp3client")
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(res, "{server:meridian ") ||
+		!strings.Contains(res, " proto::3 ") ||
+		!strings.HasSuffix(res, " modules:[]}") {
+		return fmt.Errorf("unexpected HELLO reply '%s'", res)
+	}
+	if err := resp3Expect(c,
+		[]string{"HELLO", "4", "ERR NOPROTO unsupported protocol version"},
+		[]string{"SET", "fleet", "truck1", "FIELD", "speed", "55",
+			"POINT", "33.5", "-115.25", "OK"},
+		[]string{"SET", "fleet", "truck2", "POINT", "34", "-116", "OK"},
+		[]string{"GET", "fleet", "truck1", "POINT", "[,33.5 ,-115.25]"},
+		[]string{"GET", "fleet", "truck1", "WITHFIELDS", "POINT",
+			"[[,33.5 ,-115.25] {speed:,55}]"},
+		[]string{"GET", "fleet", "truck3", "_"},
+		[]string{"SCAN", "fleet", "POINTS",
+			"[:0 [[truck1 [,33.5 ,-115.25] {speed:,55}] [truck2 [,34 ,-116]]]]"},
+		[]string{"NEARBY", "fleet", "LIMIT", "1", "DISTANCE", "IDS",
+			"POINT", "33.5", "-115.25", "[:1 [[truck1 ,0]]]"},
+		[]string{"NEARBY", "fleet", "COUNT", "POINT", "33.5", "-115.25",
+			":2"},
+		[]string{"CLIENT", "GETNAME", "resp3client"},
+	); err != nil {
+		return err
+	}
+	for cmd, expect := range map[string]string{
+		"SERVER": " num_points::2 ",
+		"INFO":   " connected_clients::",
+	} {
+		res, err := c.Do(cmd)
+		if err != nil {
+			return err
+		}
+		if !strings.HasPrefix(res, "{") || !strings.Contains(res, expect) {
+			return fmt.Errorf("unexpected %s reply '%s'", cmd, res)
+		}
+	}
+	res, err = c.Do("STATS", "fleet", "none")
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(res, "[{") || !strings.HasSuffix(res, "} _]") ||
+		!strings.Contains(res, "num_objects::2") {
+		return fmt.Errorf("unexpected STATS reply '%s'", res)
+	}
+	return nil
+}
+
+func proto_RESP3_push_test(mc *mockServer) error {
+	c, err := resp3Dial(mc)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	if _, err := c.Do("HELLO", "3"); err != nil {
+		return err
+	}
+	if err := resp3Expect(c,
+		[]string{"SUBSCRIBE", "news", "alerts", ">[subscribe news :1]"},
+	); err != nil {
+		return err
+	}
+	if res, err := c.Read(); err != nil || res != ">[subscribe alerts :2]" {
+		return fmt.Errorf("unexpected subscribe reply '%s' %v", res, err)
+	}
+	if err := resp3Expect(c,
+		[]string{"NEARBY", "fleet", "FENCE", "POINT", "33", "-115", "10000",
+			"OK"},
+		[]string{"PING", "PONG"},
+	); err != nil {
+		return err
+	}
+	if err := mc.DoBatch(
+		Do("PUBLISH", "news", "hello").Str("1"),
+		Do("SET", "fleet", "truck1", "POINT", 33, -115).OK(),
+	); err != nil {
+		return err
+	}
+	// the message, and the enter and inside fence events, in any order
+	var sawMessage bool
+	var detects []string
+	for !sawMessage || len(detects) < 2 {
+		res, err := c.Read()
+		if err != nil {
+			return err
+		}
+		switch {
+		case res == ">[message news hello]":
+			sawMessage = true
+		case strings.HasPrefix(res, ">[fence {") &&
+			strings.Contains(res, `"id":"truck1"`):
+			detects = append(detects,
+				gjson.Get(res[len(">[fence "):len(res)-1], "detect").String())
+		default:
+			return fmt.Errorf("unexpected push '%s'", res)
+		}
+	}
+	if strings.Join(detects, ",") != "enter,inside" {
+		return fmt.Errorf("unexpected fence events %v", detects)
+	}
+	return resp3Expect(c,
+		[]string{"UNSUBSCRIBE", "news", ">[unsubscribe news :1]"},
+		[]string{"GET", "fleet", "truck1", "POINT", "[,33 ,-115]"},
+	)
+}
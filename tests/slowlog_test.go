@@ -0,0 +1,119 @@
+package tests
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+func subTestSlowLog(g *testGroup) {
+	g.regSubTest("log", slowlog_log_test)
+	g.regSubTest("config", slowlog_config_test)
+}
+
+func slowlog_log_test(mc *mockServer) error {
+	// entry returns the newest entry of a command.
+	entry := func(s, cmd string) gjson.Result {
+		for _, e := range gjson.Get(s, "entries").Array() {
+			if e.Get("args.0").String() == cmd {
+				return e
+			}
+		}
+		return gjson.Result{}
+	}
+	return mc.DoBatch(
+		Do("SET", "fleet", "truck1", "FIELD", "speed", 10, "POINT", 33, -115).OK(),
+		Do("SET", "fleet", "truck2", "FIELD", "speed", 50, "POINT", 33.01, -115.01).OK(),
+		Do("SET", "fleet", "truck3", "FIELD", "speed", 90, "POINT", 33.02, -115.02).OK(),
+		Do("SLOWLOG", "LEN").Str("0"),
+		Do("CONFIG", "SET", "slowlog-log-slower-than", "0").OK(),
+		Do("CONFIG", "SET", "requirepass", "secret").OK(),
+		Do("AUTH", "secret").OK(),
+		Do("CONFIG", "SET", "requirepass", "").OK(),
+		Do("INTERSECTS", "fleet", "WHERE", "speed", 40, "+inf", "COUNT",
+			"BOUNDS", 32, -116, 34, -114).Str("2"),
+		Do("SET", "fleet", strings.Repeat("x", 200), "POINT", 33, -115).OK(),
+		Do("SLOWLOG", "LEN").Str("5"),
+		Do("SLOWLOG", "GET", 2).Func(func(s string) error {
+			if !strings.HasPrefix(s, "[[5 ") ||
+				!strings.Contains(s, " [SET fleet "+strings.Repeat("x", 128)+
+					"... (72 more bytes) POINT 33 -115] ") {
+				return fmt.Errorf("unexpected entries '%s'", s)
+			}
+			return nil
+		}),
+		Do("SLOWLOG", "GET", -1).JSON().Func(func(s string) error {
+			e := entry(s, "INTERSECTS")
+			if e.Get("args").String() != `["INTERSECTS","fleet","WHERE","speed","40","+inf","COUNT","BOUNDS","32","-116","34","-114"]` {
+				return fmt.Errorf("unexpected entry '%s'", e)
+			}
+			if e.Get("iters").Int() != 3 || e.Get("items").Int() != 2 {
+				return fmt.Errorf("expected 3 iters and 2 items, got '%s'", e)
+			}
+			if e.Get("addr").String() == "" {
+				return errors.New("expected the client address")
+			}
+			// the secrets are redacted and AUTH isn't recorded
+			if entry(s, "AUTH").Exists() {
+				return errors.New("expected no AUTH entry")
+			}
+			for _, e := range gjson.Get(s, "entries").Array() {
+				args := e.Get("args").String()
+				if strings.Contains(args, "requirepass") &&
+					args != `["CONFIG","SET","requirepass","********"]` {
+					return fmt.Errorf("unexpected args '%s'", args)
+				}
+			}
+			return nil
+		}),
+		Do("SLOWLOG", "RESET").OK(),
+		Do("SLOWLOG", "LEN").Str("1"),
+		Do("SLOWLOG", "FOO").Err("invalid argument 'FOO'"),
+		Do("SLOWLOG", "GET", "x").Err("invalid argument 'x'"),
+	)
+}
+
+func slowlog_config_test(mc *mockServer) error {
+	if err := mc.DoBatch(
+		Do("CONFIG", "GET", "slowlog*").JSON().Func(func(s string) error {
+			props := gjson.Get(s, "properties").String()
+			expect := `{"slowlog-log-slower-than":"10000","slowlog-max-len":"128"}`
+			if props != expect {
+				return fmt.Errorf("expected '%s', got '%s'", expect, props)
+			}
+			return nil
+		}),
+		Do("CONFIG", "SET", "slowlog-max-len", "x").Err(
+			"Invalid argument 'x' for CONFIG SET 'slowlog-max-len'"),
+		Do("CONFIG", "SET", "slowlog-max-len", "3").OK(),
+		Do("CONFIG", "SET", "slowlog-log-slower-than", "0").OK(),
+	); err != nil {
+		return err
+	}
+	for i := 0; i < 10; i++ {
+		if err := mc.DoBatch(
+			Do("SET", "fleet", fmt.Sprintf("truck%d", i), "POINT", 33, -115).OK(),
+		); err != nil {
+			return err
+		}
+	}
+	return mc.DoBatch(
+		Do("SLOWLOG", "LEN").Str("3"),
+		Do("SLOWLOG", "GET", -1).Func(func(s string) error {
+			if !strings.HasPrefix(s, "[[11 1") ||
+				!strings.Contains(s, " [SLOWLOG LEN] ") ||
+				!strings.Contains(s, " [SET fleet truck9 POINT 33 -115] ") ||
+				strings.Contains(s, "truck7") {
+				return fmt.Errorf("unexpected entries '%s'", s)
+			}
+			return nil
+		}),
+		// negative disables the slow log
+		Do("CONFIG", "SET", "slowlog-log-slower-than", "-1").OK(),
+		Do("SLOWLOG", "RESET").OK(),
+		Do("SET", "fleet", "truck1", "POINT", 33, -115).OK(),
+		Do("SLOWLOG", "LEN").Str("0"),
+	)
+}
@@ -0,0 +1,187 @@
+package tests
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/tidwall/gjson"
+)
+
+func subTestSSE(g *testGroup) {
+	g.regSubTest("fence", sse_fence_test)
+	g.regSubTest("subscribe", sse_subscribe_test)
+	g.regSubTest("unsupported", sse_unsupported_test)
+}
+
+type sseConn struct {
+	resp *http.Response
+	rd   *bufio.Reader
+}
+
+// sseOpen opens an event stream for a live command.
+func sseOpen(mc *mockServer, path, lastEventID string) (*sseConn, error) {
+	req, err := http.NewRequest("GET",
+		fmt.Sprintf("http://127.0.0.1:%d/%s", mc.port, path), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != 200 ||
+		resp.Header.Get("Content-Type") != "text/event-stream" {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected response %d %s", resp.StatusCode,
+			resp.Header.Get("Content-Type"))
+	}
+	return &sseConn{resp: resp, rd: bufio.NewReader(resp.Body)}, nil
+}
+
+func (c *sseConn) Close() {
+	c.resp.Body.Close()
+}
+
+// next reads the next event, skipping comments.
+func (c *sseConn) next() (id, data string, err error) {
+	type result struct {
+		id, data string
+		err      error
+	}
+	ch := make(chan result, 1)
+	go func() {
+		var r result
+		for {
+			line, err := c.rd.ReadString('\n')
+			if err != nil {
+				r.err = err
+				break
+			}
+			line = strings.TrimSuffix(line, "\n")
+			if line == "" {
+				if r.id != "" {
+					break
+				}
+				continue
+			}
+			if strings.HasPrefix(line, "id: ") {
+				r.id = line[4:]
+			} else if strings.HasPrefix(line, "data: ") {
+				r.data += line[6:]
+			}
+		}
+		ch <- r
+	}()
+	select {
+	case r := <-ch:
+		return r.id, r.data, r.err
+	case <-time.After(5 * time.Second):
+		return "", "", errors.New("timeout waiting for event")
+	}
+}
+
+func sse_fence_test(mc *mockServer) error {
+	const path = "NEARBY+fleet+FENCE+POINT+33+-115+10000"
+	c, err := sseOpen(mc, path, "")
+	if err != nil {
+		return err
+	}
+	if err := mc.DoBatch(
+		Do("SET", "fleet", "truck1", "POINT", 33, -115).OK(),
+	); err != nil {
+		c.Close()
+		return err
+	}
+	id, data, err := c.next()
+	c.Close()
+	if err != nil {
+		return err
+	}
+	if gjson.Get(data, "id").String() != "truck1" ||
+		gjson.Get(data, "detect").String() != "enter" {
+		return fmt.Errorf("unexpected event %s", data)
+	}
+
+	// messages sent while disconnected are replayed on resume
+	if err := mc.DoBatch(
+		Do("SET", "fleet", "truck2", "POINT", 33.01, -115.01).OK(),
+	); err != nil {
+		return err
+	}
+	time.Sleep(time.Second / 4)
+	c, err = sseOpen(mc, path, id)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	// the replay starts after the last seen event, which was followed by
+	// the "inside" event of truck1
+	for _, expect := range []string{"truck1", "truck2"} {
+		var data string
+		id, data, err = c.next()
+		if err != nil {
+			return err
+		}
+		if gjson.Get(data, "id").String() != expect {
+			return fmt.Errorf("expected %s event, got %s", expect, data)
+		}
+	}
+	return nil
+}
+
+func sse_subscribe_test(mc *mockServer) error {
+	c, err := sseOpen(mc, "SUBSCRIBE+news", "")
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	if err := mc.DoBatch(
+		Do("PUBLISH", "news", "hello").Str("1"),
+		Do("PUBLISH", "news", `{"a":1}`).Str("1"),
+	); err != nil {
+		return err
+	}
+	for _, expect := range []string{`"hello"`, `{"a":1}`} {
+		_, data, err := c.next()
+		if err != nil {
+			return err
+		}
+		if data != expect {
+			return fmt.Errorf("expected %s, got %s", expect, data)
+		}
+	}
+	return nil
+}
+
+func sse_unsupported_test(mc *mockServer) error {
+	req, err := http.NewRequest("GET",
+		fmt.Sprintf("http://127.0.0.1:%d/MONITOR", mc.port), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != 400 || gjson.GetBytes(body, "err").String() !=
+		"event stream is not supported for monitor" {
+		return fmt.Errorf("expected status 400, got %d with '%s'",
+			resp.StatusCode, body)
+	}
+	return nil
+}
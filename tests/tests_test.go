@@ -58,6 +58,16 @@ func TestIntegration(t *testing.T) {
 	regTestGroup("aof", subTestAOF)
 	regTestGroup("monitor", subTestMonitor)
 	regTestGroup("proto", subTestProto)
+	regTestGroup("hooks", subTestHooks)
+	regTestGroup("grpc", subTestGRPC)
+	regTestGroup("ogc", subTestOGC)
+	regTestGroup("tiles", subTestTiles)
+	regTestGroup("sse", subTestSSE)
+	regTestGroup("acl", subTestACL)
+	regTestGroup("audit", subTestAudit)
+	regTestGroup("ratelimit", subTestRateLimit)
+	regTestGroup("slowlog", subTestSlowLog)
+	regTestGroup("keyspace", subTestKeyspace)
 	runTestGroups(t)
 }
 
@@ -229,6 +239,7 @@ func (s *testGroupSub) run() (err error) {
 		mc, err := mockOpenServer(MockServerOptions{
 			Silent:  true,
 			Metrics: true,
+			GRPC:    true,
 		})
 		if err != nil {
 			return err
@@ -0,0 +1,135 @@
+package tests
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+func subTestTiles(g *testGroup) {
+	g.regSubTest("layers", tiles_layers_test)
+	g.regSubTest("cache", tiles_cache_test)
+}
+
+// tileGet requests a tile and returns the body and the X-Cache header.
+func tileGet(mc *mockServer, path string, expectStatus int) (string, string, error) {
+	resp, err := http.Get(fmt.Sprintf("http://127.0.0.1:%d/tiles/%s", mc.port, path))
+	if err != nil {
+		return "", "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", "", err
+	}
+	if resp.StatusCode != expectStatus {
+		return "", "", fmt.Errorf("expected status %d, got %d: %s",
+			expectStatus, resp.StatusCode, body)
+	}
+	return string(body), resp.Header.Get("X-Cache"), nil
+}
+
+func tiles_layers_test(mc *mockServer) error {
+	if err := mc.DoBatch(
+		Do("SET", "fleet", "truck1", "FIELD", "speed", 90, "POINT", 33, -115).OK(),
+		Do("SET", "fleet", "truck2", "FIELD", "speed", 10, "POINT", 33.01, -115.01).OK(),
+		Do("SET", "zones", "z1", "BOUNDS", 32.9, -115.1, 33.1, -114.9).OK(),
+	); err != nil {
+		return err
+	}
+	body, _, err := tileGet(mc, "fleet,zones/10/184/412.mvt", 200)
+	if err != nil {
+		return err
+	}
+	for _, s := range []string{"fleet", "zones", "truck1", "truck2", "z1"} {
+		if !strings.Contains(body, s) {
+			return fmt.Errorf("expected '%s' in tile", s)
+		}
+	}
+	if strings.Contains(body, "speed") {
+		return fmt.Errorf("unexpected speed tag in tile")
+	}
+	body, _, err = tileGet(mc,
+		"fleet/10/184/412.mvt?fleet.where=speed+>+50&fleet.fields=speed", 200)
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(body, "truck1") || strings.Contains(body, "truck2") ||
+		!strings.Contains(body, "speed") {
+		return fmt.Errorf("unexpected filtered tile")
+	}
+	body, _, err = tileGet(mc, "fleet/10/184/412.mvt?fleet.cluster=40", 200)
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(body, "point_count") || strings.Contains(body, "truck1") {
+		return fmt.Errorf("expected a cluster in tile")
+	}
+	if _, _, err := tileGet(mc, "fleet/zz/184/412.mvt", 400); err != nil {
+		return err
+	}
+	_, _, err = tileGet(mc, "fleet/10/184.mvt", 404)
+	return err
+}
+
+func tiles_cache_test(mc *mockServer) error {
+	if err := mc.DoBatch(
+		Do("SET", "fleet", "truck1", "POINT", 33, -115).OK(),
+	); err != nil {
+		return err
+	}
+	const path = "fleet/10/184/412.mvt"
+	expect := func(cache string) error {
+		_, v, err := tileGet(mc, path, 200)
+		if err != nil {
+			return err
+		}
+		if v != cache {
+			return fmt.Errorf("expected X-Cache %s, got %s", cache, v)
+		}
+		return nil
+	}
+	if err := expect("MISS"); err != nil {
+		return err
+	}
+	if err := expect("HIT"); err != nil {
+		return err
+	}
+	// writes outside of the tile, or to other collections, keep the tile
+	if err := mc.DoBatch(
+		Do("SET", "fleet", "truck2", "POINT", 0, 0).OK(),
+		Do("SET", "zones", "z1", "POINT", 33, -115).OK(),
+	); err != nil {
+		return err
+	}
+	if err := expect("HIT"); err != nil {
+		return err
+	}
+	// moving an object out of the tile removes it
+	if err := mc.DoBatch(
+		Do("SET", "fleet", "truck1", "POINT", 0, 1).OK(),
+	); err != nil {
+		return err
+	}
+	if err := expect("MISS"); err != nil {
+		return err
+	}
+	if err := expect("HIT"); err != nil {
+		return err
+	}
+	if err := mc.DoBatch(
+		Do("DROP", "fleet").Str("1"),
+	); err != nil {
+		return err
+	}
+	if err := expect("MISS"); err != nil {
+		return err
+	}
+	if err := mc.DoBatch(
+		Do("CONFIG", "SET", "tilecache-size", 0).OK(),
+	); err != nil {
+		return err
+	}
+	return expect("MISS")
+}